@@ -0,0 +1,62 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.23
+
+package manager
+
+import (
+	"net/url"
+	"slices"
+	"sort"
+
+	"github.com/docker/cli/cli-plugins/metadata"
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/cli/connhelper"
+)
+
+// GetConnectionHelper returns a connection helper for the given daemon URL,
+// which executes the plugin that declares support for the scheme of the URL
+// (for example, "k8s-pod" for "k8s-pod://namespace/pod") in its metadata. It
+// returns nil without error when no plugin supports the scheme.
+//
+// The plugin is executed with the [metadata.ConnectSubcommandName] subcommand
+// and the daemon URL as arguments.
+func GetConnectionHelper(cfg *configfile.ConfigFile, daemonURL string) (*connhelper.ConnectionHelper, error) {
+	u, err := url.Parse(daemonURL)
+	if err != nil {
+		return nil, err
+	}
+	p, err := getConnectionPlugin(getPluginDirs(cfg), u.Scheme)
+	if err != nil || p == nil {
+		return nil, err
+	}
+	return connhelper.GetCommandConnectionHelper(p.Path, p.Name, metadata.ConnectSubcommandName, daemonURL)
+}
+
+// getConnectionPlugin returns the first plugin, in alphabetical order, that
+// declares support for the given connection scheme in its metadata.
+func getConnectionPlugin(pluginDirs []string, scheme string) (*Plugin, error) {
+	candidates := listPluginCandidates(pluginDirs)
+	names := make([]string, 0, len(candidates))
+	for name := range candidates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		paths := candidates[name]
+		if len(paths) == 0 {
+			continue
+		}
+		p, err := newPlugin(&candidate{paths[0]}, nil)
+		if err != nil {
+			return nil, err
+		}
+		if p.Err != nil {
+			continue
+		}
+		if slices.Contains(p.Metadata.ConnectionSchemes, scheme) {
+			return &p, nil
+		}
+	}
+	return nil, nil
+}
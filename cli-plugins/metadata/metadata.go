@@ -14,6 +14,13 @@
 	// for hooks in their metadata.
 	HookSubcommandName = "docker-cli-plugin-hooks"
 
+	// ConnectSubcommandName is the name of the plugin subcommand
+	// which must be implemented by plugins declaring support for
+	// connection schemes in their metadata. It is called with the
+	// URL of the daemon host, and must connect its stdin and stdout
+	// to the daemon, in the same way as "docker system dial-stdio".
+	ConnectSubcommandName = "docker-cli-plugin-connect"
+
 	// ReexecEnvvar is the name of an ennvar which is set to the command
 	// used to originally invoke the docker CLI when executing a
 	// plugin. Assuming $PATH and $CWD remain unchanged this should allow
@@ -33,4 +40,8 @@ type Metadata struct {
 	ShortDescription string `json:",omitempty"`
 	// URL is a pointer to the plugin's homepage.
 	URL string `json:",omitempty"`
+	// ConnectionSchemes are the schemes of daemon host URLs (for example,
+	// "k8s-pod" for "k8s-pod://namespace/pod") that the plugin can connect
+	// to through its ConnectSubcommandName subcommand.
+	ConnectionSchemes []string `json:",omitempty"`
 }
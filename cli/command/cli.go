@@ -8,6 +8,7 @@
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
 	"runtime"
 	"strconv"
@@ -16,6 +17,7 @@
 
 	"github.com/docker/cli/cli/config"
 	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/cli/config/credentials"
 	dcontext "github.com/docker/cli/cli/context"
 	"github.com/docker/cli/cli/context/docker"
 	"github.com/docker/cli/cli/context/store"
@@ -68,12 +70,14 @@ type DockerCli struct {
 	serverInfo         ServerInfo
 	contextStore       store.Store
 	currentContext     string
+	contextSource      string
 	init               sync.Once
 	initErr            error
 	dockerEndpoint     docker.Endpoint
 	contextStoreConfig *store.Config
 	initTimeout        time.Duration
 	userAgent          string
+	connectionPlugins  connectionPluginFunc
 	res                telemetryResource
 
 	// baseCtx is the base context used for internal operations. In the future
@@ -250,9 +254,9 @@ func (cli *DockerCli) Initialize(opts *cliflags.ClientOptions, ops ...CLIOption)
 
 	cli.options = opts
 	cli.configFile = config.LoadDefaultConfigFile(cli.err)
-	cli.currentContext = resolveContextName(cli.options, cli.configFile)
+	cli.currentContext, cli.contextSource = resolveContextName(cli.options, cli.configFile)
 	cli.contextStore = &ContextStoreWithDefault{
-		Store: store.New(config.ContextStoreDir(), *cli.contextStoreConfig),
+		Store: store.New(config.ContextStoreDir(), withContextSecretStore(*cli.contextStoreConfig, cli.configFile)),
 		Resolver: func() (*DefaultContext, error) {
 			return resolveDefaultContext(cli.options, *cli.contextStoreConfig)
 		},
@@ -289,23 +293,54 @@ func NewAPIClientFromFlags(opts *cliflags.ClientOptions, configFile *configfile.
 
 	storeConfig := DefaultContextStoreConfig()
 	contextStore := &ContextStoreWithDefault{
-		Store: store.New(config.ContextStoreDir(), storeConfig),
+		Store: store.New(config.ContextStoreDir(), withContextSecretStore(storeConfig, configFile)),
 		Resolver: func() (*DefaultContext, error) {
 			return resolveDefaultContext(opts, storeConfig)
 		},
 	}
-	endpoint, err := resolveDockerEndpoint(contextStore, resolveContextName(opts, configFile))
+	contextName, _ := resolveContextName(opts, configFile)
+	endpoint, err := resolveDockerEndpoint(contextStore, contextName)
 	if err != nil {
 		return nil, fmt.Errorf("unable to resolve docker endpoint: %w", err)
 	}
-	return newAPIClientFromEndpoint(endpoint, configFile, client.WithUserAgent(UserAgent()))
+	return newAPIClientFromEndpoint(endpoint, configFile, nil, client.WithUserAgent(UserAgent()))
 }
 
-func newAPIClientFromEndpoint(ep docker.Endpoint, configFile *configfile.ConfigFile, extraOpts ...client.Opt) (client.APIClient, error) {
+// NewAPIClientForContext creates a new APIClient for the Docker endpoint of
+// the given context, which does not have to be the current context.
+func NewAPIClientForContext(dockerCLI Cli, contextName string) (client.APIClient, error) {
+	endpoint, err := resolveDockerEndpoint(dockerCLI.ContextStore(), contextName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve docker endpoint: %w", err)
+	}
+	var getPluginHelper connectionPluginFunc
+	if cli, ok := dockerCLI.(*DockerCli); ok {
+		getPluginHelper = cli.connectionPlugins
+	}
+	return newAPIClientFromEndpoint(endpoint, dockerCLI.ConfigFile(), getPluginHelper, client.WithUserAgent(UserAgent()))
+}
+
+func newAPIClientFromEndpoint(ep docker.Endpoint, configFile *configfile.ConfigFile, getPluginHelper connectionPluginFunc, extraOpts ...client.Opt) (client.APIClient, error) {
 	opts, err := ep.ClientOpts()
 	if err != nil {
 		return nil, err
 	}
+	helper, err := getCustomConnectionHelper(ep.Host, configFile, getPluginHelper)
+	if err != nil {
+		return nil, err
+	}
+	if helper != nil {
+		opts = append(opts,
+			client.WithHTTPClient(&http.Client{
+				// No TLS, and no proxy.
+				Transport: &http.Transport{
+					DialContext: helper.Dialer,
+				},
+			}),
+			client.WithHost(helper.Host),
+			client.WithDialContext(helper.Dialer),
+		)
+	}
 	if len(configFile.HTTPHeaders) > 0 {
 		opts = append(opts, client.WithHTTPHeaders(configFile.HTTPHeaders))
 	}
@@ -409,9 +444,11 @@ func (cli *DockerCli) ContextStore() store.Store {
 //
 //  1. The "--context" command-line option.
 //  2. The "DOCKER_CONTEXT" environment variable ([EnvOverrideContext]).
-//  3. The current context as configured through the in "currentContext"
+//  3. The context set for the project through a [ContextFileName] file in
+//     the current directory or one of its parent directories.
+//  4. The current context as configured through the in "currentContext"
 //     field in the CLI configuration file ("~/.docker/config.json").
-//  4. If no context is configured, use the "default" context.
+//  5. If no context is configured, use the "default" context.
 //
 // # Fallbacks for backward-compatibility
 //
@@ -434,30 +471,46 @@ func (cli *DockerCli) CurrentContext() string {
 	return cli.currentContext
 }
 
-// CurrentContext returns the current context name, based on flags,
-// environment variables and the cli configuration file. It does not
-// validate if the given context exists or if it's valid; errors may
-// occur when trying to use it.
+// CurrentContextSource returns a description of where the current context
+// was set, for example, "DOCKER_CONTEXT environment variable" or the path of
+// the [ContextFileName] file.
+func (cli *DockerCli) CurrentContextSource() string {
+	return cli.contextSource
+}
+
+// resolveContextName returns the current context name, based on flags,
+// environment variables, project files, and the cli configuration file,
+// and a description of where it was set. It does not validate if the
+// given context exists or if it's valid; errors may occur when trying
+// to use it.
 //
 // Refer to [DockerCli.CurrentContext] above for further details.
-func resolveContextName(opts *cliflags.ClientOptions, cfg *configfile.ConfigFile) string {
+func resolveContextName(opts *cliflags.ClientOptions, cfg *configfile.ConfigFile) (name string, source string) {
 	if opts != nil && opts.Context != "" {
-		return opts.Context
+		return opts.Context, "--context flag"
 	}
 	if opts != nil && len(opts.Hosts) > 0 {
-		return DefaultContextName
+		return DefaultContextName, "--host flag"
 	}
 	if os.Getenv(client.EnvOverrideHost) != "" {
-		return DefaultContextName
+		return DefaultContextName, client.EnvOverrideHost + " environment variable"
 	}
 	if ctxName := os.Getenv(EnvOverrideContext); ctxName != "" {
-		return ctxName
+		return ctxName, EnvOverrideContext + " environment variable"
+	}
+	if wd, err := os.Getwd(); err == nil {
+		if ctxName, path := FindProjectContext(wd); ctxName != "" {
+			return ctxName, path
+		}
 	}
 	if cfg != nil && cfg.CurrentContext != "" {
 		// We don't validate if this context exists: errors may occur when trying to use it.
-		return cfg.CurrentContext
+		if cfg.Filename == "" {
+			return cfg.CurrentContext, `"currentContext" in the CLI configuration file`
+		}
+		return cfg.CurrentContext, `"currentContext" in ` + cfg.Filename
 	}
-	return DefaultContextName
+	return DefaultContextName, "no context configured"
 }
 
 // DockerEndpoint returns the current docker endpoint
@@ -538,7 +591,7 @@ func (cli *DockerCli) initialize() error {
 		}
 		if cli.client == nil {
 			ops := []client.Opt{client.WithUserAgent(cli.userAgent)}
-			if cli.client, cli.initErr = newAPIClientFromEndpoint(cli.dockerEndpoint, cli.configFile, ops...); cli.initErr != nil {
+			if cli.client, cli.initErr = newAPIClientFromEndpoint(cli.dockerEndpoint, cli.configFile, cli.connectionPlugins, ops...); cli.initErr != nil {
 				return
 			}
 		}
@@ -590,8 +643,15 @@ func NewDockerCli(ops ...CLIOption) (*DockerCli, error) {
 func getServerHost(hosts []string, defaultToTLS bool) (string, error) {
 	switch len(hosts) {
 	case 0:
-		return dopts.ParseHost(defaultToTLS, os.Getenv(client.EnvOverrideHost))
+		host := os.Getenv(client.EnvOverrideHost)
+		if getCustomScheme(host) != "" {
+			return host, nil
+		}
+		return dopts.ParseHost(defaultToTLS, host)
 	case 1:
+		if getCustomScheme(hosts[0]) != "" {
+			return hosts[0], nil
+		}
 		return dopts.ParseHost(defaultToTLS, hosts[0])
 	default:
 		return "", errors.New("specify only one -H")
@@ -615,6 +675,16 @@ func RegisterDefaultStoreEndpoints(ep ...store.NamedTypeGetter) {
 	defaultStoreEndpoints = append(defaultStoreEndpoints, ep...)
 }
 
+// withContextSecretStore returns a copy of the given context store config
+// that stores TLS keys through the credentials helper that's set in the
+// "contextCredsStore" field of the CLI configuration file, if any.
+func withContextSecretStore(cfg store.Config, configFile *configfile.ConfigFile) store.Config {
+	if configFile == nil || configFile.ContextCredsStore == "" {
+		return cfg
+	}
+	return cfg.WithSecretStore(credentials.NewNativeSecretStore(configFile.ContextCredsStore))
+}
+
 // DefaultContextStoreConfig returns a new store.Config with the default set of endpoints configured.
 func DefaultContextStoreConfig() store.Config {
 	return store.NewConfig(
@@ -10,6 +10,8 @@
 	"os"
 	"strings"
 
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/cli/connhelper"
 	"github.com/docker/cli/cli/streams"
 	"github.com/moby/moby/client"
 	"github.com/moby/term"
@@ -225,3 +227,14 @@ func WithUserAgent(userAgent string) CLIOption {
 		return nil
 	}
 }
+
+// WithConnectionPlugins configures the function used to look up a CLI plugin
+// to connect to a daemon host with a custom scheme (for example,
+// "k8s-pod://namespace/pod"), if no command is configured for the scheme in
+// the "connectionHelpers" in the config file.
+func WithConnectionPlugins(getHelper func(cfg *configfile.ConfigFile, daemonURL string) (*connhelper.ConnectionHelper, error)) CLIOption {
+	return func(cli *DockerCli) error {
+		cli.connectionPlugins = getHelper
+		return nil
+	}
+}
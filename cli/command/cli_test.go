@@ -50,6 +50,19 @@ func TestNewAPIClientFromFlagsForDefaultSchema(t *testing.T) {
 	assert.Equal(t, apiClient.ClientVersion(), client.MaxAPIVersion)
 }
 
+func TestNewAPIClientFromFlagsWithConnectionHelper(t *testing.T) {
+	opts := &flags.ClientOptions{Hosts: []string{"k8s-pod://namespace/pod"}}
+	configFile := &configfile.ConfigFile{
+		ConnectionHelpers: map[string]string{"k8s-pod": "docker-k8s-pod-connect"},
+	}
+	apiClient, err := NewAPIClientFromFlags(opts, configFile)
+	assert.NilError(t, err)
+	assert.Equal(t, apiClient.DaemonHost(), "http://docker.example.com")
+
+	_, err = NewAPIClientFromFlags(opts, &configfile.ConfigFile{})
+	assert.ErrorContains(t, err, `no connection helper found for "k8s-pod://namespace/pod"`)
+}
+
 func TestNewAPIClientFromFlagsWithCustomHeaders(t *testing.T) {
 	var received map[string]string
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,68 @@
+package command
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/cli/connhelper"
+)
+
+// connectionPluginFunc returns a connection helper that executes the CLI
+// plugin supporting the scheme of the given daemon URL, or nil if no plugin
+// supports the scheme.
+type connectionPluginFunc func(cfg *configfile.ConfigFile, daemonURL string) (*connhelper.ConnectionHelper, error)
+
+// customSchemeFormat is the format of the schemes of daemon hosts that can be
+// handled by connection helpers, as defined in RFC 3986, section 3.1.
+var customSchemeFormat = regexp.MustCompile(`^[a-z][a-z0-9+.-]*$`)
+
+// getCustomScheme returns the scheme of the given daemon host if it's not one
+// of the schemes that are handled by the CLI itself, or an empty string
+// otherwise.
+func getCustomScheme(host string) string {
+	scheme, _, ok := strings.Cut(host, "://")
+	if !ok {
+		return ""
+	}
+	switch scheme {
+	case "tcp", "unix", "npipe", "fd", "ssh", "http", "https":
+		return ""
+	}
+	if !customSchemeFormat.MatchString(scheme) {
+		return ""
+	}
+	return scheme
+}
+
+// getCustomConnectionHelper returns a connection helper for a daemon host with
+// a custom scheme (for example, "k8s-pod://namespace/pod"). It returns nil
+// without error if the host does not have a custom scheme.
+//
+// The command used to connect to the daemon is looked up in the
+// "connectionHelpers" of the config file first, and in the CLI plugins that
+// declare support for the scheme otherwise. The command is executed with the
+// daemon host as last argument, and must connect its stdin and stdout to the
+// daemon, in the same way as "docker system dial-stdio".
+func getCustomConnectionHelper(host string, configFile *configfile.ConfigFile, getPluginHelper connectionPluginFunc) (*connhelper.ConnectionHelper, error) {
+	scheme := getCustomScheme(host)
+	if scheme == "" {
+		return nil, nil
+	}
+	if configFile != nil {
+		if cmd := configFile.ConnectionHelpers[scheme]; cmd != "" {
+			return connhelper.GetCommandConnectionHelper(cmd, host)
+		}
+	}
+	if getPluginHelper != nil {
+		helper, err := getPluginHelper(configFile, host)
+		if err != nil {
+			return nil, err
+		}
+		if helper != nil {
+			return helper, nil
+		}
+	}
+	return nil, fmt.Errorf(`no connection helper found for %q: configure a command for the %q scheme in "connectionHelpers" in the config file, or install a CLI plugin that supports it`, host, scheme)
+}
@@ -0,0 +1,85 @@
+package command
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/cli/connhelper"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestGetCustomScheme(t *testing.T) {
+	tests := []struct {
+		host     string
+		expected string
+	}{
+		{host: "", expected: ""},
+		{host: "unix:///var/run/docker.sock", expected: ""},
+		{host: "tcp://localhost:2375", expected: ""},
+		{host: "ssh://me@example.com", expected: ""},
+		{host: "npipe:////./pipe/docker_engine", expected: ""},
+		{host: "localhost:2375", expected: ""},
+		{host: "k8s-pod://namespace/pod", expected: "k8s-pod"},
+		{host: "wsl://Ubuntu", expected: "wsl"},
+		{host: "vsock+tls://2:2375", expected: "vsock+tls"},
+		{host: "Upper://host", expected: ""},
+		{host: "1abc://host", expected: ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.host, func(t *testing.T) {
+			assert.Check(t, is.Equal(getCustomScheme(tc.host), tc.expected))
+		})
+	}
+}
+
+func TestGetCustomConnectionHelper(t *testing.T) {
+	var pluginHost string
+	pluginHelper := &connhelper.ConnectionHelper{Host: "http://plugin.example.com"}
+	getPluginHelper := func(_ *configfile.ConfigFile, daemonURL string) (*connhelper.ConnectionHelper, error) {
+		pluginHost = daemonURL
+		if daemonURL == "vsock://2:2375" {
+			return pluginHelper, nil
+		}
+		if daemonURL == "broken://host" {
+			return nil, errors.New("plugin error")
+		}
+		return nil, nil
+	}
+	configFile := &configfile.ConfigFile{
+		ConnectionHelpers: map[string]string{"wsl": "docker-wsl-connect"},
+	}
+
+	t.Run("builtin scheme", func(t *testing.T) {
+		helper, err := getCustomConnectionHelper("tcp://localhost:2375", configFile, getPluginHelper)
+		assert.NilError(t, err)
+		assert.Check(t, is.Nil(helper))
+	})
+
+	t.Run("config file", func(t *testing.T) {
+		pluginHost = ""
+		helper, err := getCustomConnectionHelper("wsl://Ubuntu", configFile, getPluginHelper)
+		assert.NilError(t, err)
+		assert.Assert(t, helper != nil)
+		assert.Check(t, is.Equal(helper.Host, "http://docker.example.com"))
+		assert.Check(t, is.Equal(pluginHost, ""), "plugins should not be looked up if the scheme is configured")
+	})
+
+	t.Run("plugin", func(t *testing.T) {
+		helper, err := getCustomConnectionHelper("vsock://2:2375", configFile, getPluginHelper)
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(helper, pluginHelper))
+		assert.Check(t, is.Equal(pluginHost, "vsock://2:2375"))
+	})
+
+	t.Run("plugin error", func(t *testing.T) {
+		_, err := getCustomConnectionHelper("broken://host", configFile, getPluginHelper)
+		assert.Check(t, is.Error(err, "plugin error"))
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := getCustomConnectionHelper("tsh://node", configFile, getPluginHelper)
+		assert.Check(t, is.ErrorContains(err, `no connection helper found for "tsh://node"`))
+	})
+}
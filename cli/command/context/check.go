@@ -0,0 +1,75 @@
+package context
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/formatter"
+)
+
+// defaultCheckTimeout is the default timeout for checking the Docker endpoint
+// of a context.
+const defaultCheckTimeout = 5 * time.Second
+
+// checkContexts checks the Docker endpoints of the given contexts concurrently,
+// and sets the results in their Check field. Contexts that could not be loaded
+// are marked as unreachable without being checked.
+func checkContexts(ctx context.Context, dockerCLI command.Cli, contexts []*formatter.ClientContext, timeout time.Duration) {
+	var wg sync.WaitGroup
+	for _, c := range contexts {
+		if c.Error != "" {
+			c.Check = &formatter.ClientContextCheck{}
+			continue
+		}
+		wg.Add(1)
+		go func(c *formatter.ClientContext) {
+			defer wg.Done()
+			check, err := checkContext(ctx, dockerCLI, c.Name, timeout)
+			if err != nil {
+				c.Error = err.Error()
+			}
+			c.Check = check
+		}(c)
+	}
+	wg.Wait()
+}
+
+// checkContext connects to the Docker endpoint of a context, and returns the
+// version of the daemon and the round-trip time of a request to the daemon.
+func checkContext(ctx context.Context, dockerCLI command.Cli, name string, timeout time.Duration) (*formatter.ClientContextCheck, error) {
+	check := &formatter.ClientContextCheck{}
+	apiClient, err := command.NewAPIClientForContext(dockerCLI, name)
+	if err != nil {
+		return check, err
+	}
+	defer apiClient.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// The first request includes connecting to the daemon; use a second
+	// request to measure the round-trip time.
+	ping, err := apiClient.Ping(ctx)
+	if err != nil {
+		return check, err
+	}
+	apiClient.NegotiateAPIVersionPing(ping)
+	check.APIVersion = ping.APIVersion
+
+	start := time.Now()
+	v, err := apiClient.ServerVersion(ctx)
+	if err != nil {
+		return check, err
+	}
+	check.Latency = time.Since(start)
+	check.Reachable = true
+	check.ServerVersion = v.Version
+	check.Os = v.Os
+	check.Arch = v.Arch
+	if check.APIVersion == "" {
+		check.APIVersion = v.APIVersion
+	}
+	return check, nil
+}
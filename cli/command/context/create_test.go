@@ -122,6 +122,27 @@ func TestCreate(t *testing.T) {
 			},
 			expecterErr: `unable to create docker endpoint config: skip-tls-verify: parsing "INVALID": invalid syntax`,
 		},
+		{
+			doc:  "ssh host with ssh-transport=native",
+			name: "ssh-transport-native",
+			options: createOptions{
+				endpoint: map[string]string{
+					"host":          "ssh://example.com",
+					"ssh-transport": "native",
+				},
+			},
+		},
+		{
+			doc:  "ssh host with ssh-transport=INVALID",
+			name: "ssh-transport-invalid",
+			options: createOptions{
+				endpoint: map[string]string{
+					"host":          "ssh://example.com",
+					"ssh-transport": "INVALID",
+				},
+			},
+			expecterErr: `unable to create docker endpoint config: invalid docker endpoint options: invalid SSH transport "INVALID": must be one of system, native`,
+		},
 		{
 			doc:  "unknown option",
 			name: "unknown-option",
@@ -0,0 +1,384 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.23
+
+package context
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/formatter/tabwriter"
+	"github.com/docker/cli/cli/config"
+	"github.com/docker/cli/cli/context/store"
+	"github.com/spf13/cobra"
+)
+
+// tableColumn is a column of the table that's printed for a command, and the
+// field of the JSON output of the command that's printed in it.
+type tableColumn struct {
+	header string
+	field  string
+}
+
+var (
+	taskColumns    = []tableColumn{{"ID", "ID"}, {"NAME", "Name"}, {"IMAGE", "Image"}, {"NODE", "Node"}, {"DESIRED STATE", "DesiredState"}, {"CURRENT STATE", "CurrentState"}, {"ERROR", "Error"}, {"PORTS", "Ports"}}
+	serviceColumns = []tableColumn{{"ID", "ID"}, {"NAME", "Name"}, {"MODE", "Mode"}, {"REPLICAS", "Replicas"}, {"IMAGE", "Image"}, {"PORTS", "Ports"}}
+	imageColumns   = []tableColumn{{"REPOSITORY", "Repository"}, {"TAG", "Tag"}, {"IMAGE ID", "ID"}, {"CREATED", "CreatedSince"}, {"SIZE", "Size"}}
+	psColumns      = []tableColumn{{"CONTAINER ID", "ID"}, {"IMAGE", "Image"}, {"COMMAND", "Command"}, {"CREATED", "RunningFor"}, {"STATUS", "Status"}, {"PORTS", "Ports"}, {"NAMES", "Names"}}
+)
+
+// fanOutCommands are the read-only commands that can be run against
+// multiple contexts. The value contains the columns of the default table
+// that's printed by the command, if it prints a table.
+var fanOutCommands = map[string][]tableColumn{
+	"config ls":      {{"ID", "ID"}, {"NAME", "Name"}, {"CREATED", "CreatedAt"}, {"UPDATED", "UpdatedAt"}},
+	"container ls":   psColumns,
+	"image ls":       imageColumns,
+	"images":         imageColumns,
+	"info":           nil,
+	"network ls":     {{"NETWORK ID", "ID"}, {"NAME", "Name"}, {"DRIVER", "Driver"}, {"SCOPE", "Scope"}},
+	"node ls":        {{"ID", "ID"}, {"HOSTNAME", "Hostname"}, {"STATUS", "Status"}, {"AVAILABILITY", "Availability"}, {"MANAGER STATUS", "ManagerStatus"}, {"ENGINE VERSION", "EngineVersion"}},
+	"node ps":        taskColumns,
+	"plugin ls":      {{"ID", "ID"}, {"NAME", "Name"}, {"DESCRIPTION", "Description"}, {"ENABLED", "Enabled"}},
+	"ps":             psColumns,
+	"secret ls":      {{"ID", "ID"}, {"NAME", "Name"}, {"DRIVER", "Driver"}, {"CREATED", "CreatedAt"}, {"UPDATED", "UpdatedAt"}},
+	"service ls":     serviceColumns,
+	"service ps":     taskColumns,
+	"stack ls":       {{"NAME", "Name"}, {"SERVICES", "Services"}},
+	"stack ps":       taskColumns,
+	"stack services": serviceColumns,
+	"system df":      {{"TYPE", "Type"}, {"TOTAL", "TotalCount"}, {"ACTIVE", "Active"}, {"SIZE", "Size"}, {"RECLAIMABLE", "Reclaimable"}},
+	"system info":    nil,
+	"version":        nil,
+	"volume ls":      {{"DRIVER", "Driver"}, {"VOLUME NAME", "Name"}},
+}
+
+// globalFlags are the global options that are passed on to the docker CLI
+// that's run against each context.
+var globalFlags = []string{"debug", "log-level"}
+
+// maxConcurrentContexts is the maximum number of contexts that the command
+// is run against concurrently.
+const maxConcurrentContexts = 8
+
+// execDocker runs the docker CLI with the given arguments against a
+// context, and returns its output. It's a variable so that it can be
+// replaced in tests.
+var execDocker = func(ctx context.Context, contextName string, args []string) (stdout, stderr []byte, _ error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, nil, err
+	}
+	var outBuf, errBuf bytes.Buffer
+	cmd := exec.CommandContext(ctx, exe, append([]string{"--context", contextName}, args...)...)
+	cmd.Env = append(os.Environ(), config.EnvOverrideConfigDir+"="+config.Dir())
+	cmd.Stdout = &outBuf
+	cmd.Stderr = &errBuf
+	err = cmd.Run()
+	return outBuf.Bytes(), errBuf.Bytes(), err
+}
+
+type execOptions struct {
+	contexts []string
+	command  []string
+}
+
+func newExecCommand(dockerCLI command.Cli) *cobra.Command {
+	var opts execOptions
+	cmd := &cobra.Command{
+		Use:   "exec [OPTIONS] -- COMMAND [ARG...]",
+		Short: "Run a read-only command against multiple contexts",
+		Args:  cli.RequiresMinArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.command = args
+			return runExec(cmd.Context(), dockerCLI, cmd.Root(), opts)
+		},
+		ValidArgsFunction:     cobra.NoFileCompletions,
+		DisableFlagsInUseLine: true,
+	}
+
+	flags := cmd.Flags()
+	flags.SetInterspersed(false)
+	flags.StringSliceVar(&opts.contexts, "contexts", nil, `Names or patterns of the contexts to run the command against (for example, "prod-*")`)
+	_ = cmd.MarkFlagRequired("contexts")
+	_ = cmd.RegisterFlagCompletionFunc("contexts", completeContextNames(dockerCLI, -1, false))
+	return cmd
+}
+
+type outputKind int
+
+const (
+	textOutput outputKind = iota
+	tableOutput
+	jsonOutput
+)
+
+type execResult struct {
+	stdout []byte
+	stderr []byte
+	err    error
+}
+
+// runExec runs a read-only command against the contexts that match
+// opts.contexts concurrently, and merges the output.
+func runExec(ctx context.Context, dockerCLI command.Cli, rootCmd *cobra.Command, opts execOptions) error {
+	names, err := matchContexts(dockerCLI.ContextStore(), opts.contexts)
+	if err != nil {
+		return err
+	}
+	kind, columns, args, err := getOutputKind(rootCmd, opts.command)
+	if err != nil {
+		return err
+	}
+	for _, name := range globalFlags {
+		if f := rootCmd.Flags().Lookup(name); f != nil && f.Changed {
+			args = append([]string{"--" + name + "=" + f.Value.String()}, args...)
+		}
+	}
+
+	results := make([]execResult, len(names))
+	sem := make(chan struct{}, maxConcurrentContexts)
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			stdout, stderr, err := execDocker(ctx, name, args)
+			results[i] = execResult{stdout: stdout, stderr: stderr, err: err}
+		}()
+	}
+	wg.Wait()
+
+	var errs []string
+	outputs := make(map[string][]byte, len(names))
+	for i, name := range names {
+		r := results[i]
+		if r.err != nil {
+			msg := strings.TrimSpace(string(r.stderr))
+			if msg == "" {
+				msg = r.err.Error()
+			}
+			errs = append(errs, fmt.Sprintf("context %s: %s", name, msg))
+			continue
+		}
+		for _, line := range splitLines(r.stderr) {
+			_, _ = fmt.Fprintf(dockerCLI.Err(), "%s: %s\n", name, line)
+		}
+		outputs[name] = r.stdout
+	}
+
+	switch kind {
+	case tableOutput:
+		var tableErrs []string
+		tableErrs, err = writeTable(dockerCLI.Out(), columns, names, outputs)
+		errs = append(errs, tableErrs...)
+	case jsonOutput:
+		err = writeJSONLines(dockerCLI.Out(), names, outputs)
+	default:
+		err = writeText(dockerCLI.Out(), names, outputs)
+	}
+	if err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "\n"))
+	}
+	return nil
+}
+
+// matchContexts returns the names of the contexts that match the given names
+// or patterns, in alphabetical order. Patterns use the syntax of [path.Match].
+func matchContexts(s store.Lister, patterns []string) ([]string, error) {
+	if len(patterns) == 0 {
+		return nil, errors.New("no contexts specified")
+	}
+	all, err := store.Names(s)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+		var found bool
+		for _, name := range all {
+			if ok, _ := path.Match(pattern, name); ok {
+				found = true
+				if !slices.Contains(names, name) {
+					names = append(names, name)
+				}
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("no contexts found matching %q", pattern)
+		}
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+// getOutputKind validates that args is a command that can be run against
+// multiple contexts, and returns the kind of output it produces, and the
+// arguments to run it with. For commands that print a table by default, the
+// JSON output is requested, and the columns of the table are returned.
+func getOutputKind(rootCmd *cobra.Command, args []string) (outputKind, []tableColumn, []string, error) {
+	subCmd, subArgs, err := rootCmd.Find(args)
+	if err != nil || subCmd == rootCmd {
+		return textOutput, nil, nil, fmt.Errorf("unknown command: %q", strings.Join(args, " "))
+	}
+	cmdPath := strings.TrimPrefix(subCmd.CommandPath(), rootCmd.Name()+" ")
+	columns, ok := fanOutCommands[cmdPath]
+	if !ok {
+		return textOutput, nil, nil, fmt.Errorf("%q is not supported with multiple contexts: only read-only commands, such as \"ps\" and \"image ls\", are supported", cmdPath)
+	}
+	if err := subCmd.ParseFlags(subArgs); err != nil {
+		return textOutput, nil, nil, err
+	}
+	for _, name := range []string{"quiet", "verbose"} {
+		if f := subCmd.Flags().Lookup(name); f != nil && f.Value.String() == "true" {
+			return textOutput, nil, args, nil
+		}
+	}
+	formatFlag := subCmd.Flags().Lookup("format")
+	if formatFlag == nil {
+		return textOutput, nil, args, nil
+	}
+	switch format := strings.TrimSpace(formatFlag.Value.String()); {
+	case format == "" && len(columns) > 0:
+		tableArgs := append(strings.Fields(cmdPath), "--format=json")
+		return tableOutput, columns, append(tableArgs, subArgs...), nil
+	case format == "json" || format == "{{json .}}":
+		return jsonOutput, nil, args, nil
+	default:
+		return textOutput, nil, args, nil
+	}
+}
+
+// writeTable merges the JSON output of a command for each context into a
+// single table, with a CONTEXT column. It returns the errors for contexts
+// with output that could not be parsed.
+func writeTable(out io.Writer, columns []tableColumn, names []string, outputs map[string][]byte) (errs []string, _ error) {
+	w := tabwriter.NewWriter(out, 10, 1, 3, ' ', 0)
+	headers := make([]string, 0, len(columns)+1)
+	headers = append(headers, "CONTEXT")
+	for _, c := range columns {
+		headers = append(headers, c.header)
+	}
+	_, _ = fmt.Fprintln(w, strings.Join(headers, "\t"))
+	for _, name := range names {
+		output, ok := outputs[name]
+		if !ok {
+			continue
+		}
+		rows, err := parseJSONLines(output)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("context %s: invalid output: %v", name, err))
+			continue
+		}
+		for _, row := range rows {
+			cells := make([]string, 0, len(columns)+1)
+			cells = append(cells, name)
+			for _, c := range columns {
+				cells = append(cells, jsonCell(row[c.field]))
+			}
+			_, _ = fmt.Fprintln(w, strings.Join(cells, "\t"))
+		}
+	}
+	return errs, w.Flush()
+}
+
+// parseJSONLines parses output that contains a JSON object on each line.
+func parseJSONLines(output []byte) ([]map[string]any, error) {
+	var rows []map[string]any
+	for _, line := range splitLines(output) {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		dec := json.NewDecoder(strings.NewReader(line))
+		dec.UseNumber()
+		var row map[string]any
+		if err := dec.Decode(&row); err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// jsonCell returns the text that's printed in a table for a JSON value.
+func jsonCell(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// writeJSONLines adds a "Context" field to each JSON object printed for each
+// context.
+func writeJSONLines(out io.Writer, names []string, outputs map[string][]byte) error {
+	for _, name := range names {
+		quotedName, err := json.Marshal(name)
+		if err != nil {
+			return err
+		}
+		for _, line := range splitLines(outputs[name]) {
+			line = strings.TrimSpace(line)
+			if strings.HasPrefix(line, "{") {
+				rest := strings.TrimSpace(line[1:])
+				if rest != "}" {
+					rest = "," + rest
+				}
+				line = `{"Context":` + string(quotedName) + rest
+			}
+			if _, err := fmt.Fprintln(out, line); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// writeText prefixes each line of output with the name of the context.
+func writeText(out io.Writer, names []string, outputs map[string][]byte) error {
+	var width int
+	for _, name := range names {
+		width = max(width, len(name))
+	}
+	for _, name := range names {
+		for _, line := range splitLines(outputs[name]) {
+			if _, err := fmt.Fprintf(out, "%-*s   %s\n", width, name, line); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func splitLines(output []byte) []string {
+	s := strings.TrimRight(string(output), "\r\n")
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
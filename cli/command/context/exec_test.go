@@ -0,0 +1,239 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.23
+
+package context
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+// newExecTestRootCmd returns a root command with stubs for the "ps",
+// "version", and "rm" commands.
+func newExecTestRootCmd() *cobra.Command {
+	rootCmd := &cobra.Command{Use: "docker"}
+	ps := &cobra.Command{Use: "ps"}
+	ps.Flags().BoolP("quiet", "q", false, "")
+	ps.Flags().BoolP("all", "a", false, "")
+	ps.Flags().String("format", "", "")
+	version := &cobra.Command{Use: "version"}
+	version.Flags().StringP("format", "f", "", "")
+	rootCmd.AddCommand(ps, version, &cobra.Command{Use: "rm"})
+	return rootCmd
+}
+
+func setExecDocker(t *testing.T, outputs map[string]string) *[][]string {
+	t.Helper()
+	orig := execDocker
+	var mu sync.Mutex
+	var calls [][]string
+	execDocker = func(_ context.Context, contextName string, args []string) ([]byte, []byte, error) {
+		mu.Lock()
+		calls = append(calls, args)
+		mu.Unlock()
+		out, ok := outputs[contextName]
+		if !ok {
+			return nil, []byte("Cannot connect to the Docker daemon\n"), errors.New("exit status 1")
+		}
+		return []byte(out), nil, nil
+	}
+	t.Cleanup(func() { execDocker = orig })
+	return &calls
+}
+
+func TestExecTable(t *testing.T) {
+	cli := makeFakeCli(t)
+	createTestContexts(t, cli, "prod-a", "prod-b", "dev")
+	calls := setExecDocker(t, map[string]string{
+		"prod-a": `{"ID":"abc123def456","Image":"nginx:latest","Command":"\"nginx -g 'daemon off;'\"","RunningFor":"2 hours ago","Status":"Up 2 hours","Ports":"80/tcp","Names":"web"}` + "\n",
+		"prod-b": strings.Join([]string{
+			`{"ID":"0123456789ab","Image":"postgres:16-alpine","Command":"\"docker-entrypoint.sh postgres\"","RunningFor":"10 minutes ago","Status":"Up 10 minutes","Ports":"5432/tcp","Names":"db"}`,
+			`{"ID":"ba9876543210","Image":"redis","Command":"\"redis-server\"","RunningFor":"3 days ago","Status":"Exited (0) 2 days ago","Ports":"","Names":"cache"}`,
+			"",
+		}, "\n"),
+	})
+	cli.OutBuffer().Reset()
+
+	err := runExec(context.Background(), cli, newExecTestRootCmd(), execOptions{
+		contexts: []string{"prod-*"},
+		command:  []string{"ps", "--all"},
+	})
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), strings.Join([]string{
+		"CONTEXT   CONTAINER ID   IMAGE                COMMAND                           CREATED          STATUS                  PORTS      NAMES",
+		"prod-a    abc123def456   nginx:latest         \"nginx -g 'daemon off;'\"          2 hours ago      Up 2 hours              80/tcp     web",
+		"prod-b    0123456789ab   postgres:16-alpine   \"docker-entrypoint.sh postgres\"   10 minutes ago   Up 10 minutes           5432/tcp   db",
+		"prod-b    ba9876543210   redis                \"redis-server\"                    3 days ago       Exited (0) 2 days ago              cache",
+		"",
+	}, "\n")))
+	assert.Check(t, is.DeepEqual(*calls, [][]string{
+		{"ps", "--format=json", "--all"},
+		{"ps", "--format=json", "--all"},
+	}))
+}
+
+func TestExecGlobalFlags(t *testing.T) {
+	cli := makeFakeCli(t)
+	createTestContexts(t, cli, "prod-a")
+	calls := setExecDocker(t, map[string]string{"prod-a": "28.0.1\n"})
+
+	rootCmd := newExecTestRootCmd()
+	rootCmd.Flags().BoolP("debug", "D", false, "")
+	rootCmd.Flags().StringP("log-level", "l", "info", "")
+	rootCmd.Flags().String("tlscacert", "", "")
+	assert.NilError(t, rootCmd.ParseFlags([]string{"--debug", "--log-level=warn", "--tlscacert=ca.pem"}))
+
+	err := runExec(context.Background(), cli, rootCmd, execOptions{
+		contexts: []string{"prod-a"},
+		command:  []string{"version"},
+	})
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(*calls, [][]string{{"--log-level=warn", "--debug=true", "version"}}))
+}
+
+func TestExecMaxConcurrency(t *testing.T) {
+	cli := makeFakeCli(t)
+	var names []string
+	for i := range maxConcurrentContexts * 2 {
+		names = append(names, fmt.Sprintf("ctx-%02d", i))
+	}
+	createTestContexts(t, cli, names...)
+
+	orig := execDocker
+	t.Cleanup(func() { execDocker = orig })
+	var running, maxRunning atomic.Int32
+	execDocker = func(context.Context, string, []string) ([]byte, []byte, error) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			m := maxRunning.Load()
+			if n <= m || maxRunning.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		return []byte("28.0.1\n"), nil, nil
+	}
+
+	err := runExec(context.Background(), cli, newExecTestRootCmd(), execOptions{
+		contexts: []string{"ctx-*"},
+		command:  []string{"version"},
+	})
+	assert.NilError(t, err)
+	assert.Check(t, maxRunning.Load() <= maxConcurrentContexts, "max running: %d", maxRunning.Load())
+}
+
+func TestExecInvalidJSON(t *testing.T) {
+	cli := makeFakeCli(t)
+	createTestContexts(t, cli, "prod-a", "prod-b")
+	setExecDocker(t, map[string]string{
+		"prod-a": `{"ID":"abc123def456","Names":"web"}` + "\n",
+		"prod-b": "CONTAINER ID   NAMES\n",
+	})
+	cli.OutBuffer().Reset()
+
+	err := runExec(context.Background(), cli, newExecTestRootCmd(), execOptions{
+		contexts: []string{"prod-*"},
+		command:  []string{"ps"},
+	})
+	assert.Check(t, is.ErrorContains(err, "context prod-b: invalid output"))
+	assert.Check(t, is.Contains(cli.OutBuffer().String(), "prod-a    abc123def456"))
+}
+
+func TestExecJSON(t *testing.T) {
+	cli := makeFakeCli(t)
+	createTestContexts(t, cli, "prod-a", "prod-b")
+	setExecDocker(t, map[string]string{
+		"prod-a": `{"ID":"abc123def456","Names":"web"}` + "\n",
+		"prod-b": "{}\n",
+	})
+	cli.OutBuffer().Reset()
+
+	err := runExec(context.Background(), cli, newExecTestRootCmd(), execOptions{
+		contexts: []string{"prod-a", "prod-b"},
+		command:  []string{"ps", "--format", "json"},
+	})
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), strings.Join([]string{
+		`{"Context":"prod-a","ID":"abc123def456","Names":"web"}`,
+		`{"Context":"prod-b"}`,
+		"",
+	}, "\n")))
+}
+
+func TestExecText(t *testing.T) {
+	cli := makeFakeCli(t)
+	createTestContexts(t, cli, "prod-a", "dev")
+	setExecDocker(t, map[string]string{
+		"prod-a": "28.0.1\n",
+	})
+	cli.OutBuffer().Reset()
+
+	err := runExec(context.Background(), cli, newExecTestRootCmd(), execOptions{
+		contexts: []string{"prod-a", "dev"},
+		command:  []string{"version", "-f", "{{.Server.Version}}"},
+	})
+	assert.Check(t, is.Error(err, "context dev: Cannot connect to the Docker daemon"))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "prod-a   28.0.1\n"))
+}
+
+func TestExecInvalid(t *testing.T) {
+	cli := makeFakeCli(t)
+	createTestContexts(t, cli, "prod-a")
+
+	tests := []struct {
+		doc         string
+		opts        execOptions
+		expectedErr string
+	}{
+		{
+			doc:         "no matching contexts",
+			opts:        execOptions{contexts: []string{"staging-*"}, command: []string{"ps"}},
+			expectedErr: `no contexts found matching "staging-*"`,
+		},
+		{
+			doc:         "invalid pattern",
+			opts:        execOptions{contexts: []string{"prod-["}, command: []string{"ps"}},
+			expectedErr: `invalid pattern "prod-["`,
+		},
+		{
+			doc:         "unknown command",
+			opts:        execOptions{contexts: []string{"prod-a"}, command: []string{"no-such-command"}},
+			expectedErr: `unknown command: "no-such-command"`,
+		},
+		{
+			doc:         "unsupported command",
+			opts:        execOptions{contexts: []string{"prod-a"}, command: []string{"rm", "foo"}},
+			expectedErr: `"rm" is not supported with multiple contexts`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.doc, func(t *testing.T) {
+			err := runExec(context.Background(), cli, newExecTestRootCmd(), tc.opts)
+			assert.Check(t, is.ErrorContains(err, tc.expectedErr))
+		})
+	}
+}
+
+func TestMatchContexts(t *testing.T) {
+	cli := makeFakeCli(t)
+	createTestContexts(t, cli, "prod-a", "prod-b", "dev")
+
+	names, err := matchContexts(cli.ContextStore(), []string{"prod-*", "dev", "prod-a"})
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(names, []string{"dev", "prod-a", "prod-b"}))
+
+	names, err = matchContexts(cli.ContextStore(), []string{"default"})
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(names, []string{"default"}))
+}
@@ -2,12 +2,15 @@
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"filippo.io/age"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/streams"
 	"gotest.tools/v3/assert"
@@ -21,11 +24,11 @@ func TestExportImportWithFile(t *testing.T) {
 		"MyCustomMetadata": t.Name(),
 	})
 	cli.ErrBuffer().Reset()
-	assert.NilError(t, runExport(cli, "test", contextFile))
+	assert.NilError(t, runExport(context.Background(), cli, exportOptions{contextName: "test", dest: contextFile}))
 	assert.Equal(t, cli.ErrBuffer().String(), fmt.Sprintf("Written file %q\n", contextFile))
 	cli.OutBuffer().Reset()
 	cli.ErrBuffer().Reset()
-	assert.NilError(t, runImport(cli, "test2", contextFile))
+	assert.NilError(t, runImport(context.Background(), cli, importOptions{name: "test2", source: contextFile}))
 	context1, err := cli.ContextStore().GetMetadata("test")
 	assert.NilError(t, err)
 	context2, err := cli.ContextStore().GetMetadata("test2")
@@ -52,12 +55,12 @@ func TestExportImportPipe(t *testing.T) {
 	})
 	cli.ErrBuffer().Reset()
 	cli.OutBuffer().Reset()
-	assert.NilError(t, runExport(cli, "test", "-"))
+	assert.NilError(t, runExport(context.Background(), cli, exportOptions{contextName: "test", dest: "-"}))
 	assert.Equal(t, cli.ErrBuffer().String(), "")
 	cli.SetIn(streams.NewIn(io.NopCloser(bytes.NewBuffer(cli.OutBuffer().Bytes()))))
 	cli.OutBuffer().Reset()
 	cli.ErrBuffer().Reset()
-	assert.NilError(t, runImport(cli, "test2", "-"))
+	assert.NilError(t, runImport(context.Background(), cli, importOptions{name: "test2", source: "-"}))
 	context1, err := cli.ContextStore().GetMetadata("test")
 	assert.NilError(t, err)
 	context2, err := cli.ContextStore().GetMetadata("test2")
@@ -82,6 +85,84 @@ func TestExportExistingFile(t *testing.T) {
 	cli := makeFakeCli(t)
 	cli.ErrBuffer().Reset()
 	assert.NilError(t, os.WriteFile(contextFile, []byte{}, 0o644))
-	err := runExport(cli, "test", contextFile)
+	err := runExport(context.Background(), cli, exportOptions{contextName: "test", dest: contextFile})
 	assert.Assert(t, os.IsExist(err))
 }
+
+func TestExportImportEncryptedWithRecipient(t *testing.T) {
+	identity, err := age.GenerateX25519Identity()
+	assert.NilError(t, err)
+	identityFile := filepath.Join(t.TempDir(), "key.txt")
+	assert.NilError(t, os.WriteFile(identityFile, []byte(identity.String()+"\n"), 0o600))
+
+	contextFile := filepath.Join(t.TempDir(), "exported")
+	cli := makeFakeCli(t)
+	createTestContext(t, cli, "test", nil)
+	assert.NilError(t, runExport(context.Background(), cli, exportOptions{
+		contextName: "test",
+		dest:        contextFile,
+		recipients:  []string{identity.Recipient().String()},
+	}))
+	content, err := os.ReadFile(contextFile)
+	assert.NilError(t, err)
+	assert.Check(t, is.Contains(string(content), "-----BEGIN AGE ENCRYPTED FILE-----"))
+
+	err = runImport(context.Background(), cli, importOptions{name: "test2", source: contextFile})
+	assert.Check(t, is.ErrorContains(err, "use --identity to specify the identity file"))
+
+	cli.OutBuffer().Reset()
+	assert.NilError(t, runImport(context.Background(), cli, importOptions{name: "test2", source: contextFile, identities: []string{identityFile}}))
+	assert.Check(t, is.Equal("test2\n", cli.OutBuffer().String()))
+	context1, err := cli.ContextStore().GetMetadata("test")
+	assert.NilError(t, err)
+	context2, err := cli.ContextStore().GetMetadata("test2")
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(context1.Endpoints, context2.Endpoints))
+	assert.Check(t, is.DeepEqual(context1.Metadata, context2.Metadata))
+}
+
+func TestExportImportEncryptedWithPassphrase(t *testing.T) {
+	contextFile := filepath.Join(t.TempDir(), "exported")
+	cli := makeFakeCli(t)
+	createTestContext(t, cli, "test", nil)
+	cli.SetIn(streams.NewIn(io.NopCloser(strings.NewReader("my passphrase\n"))))
+	assert.NilError(t, runExport(context.Background(), cli, exportOptions{contextName: "test", dest: contextFile, encrypt: true}))
+
+	cli.SetIn(streams.NewIn(io.NopCloser(strings.NewReader("wrong passphrase\n"))))
+	err := runImport(context.Background(), cli, importOptions{name: "test2", source: contextFile})
+	assert.Check(t, is.ErrorContains(err, "incorrect passphrase"))
+
+	cli.SetIn(streams.NewIn(io.NopCloser(strings.NewReader("my passphrase\n"))))
+	assert.NilError(t, runImport(context.Background(), cli, importOptions{name: "test2", source: contextFile}))
+	context1, err := cli.ContextStore().GetMetadata("test")
+	assert.NilError(t, err)
+	context2, err := cli.ContextStore().GetMetadata("test2")
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(context1.Endpoints, context2.Endpoints))
+	assert.Check(t, is.DeepEqual(context1.Metadata, context2.Metadata))
+}
+
+func TestExportEncryptedInvalidRecipient(t *testing.T) {
+	cli := makeFakeCli(t)
+	createTestContext(t, cli, "test", nil)
+	err := runExport(context.Background(), cli, exportOptions{
+		contextName: "test",
+		dest:        filepath.Join(t.TempDir(), "exported"),
+		recipients:  []string{"not-a-recipient"},
+	})
+	assert.Check(t, is.ErrorContains(err, `invalid recipient "not-a-recipient"`))
+}
+
+func TestExportEncryptedPassphraseAndRecipient(t *testing.T) {
+	cli := makeFakeCli(t)
+	createTestContext(t, cli, "test", nil)
+	identity, err := age.GenerateX25519Identity()
+	assert.NilError(t, err)
+	err = runExport(context.Background(), cli, exportOptions{
+		contextName: "test",
+		dest:        filepath.Join(t.TempDir(), "exported"),
+		encrypt:     true,
+		recipients:  []string{identity.Recipient().String()},
+	})
+	assert.Check(t, is.ErrorContains(err, "conflicting options: --encrypt cannot be used with --recipient"))
+}
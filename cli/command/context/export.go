@@ -1,35 +1,49 @@
 package context
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"os"
 
+	"filippo.io/age"
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/context/store"
 	"github.com/spf13/cobra"
 )
 
+type exportOptions struct {
+	contextName string
+	dest        string
+	encrypt     bool
+	recipients  []string
+}
+
 func newExportCommand(dockerCLI command.Cli) *cobra.Command {
-	return &cobra.Command{
+	var opts exportOptions
+	cmd := &cobra.Command{
 		Use:   "export [OPTIONS] CONTEXT [FILE|-]",
 		Short: "Export a context to a tar archive FILE or a tar stream on STDOUT.",
 		Args:  cli.RequiresRangeArgs(1, 2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			contextName := args[0]
-			var dest string
+			opts.contextName = args[0]
 			if len(args) == 2 {
-				dest = args[1]
+				opts.dest = args[1]
 			} else {
-				dest = contextName + ".dockercontext"
+				opts.dest = opts.contextName + ".dockercontext"
 			}
-			return runExport(dockerCLI, contextName, dest)
+			return runExport(cmd.Context(), dockerCLI, opts)
 		},
 		ValidArgsFunction:     completeContextNames(dockerCLI, 1, true),
 		DisableFlagsInUseLine: true,
 	}
+
+	flags := cmd.Flags()
+	flags.BoolVar(&opts.encrypt, "encrypt", false, "Encrypt the exported context with a passphrase")
+	flags.StringSliceVar(&opts.recipients, "recipient", nil, `Encrypt the exported context for an age recipient ("age1...")`)
+	return cmd
 }
 
 func writeTo(dockerCli command.Cli, reader io.Reader, dest string) error {
@@ -59,11 +73,49 @@ func writeTo(dockerCli command.Cli, reader io.Reader, dest string) error {
 }
 
 // runExport exports a Docker context.
-func runExport(dockerCLI command.Cli, contextName string, dest string) error {
-	if err := store.ValidateContextName(contextName); err != nil && contextName != command.DefaultContextName {
+func runExport(ctx context.Context, dockerCLI command.Cli, opts exportOptions) error {
+	if err := store.ValidateContextName(opts.contextName); err != nil && opts.contextName != command.DefaultContextName {
 		return err
 	}
-	reader := store.Export(contextName, dockerCLI.ContextStore())
+	if opts.encrypt && len(opts.recipients) > 0 {
+		return errors.New("conflicting options: --encrypt cannot be used with --recipient; use either a passphrase or recipients to encrypt the context")
+	}
+	if !opts.encrypt && len(opts.recipients) == 0 {
+		reader := store.Export(opts.contextName, dockerCLI.ContextStore())
+		defer reader.Close()
+		return writeTo(dockerCLI, reader, opts.dest)
+	}
+
+	recipients, err := getRecipients(ctx, dockerCLI, opts.recipients)
+	if err != nil {
+		return err
+	}
+	reader := store.ExportEncrypted(opts.contextName, dockerCLI.ContextStore(), recipients...)
 	defer reader.Close()
-	return writeTo(dockerCLI, reader, dest)
+	return writeTo(dockerCLI, reader, opts.dest)
+}
+
+// getRecipients returns the age recipients to encrypt an exported context
+// for. If no recipients are set, the user is prompted for a passphrase.
+func getRecipients(ctx context.Context, dockerCLI command.Cli, recipients []string) ([]age.Recipient, error) {
+	if len(recipients) == 0 {
+		passphrase, err := readPassphrase(ctx, dockerCLI, true)
+		if err != nil {
+			return nil, err
+		}
+		r, err := age.NewScryptRecipient(passphrase)
+		if err != nil {
+			return nil, err
+		}
+		return []age.Recipient{r}, nil
+	}
+	result := make([]age.Recipient, 0, len(recipients))
+	for _, recipient := range recipients {
+		r, err := age.ParseX25519Recipient(recipient)
+		if err != nil {
+			return nil, fmt.Errorf("invalid recipient %q: %w", recipient, err)
+		}
+		result = append(result, r)
+	}
+	return result, nil
 }
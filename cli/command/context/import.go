@@ -1,10 +1,14 @@
 package context
 
 import (
+	"bufio"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 
+	"filippo.io/age"
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/completion"
@@ -12,32 +16,44 @@
 	"github.com/spf13/cobra"
 )
 
+type importOptions struct {
+	name       string
+	source     string
+	identities []string
+}
+
 func newImportCommand(dockerCLI command.Cli) *cobra.Command {
+	var opts importOptions
 	cmd := &cobra.Command{
-		Use:   "import CONTEXT FILE|-",
+		Use:   "import [OPTIONS] CONTEXT FILE|-",
 		Short: "Import a context from a tar or zip file",
 		Args:  cli.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runImport(dockerCLI, args[0], args[1])
+			opts.name = args[0]
+			opts.source = args[1]
+			return runImport(cmd.Context(), dockerCLI, opts)
 		},
 		// TODO(thaJeztah): this should also include "-"
 		ValidArgsFunction:     completion.FileNames(),
 		DisableFlagsInUseLine: true,
 	}
+
+	flags := cmd.Flags()
+	flags.StringSliceVarP(&opts.identities, "identity", "i", nil, "Path to an age identity file to decrypt an encrypted context")
 	return cmd
 }
 
 // runImport imports a Docker context.
-func runImport(dockerCLI command.Cli, name string, source string) error {
-	if err := checkContextNameForCreation(dockerCLI.ContextStore(), name); err != nil {
+func runImport(ctx context.Context, dockerCLI command.Cli, opts importOptions) error {
+	if err := checkContextNameForCreation(dockerCLI.ContextStore(), opts.name); err != nil {
 		return err
 	}
 
 	var reader io.Reader
-	if source == "-" {
+	if opts.source == "-" {
 		reader = dockerCLI.In()
 	} else {
-		f, err := os.Open(source)
+		f, err := os.Open(opts.source)
 		if err != nil {
 			return err
 		}
@@ -45,11 +61,56 @@ func runImport(dockerCLI command.Cli, name string, source string) error {
 		reader = f
 	}
 
-	if err := store.Import(name, dockerCLI.ContextStore(), reader); err != nil {
+	r := bufio.NewReader(reader)
+	encrypted, err := store.IsEncrypted(r)
+	if err != nil {
+		return err
+	}
+	if encrypted {
+		identities, err := getIdentities(ctx, dockerCLI, opts)
+		if err != nil {
+			return err
+		}
+		err = store.ImportEncrypted(opts.name, dockerCLI.ContextStore(), r, identities...)
+		var noMatchErr *age.NoIdentityMatchError
+		if len(opts.identities) == 0 && errors.As(err, &noMatchErr) {
+			return errors.New("failed to decrypt context: the context is encrypted for a recipient: use --identity to specify the identity file to decrypt it")
+		}
+		if err != nil {
+			return fmt.Errorf("failed to decrypt context: %w", err)
+		}
+	} else if err := store.Import(opts.name, dockerCLI.ContextStore(), r); err != nil {
 		return err
 	}
 
-	_, _ = fmt.Fprintln(dockerCLI.Out(), name)
-	_, _ = fmt.Fprintf(dockerCLI.Err(), "Successfully imported context %q\n", name)
+	_, _ = fmt.Fprintln(dockerCLI.Out(), opts.name)
+	_, _ = fmt.Fprintf(dockerCLI.Err(), "Successfully imported context %q\n", opts.name)
 	return nil
 }
+
+// getIdentities returns the age identities to decrypt an encrypted context
+// with, from the identity files in opts. The user is prompted for a
+// passphrase if the context is encrypted with a passphrase.
+func getIdentities(ctx context.Context, dockerCLI command.Cli, opts importOptions) ([]age.Identity, error) {
+	identities := []age.Identity{&passphraseIdentity{
+		readPassphrase: func() (string, error) {
+			if opts.source == "-" {
+				return "", errors.New("cannot read the passphrase from STDIN when importing from STDIN: import from a file instead")
+			}
+			return readPassphrase(ctx, dockerCLI, false)
+		},
+	}}
+	for _, fileName := range opts.identities {
+		f, err := os.Open(fileName)
+		if err != nil {
+			return nil, err
+		}
+		ids, err := age.ParseIdentities(f)
+		_ = f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read identity file %s: %w", fileName, err)
+		}
+		identities = append(identities, ids...)
+	}
+	return identities, nil
+}
@@ -4,9 +4,11 @@
 package context
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"sort"
+	"time"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
@@ -19,19 +21,21 @@
 )
 
 type listOptions struct {
-	format string
-	quiet  bool
+	format  string
+	quiet   bool
+	check   bool
+	timeout time.Duration
 }
 
 func newListCommand(dockerCLI command.Cli) *cobra.Command {
-	opts := &listOptions{}
+	opts := &listOptions{timeout: defaultCheckTimeout}
 	cmd := &cobra.Command{
 		Use:     "ls [OPTIONS]",
 		Aliases: []string{"list"},
 		Short:   "List contexts",
 		Args:    cli.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runList(dockerCLI, opts)
+			return runList(cmd.Context(), dockerCLI, opts)
 		},
 		ValidArgsFunction:     cobra.NoFileCompletions,
 		DisableFlagsInUseLine: true,
@@ -40,13 +44,18 @@ func newListCommand(dockerCLI command.Cli) *cobra.Command {
 	flags := cmd.Flags()
 	flags.StringVar(&opts.format, "format", "", flagsHelper.FormatHelp)
 	flags.BoolVarP(&opts.quiet, "quiet", "q", false, "Only show context names")
+	flags.BoolVar(&opts.check, "check", false, "Check if the Docker endpoint of each context is reachable")
+	flags.DurationVar(&opts.timeout, "timeout", defaultCheckTimeout, "Timeout for checking the Docker endpoint of each context (with --check)")
 	return cmd
 }
 
-func runList(dockerCli command.Cli, opts *listOptions) error {
+func runList(ctx context.Context, dockerCli command.Cli, opts *listOptions) error {
 	if opts.format == "" {
 		opts.format = formatter.TableFormatKey
 	}
+	if opts.check && opts.timeout <= 0 {
+		return fmt.Errorf("invalid value for --timeout: %s: must be positive", opts.timeout)
+	}
 	contextMap, err := dockerCli.ContextStore().List()
 	if err != nil {
 		return err
@@ -104,6 +113,9 @@ func runList(dockerCli command.Cli, opts *listOptions) error {
 	sort.Slice(contexts, func(i, j int) bool {
 		return sortorder.NaturalLess(contexts[i].Name, contexts[j].Name)
 	})
+	if opts.check && !opts.quiet {
+		checkContexts(ctx, dockerCli, contexts, opts.timeout)
+	}
 	if err := format(dockerCli, opts, contexts); err != nil {
 		return err
 	}
@@ -115,9 +127,13 @@ func runList(dockerCli command.Cli, opts *listOptions) error {
 }
 
 func format(dockerCli command.Cli, opts *listOptions, contexts []*formatter.ClientContext) error {
+	contextFormat := formatter.NewClientContextFormat(opts.format, opts.quiet)
+	if opts.check {
+		contextFormat = formatter.NewClientContextCheckFormat(opts.format, opts.quiet)
+	}
 	contextCtx := formatter.Context{
 		Output: dockerCli.Out(),
-		Format: formatter.NewClientContextFormat(opts.format, opts.quiet),
+		Format: contextFormat,
 	}
 	return formatter.ClientContextWrite(contextCtx, contexts)
 }
@@ -1,11 +1,19 @@
 package context
 
 import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/formatter"
+	"github.com/moby/moby/api/types"
 	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
 	"gotest.tools/v3/golden"
 )
 
@@ -33,7 +41,7 @@ func TestList(t *testing.T) {
 	createTestContexts(t, cli, "current", "other", "unset")
 	cli.SetCurrentContext("current")
 	cli.OutBuffer().Reset()
-	assert.NilError(t, runList(cli, &listOptions{}))
+	assert.NilError(t, runList(context.Background(), cli, &listOptions{}))
 	golden.Assert(t, cli.OutBuffer().String(), "list.golden")
 }
 
@@ -47,19 +55,19 @@ func TestListJSON(t *testing.T) {
 
 	t.Run("format={{json .}}", func(t *testing.T) {
 		cli.OutBuffer().Reset()
-		assert.NilError(t, runList(cli, &listOptions{format: formatter.JSONFormat}))
+		assert.NilError(t, runList(context.Background(), cli, &listOptions{format: formatter.JSONFormat}))
 		golden.Assert(t, cli.OutBuffer().String(), "list-json.golden")
 	})
 
 	t.Run("format=json", func(t *testing.T) {
 		cli.OutBuffer().Reset()
-		assert.NilError(t, runList(cli, &listOptions{format: formatter.JSONFormatKey}))
+		assert.NilError(t, runList(context.Background(), cli, &listOptions{format: formatter.JSONFormatKey}))
 		golden.Assert(t, cli.OutBuffer().String(), "list-json.golden")
 	})
 
 	t.Run("format={{ json .Name }}", func(t *testing.T) {
 		cli.OutBuffer().Reset()
-		assert.NilError(t, runList(cli, &listOptions{format: `{{ json .Name }}`}))
+		assert.NilError(t, runList(context.Background(), cli, &listOptions{format: `{{ json .Name }}`}))
 		golden.Assert(t, cli.OutBuffer().String(), "list-json-name.golden")
 	})
 }
@@ -69,7 +77,7 @@ func TestListQuiet(t *testing.T) {
 	createTestContexts(t, cli, "current", "other")
 	cli.SetCurrentContext("current")
 	cli.OutBuffer().Reset()
-	assert.NilError(t, runList(cli, &listOptions{quiet: true}))
+	assert.NilError(t, runList(context.Background(), cli, &listOptions{quiet: true}))
 	golden.Assert(t, cli.OutBuffer().String(), "quiet-list.golden")
 }
 
@@ -77,6 +85,57 @@ func TestListError(t *testing.T) {
 	cli := makeFakeCli(t)
 	cli.SetCurrentContext("nosuchcontext")
 	cli.OutBuffer().Reset()
-	assert.NilError(t, runList(cli, &listOptions{}))
+	assert.NilError(t, runList(context.Background(), cli, &listOptions{}))
 	golden.Assert(t, cli.OutBuffer().String(), "list-with-error.golden")
 }
+
+func TestListCheck(t *testing.T) {
+	daemon := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Api-Version", "1.51")
+		w.Header().Set("Ostype", "linux")
+		if strings.HasSuffix(r.URL.Path, "/version") {
+			_ = json.NewEncoder(w).Encode(types.Version{Version: "28.5.0", APIVersion: "1.51", Os: "linux", Arch: "arm64"})
+			return
+		}
+		_, _ = w.Write([]byte("OK"))
+	}))
+	defer daemon.Close()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NilError(t, err)
+	unreachableHost := "tcp://" + l.Addr().String()
+	assert.NilError(t, l.Close())
+
+	cli := makeFakeCli(t)
+	for name, host := range map[string]string{"reachable": "tcp://" + daemon.Listener.Addr().String(), "unreachable": unreachableHost} {
+		assert.NilError(t, runCreate(cli, name, createOptions{endpoint: map[string]string{keyHost: host}}))
+	}
+	cli.SetCurrentContext("reachable")
+
+	t.Run("table", func(t *testing.T) {
+		cli.OutBuffer().Reset()
+		assert.NilError(t, runList(context.Background(), cli, &listOptions{check: true, timeout: defaultCheckTimeout}))
+		lines := strings.Split(strings.TrimSpace(cli.OutBuffer().String()), "\n")
+		assert.Assert(t, is.Len(lines, 4))
+		assert.Check(t, is.Regexp(`^NAME\s+DOCKER ENDPOINT\s+STATUS\s+SERVER VERSION\s+API VERSION\s+PLATFORM\s+LATENCY\s+ERROR$`, lines[0]))
+		assert.Check(t, is.Regexp(`^default\s+unix:///var/run/docker.sock\s+(un)?reachable\s+`, lines[1]))
+		assert.Check(t, is.Regexp(`^reachable \*\s+tcp://\S+\s+reachable\s+28.5.0\s+1.51\s+linux/arm64\s+\d+(\.\d+)?(µs|ms|s)\s*$`, lines[2]))
+		assert.Check(t, is.Regexp(`^unreachable\s+tcp://\S+\s+unreachable\s+\S+`, lines[3]))
+	})
+
+	t.Run("format", func(t *testing.T) {
+		cli.OutBuffer().Reset()
+		assert.NilError(t, runList(context.Background(), cli, &listOptions{
+			format:  "{{.Name}} {{.Status}} {{.ServerVersion}} {{.APIVersion}} {{.Platform}}",
+			check:   true,
+			timeout: defaultCheckTimeout,
+		}))
+		assert.Check(t, is.Contains(cli.OutBuffer().String(), "reachable reachable 28.5.0 1.51 linux/arm64\n"))
+		assert.Check(t, is.Contains(cli.OutBuffer().String(), "unreachable unreachable   \n"))
+	})
+
+	t.Run("invalid timeout", func(t *testing.T) {
+		err := runList(context.Background(), cli, &listOptions{check: true})
+		assert.Check(t, is.Error(err, "invalid value for --timeout: 0s: must be positive"))
+	})
+}
@@ -18,6 +18,7 @@
 	keyCert          = "cert"
 	keyKey           = "key"
 	keySkipTLSVerify = "skip-tls-verify"
+	keySSHTransport  = "ssh-transport"
 )
 
 type configKeyDescription struct {
@@ -33,6 +34,7 @@ type configKeyDescription struct {
 		keyCert:          {},
 		keyKey:           {},
 		keySkipTLSVerify: {},
+		keySSHTransport:  {},
 	}
 	dockerConfigKeysDescriptions = []configKeyDescription{
 		{
@@ -59,6 +61,10 @@ type configKeyDescription struct {
 			name:        keySkipTLSVerify,
 			description: "Skip TLS certificate validation",
 		},
+		{
+			name:        keySSHTransport,
+			description: `Transport for "ssh://" hosts: "system" to use the ssh binary (default), or "native" to use the built-in SSH client`,
+		},
 	}
 )
 
@@ -114,6 +120,7 @@ func getDockerEndpoint(contextStore store.Reader, config map[string]string) (doc
 		EndpointMeta: docker.EndpointMeta{
 			Host:          config[keyHost],
 			SkipTLSVerify: skipTLSVerify,
+			SSHTransport:  config[keySSHTransport],
 		},
 		TLSData: tlsData,
 	}
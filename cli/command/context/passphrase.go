@@ -0,0 +1,78 @@
+package context
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"filippo.io/age"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/internal/prompt"
+)
+
+// readPassphrase prompts the user for the passphrase to encrypt or decrypt
+// a context with. If confirm is set, and the input is a terminal, the user
+// is asked to enter the passphrase twice.
+func readPassphrase(ctx context.Context, dockerCLI command.Cli, confirm bool) (string, error) {
+	if dockerCLI.In().IsTerminal() {
+		restoreInput, err := prompt.DisableInputEcho(dockerCLI.In())
+		if err != nil {
+			return "", err
+		}
+		defer func() {
+			if err := restoreInput(); err != nil {
+				_, _ = fmt.Fprintln(dockerCLI.Err(), "Error: failed to restore terminal state to echo input:", err)
+			}
+		}()
+	}
+
+	passphrase, err := prompt.ReadInput(ctx, dockerCLI.In(), dockerCLI.Err(), "Passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	_, _ = fmt.Fprintln(dockerCLI.Err())
+	if passphrase == "" {
+		return "", errors.New("passphrase cannot be empty")
+	}
+	if confirm && dockerCLI.In().IsTerminal() {
+		confirmed, err := prompt.ReadInput(ctx, dockerCLI.In(), dockerCLI.Err(), "Confirm passphrase: ")
+		if err != nil {
+			return "", err
+		}
+		_, _ = fmt.Fprintln(dockerCLI.Err())
+		if confirmed != passphrase {
+			return "", errors.New("passphrases do not match")
+		}
+	}
+	return passphrase, nil
+}
+
+// passphraseIdentity is an [age.Identity] for contexts that are encrypted
+// with a passphrase. It only prompts for the passphrase if the context is
+// encrypted with a passphrase, and not for a recipient.
+type passphraseIdentity struct {
+	readPassphrase func() (string, error)
+}
+
+// Unwrap implements [age.Identity].
+func (i *passphraseIdentity) Unwrap(stanzas []*age.Stanza) ([]byte, error) {
+	for _, s := range stanzas {
+		if s.Type != "scrypt" {
+			continue
+		}
+		passphrase, err := i.readPassphrase()
+		if err != nil {
+			return nil, err
+		}
+		identity, err := age.NewScryptIdentity(passphrase)
+		if err != nil {
+			return nil, err
+		}
+		fileKey, err := identity.Unwrap(stanzas)
+		if errors.Is(err, age.ErrIncorrectIdentity) {
+			return nil, errors.New("incorrect passphrase")
+		}
+		return fileKey, err
+	}
+	return nil, age.ErrIncorrectIdentity
+}
@@ -8,22 +8,43 @@
 	"github.com/spf13/cobra"
 )
 
+type showOptions struct {
+	verbose bool
+}
+
 // newShowCommand creates a new cobra.Command for `docker context sow`
 func newShowCommand(dockerCLI command.Cli) *cobra.Command {
+	var opts showOptions
 	cmd := &cobra.Command{
 		Use:   "show",
 		Short: "Print the name of the current context",
 		Args:  cli.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			runShow(dockerCLI)
+			runShow(dockerCLI, opts)
 			return nil
 		},
 		ValidArgsFunction:     cobra.NoFileCompletions,
 		DisableFlagsInUseLine: true,
 	}
+	cmd.Flags().BoolVarP(&opts.verbose, "verbose", "v", false, "Show where the current context is set")
 	return cmd
 }
 
-func runShow(dockerCli command.Cli) {
-	fmt.Fprintln(dockerCli.Out(), dockerCli.CurrentContext())
+// contextSourceProvider is implemented by CLIs that know where the current
+// context was set.
+type contextSourceProvider interface {
+	CurrentContextSource() string
+}
+
+func runShow(dockerCli command.Cli, opts showOptions) {
+	name := dockerCli.CurrentContext()
+	if !opts.verbose {
+		fmt.Fprintln(dockerCli.Out(), name)
+		return
+	}
+	source := "unknown"
+	if csp, ok := dockerCli.(contextSourceProvider); ok && csp.CurrentContextSource() != "" {
+		source = csp.CurrentContextSource()
+	}
+	fmt.Fprintf(dockerCli.Out(), "Name:   %s\nSource: %s\n", name, source)
 }
@@ -3,6 +3,9 @@
 import (
 	"testing"
 
+	"github.com/docker/cli/internal/test"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
 	"gotest.tools/v3/golden"
 )
 
@@ -12,6 +15,25 @@ func TestShow(t *testing.T) {
 	cli.SetCurrentContext("current")
 
 	cli.OutBuffer().Reset()
-	runShow(cli)
+	runShow(cli, showOptions{})
 	golden.Assert(t, cli.OutBuffer().String(), "show.golden")
 }
+
+type fakeCliWithContextSource struct {
+	*test.FakeCli
+	source string
+}
+
+func (c *fakeCliWithContextSource) CurrentContextSource() string {
+	return c.source
+}
+
+func TestShowVerbose(t *testing.T) {
+	cli := makeFakeCli(t)
+	createTestContext(t, cli, "current", nil)
+	cli.SetCurrentContext("current")
+
+	cli.OutBuffer().Reset()
+	runShow(&fakeCliWithContextSource{FakeCli: cli, source: "/home/user/project/.dockercontext"}, showOptions{verbose: true})
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "Name:   current\nSource: /home/user/project/.dockercontext\n"))
+}
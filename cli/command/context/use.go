@@ -54,5 +54,13 @@ func runUse(dockerCLI command.Cli, name string) error {
 		_, _ = fmt.Fprintf(dockerCLI.Err(), "Warning: %[1]s environment variable overrides the active context. "+
 			"To use %[2]q, either set the global --context flag, or unset %[1]s environment variable.\n", client.EnvOverrideHost, name)
 	}
+	if os.Getenv(client.EnvOverrideHost) == "" && os.Getenv(command.EnvOverrideContext) == "" {
+		if wd, err := os.Getwd(); err == nil {
+			if ctxName, path := command.FindProjectContext(wd); ctxName != "" && ctxName != name {
+				_, _ = fmt.Fprintf(dockerCLI.Err(), "Warning: %[1]s overrides the active context in this directory. "+
+					"To use %[2]q, either set the global --context flag, or remove the context from %[1]s.\n", path, name)
+			}
+		}
+	}
 	return nil
 }
@@ -7,6 +7,7 @@
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
 
 	"github.com/containerd/errdefs"
@@ -111,6 +112,39 @@ func TestUseHostOverride(t *testing.T) {
 	assert.Equal(t, cli.OutBuffer().String(), "default\n")
 }
 
+func TestUseProjectContextOverride(t *testing.T) {
+	t.Setenv("DOCKER_HOST", "")
+	t.Setenv("DOCKER_CONTEXT", "")
+	projectDir, err := filepath.EvalSymlinks(t.TempDir())
+	assert.NilError(t, err)
+	contextFile := filepath.Join(projectDir, command.ContextFileName)
+	assert.NilError(t, os.WriteFile(contextFile, []byte("project\n"), 0o644))
+	assert.NilError(t, os.Mkdir(filepath.Join(projectDir, "sub"), 0o755))
+	wd, err := os.Getwd()
+	assert.NilError(t, err)
+	assert.NilError(t, os.Chdir(filepath.Join(projectDir, "sub")))
+	t.Cleanup(func() { assert.NilError(t, os.Chdir(wd)) })
+
+	configDir := t.TempDir()
+	testCfg := configfile.New(filepath.Join(configDir, "config.json"))
+	cli := makeFakeCli(t, withCliConfig(testCfg))
+	for _, name := range []string{"test", "project"} {
+		assert.NilError(t, runCreate(cli, name, createOptions{endpoint: map[string]string{}}))
+	}
+
+	cli.ResetOutputBuffers()
+	assert.NilError(t, newUseCommand(cli).RunE(nil, []string{"test"}))
+	assert.Check(t, is.Contains(cli.ErrBuffer().String(), `Current context is now "test"`))
+	assert.Check(t, is.Contains(cli.ErrBuffer().String(),
+		"Warning: "+contextFile+` overrides the active context in this directory. To use "test", either set the global --context flag, or remove the context from `+contextFile+".",
+	))
+
+	// using the context of the project should not print a warning
+	cli.ResetOutputBuffers()
+	assert.NilError(t, newUseCommand(cli).RunE(nil, []string{"project"}))
+	assert.Check(t, !strings.Contains(cli.ErrBuffer().String(), "Warning"))
+}
+
 // An empty DOCKER_HOST used to break the 'context use' flow.
 // So we have a test with fewer fakes that tests this flow holistically.
 // https://github.com/docker/cli/issues/3667
@@ -0,0 +1,59 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.23
+
+package command
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ContextFileName is the name of the file that sets the context to use for a
+// project. The CLI looks for this file in the current directory and its
+// parent directories. The file contains the name of the context on its first
+// line.
+const ContextFileName = ".dockercontext"
+
+// FindProjectContext looks for a [ContextFileName] file in dir and its parent
+// directories. It returns the name of the context, and the path of the file
+// that sets it. An empty name is returned if no context is set.
+func FindProjectContext(dir string) (name, path string) {
+	for {
+		p := filepath.Join(dir, ContextFileName)
+		n, err := readProjectContextFile(p)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			logrus.WithError(err).Debugf("failed to read %s", p)
+		}
+		if n != "" {
+			return n, p
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", ""
+		}
+		dir = parent
+	}
+}
+
+// readProjectContextFile reads the name of the context from a [ContextFileName]
+// file. Empty lines and comments (starting with "#") are ignored.
+func readProjectContextFile(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		if fields := strings.Fields(line); len(fields) > 0 {
+			return fields[0], nil
+		}
+	}
+	return "", scanner.Err()
+}
@@ -0,0 +1,154 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.23
+
+package command
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/cli/cli/config/configfile"
+	cliflags "github.com/docker/cli/cli/flags"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/fs"
+)
+
+func TestFindProjectContext(t *testing.T) {
+	dir := fs.NewDir(t, t.Name(),
+		fs.WithFile(ContextFileName, "# comment\n\nproject-context\n"),
+		fs.WithDir("nested",
+			fs.WithDir("empty"),
+			fs.WithDir("tool-versions",
+				fs.WithFile(".tool-versions", "golang 1.23.0\ndocker-context tool-context\n"),
+			),
+			fs.WithDir("nested-context",
+				fs.WithFile(ContextFileName, "nested-context # comment"),
+			),
+			fs.WithDir("empty-file",
+				fs.WithFile(ContextFileName, "\n# no context\n"),
+			),
+		),
+	)
+
+	tests := []struct {
+		dir          string
+		expectedName string
+		expectedPath string
+	}{
+		{
+			dir:          ".",
+			expectedName: "project-context",
+			expectedPath: ContextFileName,
+		},
+		{
+			dir:          "nested/empty",
+			expectedName: "project-context",
+			expectedPath: ContextFileName,
+		},
+		{
+			// ".tool-versions" files are not used to set the context.
+			dir:          "nested/tool-versions",
+			expectedName: "project-context",
+			expectedPath: ContextFileName,
+		},
+		{
+			dir:          "nested/nested-context",
+			expectedName: "nested-context",
+			expectedPath: "nested/nested-context/" + ContextFileName,
+		},
+		{
+			dir:          "nested/empty-file",
+			expectedName: "project-context",
+			expectedPath: ContextFileName,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.dir, func(t *testing.T) {
+			name, path := FindProjectContext(dir.Join(tc.dir))
+			assert.Check(t, is.Equal(name, tc.expectedName))
+			assert.Check(t, is.Equal(path, filepath.Join(dir.Path(), tc.expectedPath)))
+		})
+	}
+}
+
+func TestResolveContextName(t *testing.T) {
+	dir := fs.NewDir(t, t.Name(), fs.WithFile(ContextFileName, "project-context\n"))
+	wd, err := os.Getwd()
+	assert.NilError(t, err)
+	assert.NilError(t, os.Chdir(dir.Path()))
+	t.Cleanup(func() { assert.NilError(t, os.Chdir(wd)) })
+
+	cfg := &configfile.ConfigFile{Filename: "/home/user/.docker/config.json", CurrentContext: "config-context"}
+
+	tests := []struct {
+		doc            string
+		opts           *cliflags.ClientOptions
+		env            map[string]string
+		noProjectFile  bool
+		expectedName   string
+		expectedSource string
+	}{
+		{
+			doc:            "context flag",
+			opts:           &cliflags.ClientOptions{Context: "flag-context"},
+			env:            map[string]string{EnvOverrideContext: "env-context"},
+			expectedName:   "flag-context",
+			expectedSource: "--context flag",
+		},
+		{
+			doc:            "host flag",
+			opts:           &cliflags.ClientOptions{Hosts: []string{"tcp://127.0.0.1:2375"}},
+			expectedName:   DefaultContextName,
+			expectedSource: "--host flag",
+		},
+		{
+			doc:            "DOCKER_HOST env-var",
+			env:            map[string]string{"DOCKER_HOST": "tcp://127.0.0.1:2375", EnvOverrideContext: "env-context"},
+			expectedName:   DefaultContextName,
+			expectedSource: "DOCKER_HOST environment variable",
+		},
+		{
+			doc:            "DOCKER_CONTEXT env-var",
+			env:            map[string]string{EnvOverrideContext: "env-context"},
+			expectedName:   "env-context",
+			expectedSource: "DOCKER_CONTEXT environment variable",
+		},
+		{
+			doc:            "project file",
+			expectedName:   "project-context",
+			expectedSource: dir.Join(ContextFileName),
+		},
+		{
+			doc:            "config file",
+			noProjectFile:  true,
+			expectedName:   "config-context",
+			expectedSource: `"currentContext" in /home/user/.docker/config.json`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.doc, func(t *testing.T) {
+			t.Setenv("DOCKER_HOST", "")
+			t.Setenv(EnvOverrideContext, "")
+			for k, v := range tc.env {
+				t.Setenv(k, v)
+			}
+			if tc.noProjectFile {
+				assert.NilError(t, os.Chdir(t.TempDir()))
+				t.Cleanup(func() { assert.NilError(t, os.Chdir(dir.Path())) })
+			}
+			name, source := resolveContextName(tc.opts, cfg)
+			assert.Check(t, is.Equal(name, tc.expectedName))
+			assert.Check(t, is.Equal(source, tc.expectedSource))
+		})
+	}
+
+	t.Run("no context configured", func(t *testing.T) {
+		assert.NilError(t, os.Chdir(t.TempDir()))
+		t.Cleanup(func() { assert.NilError(t, os.Chdir(dir.Path())) })
+		name, source := resolveContextName(&cliflags.ClientOptions{}, &configfile.ConfigFile{})
+		assert.Check(t, is.Equal(name, DefaultContextName))
+		assert.Check(t, is.Equal(source, "no context configured"))
+	})
+}
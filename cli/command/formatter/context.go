@@ -1,12 +1,25 @@
 package formatter
 
+import "time"
+
 const (
 	// ClientContextTableFormat is the default client context format.
 	ClientContextTableFormat = "table {{.Name}}{{if .Current}} *{{end}}\t{{.Description}}\t{{.DockerEndpoint}}\t{{.Error}}"
 
+	// ClientContextCheckTableFormat is the default client context format
+	// when the Docker endpoints of the contexts are checked.
+	ClientContextCheckTableFormat = "table {{.Name}}{{if .Current}} *{{end}}\t{{.DockerEndpoint}}\t{{.Status}}\t{{.ServerVersion}}\t{{.APIVersion}}\t{{.Platform}}\t{{.Latency}}\t{{.Error}}"
+
 	dockerEndpointHeader = "DOCKER ENDPOINT"
+	serverVersionHeader  = "SERVER VERSION"
+	apiVersionHeader     = "API VERSION"
+	latencyHeader        = "LATENCY"
 	quietContextFormat   = "{{.Name}}"
 
+	// Values of the Status of a checked context.
+	contextReachable   = "reachable"
+	contextUnreachable = "unreachable"
+
 	maxErrLength = 45
 )
 
@@ -21,6 +34,15 @@ func NewClientContextFormat(source string, quiet bool) Format {
 	return Format(source)
 }
 
+// NewClientContextCheckFormat returns a Format for rendering contexts of
+// which the Docker endpoints were checked.
+func NewClientContextCheckFormat(source string, quiet bool) Format {
+	if !quiet && source == TableFormatKey {
+		return ClientContextCheckTableFormat
+	}
+	return NewClientContextFormat(source, quiet)
+}
+
 // ClientContext is a context for display
 type ClientContext struct {
 	Name           string
@@ -28,13 +50,33 @@ type ClientContext struct {
 	DockerEndpoint string
 	Current        bool
 	Error          string
+
+	// Check is the result of checking the Docker endpoint of the context,
+	// or nil if the endpoint was not checked.
+	Check *ClientContextCheck
+}
+
+// ClientContextCheck is the result of checking the Docker endpoint of a
+// context. Reachable is false if the endpoint could not be reached, in which
+// case the error is set in the Error of the context.
+type ClientContextCheck struct {
+	Reachable     bool
+	ServerVersion string
+	APIVersion    string
+	Os            string
+	Arch          string
+	Latency       time.Duration
 }
 
 // ClientContextWrite writes formatted contexts using the Context
 func ClientContextWrite(ctx Context, contexts []*ClientContext) error {
 	render := func(format func(subContext SubContext) error) error {
 		for _, context := range contexts {
-			if err := format(&clientContextContext{c: context}); err != nil {
+			var subContext SubContext = &clientContextContext{c: context}
+			if context.Check != nil {
+				subContext = &clientContextCheckContext{clientContextContext{c: context}}
+			}
+			if err := format(subContext); err != nil {
 				return err
 			}
 		}
@@ -55,6 +97,11 @@ func newClientContextContext() *clientContextContext {
 		"Description":    DescriptionHeader,
 		"DockerEndpoint": dockerEndpointHeader,
 		"Error":          ErrorHeader,
+		"Status":         StatusHeader,
+		"ServerVersion":  serverVersionHeader,
+		"APIVersion":     apiVersionHeader,
+		"Platform":       platformHeader,
+		"Latency":        latencyHeader,
 	}
 	return &ctx
 }
@@ -84,3 +131,53 @@ func (c *clientContextContext) Error() string {
 	// TODO(thaJeztah) add "--no-trunc" option to context ls and set default to 30 cols to match "docker service ps"
 	return Ellipsis(c.c.Error, maxErrLength)
 }
+
+// clientContextCheckContext is used for contexts of which the Docker endpoint
+// was checked, to only include the results of the check in the JSON output
+// in that case.
+type clientContextCheckContext struct {
+	clientContextContext
+}
+
+func (c *clientContextCheckContext) MarshalJSON() ([]byte, error) {
+	return MarshalJSON(c)
+}
+
+// Status returns whether the Docker endpoint could be reached.
+func (c *clientContextCheckContext) Status() string {
+	if c.c.Check.Reachable {
+		return contextReachable
+	}
+	return contextUnreachable
+}
+
+func (c *clientContextCheckContext) ServerVersion() string {
+	return c.c.Check.ServerVersion
+}
+
+func (c *clientContextCheckContext) APIVersion() string {
+	return c.c.Check.APIVersion
+}
+
+// Platform returns the OS and architecture of the daemon, for example,
+// "linux/amd64".
+func (c *clientContextCheckContext) Platform() string {
+	if c.c.Check.Os == "" {
+		return ""
+	}
+	if c.c.Check.Arch == "" {
+		return c.c.Check.Os
+	}
+	return c.c.Check.Os + "/" + c.c.Check.Arch
+}
+
+// Latency returns the round-trip time of a request to the Docker endpoint.
+func (c *clientContextCheckContext) Latency() string {
+	if !c.c.Check.Reachable {
+		return ""
+	}
+	if c.c.Check.Latency < time.Millisecond {
+		return c.c.Check.Latency.Round(time.Microsecond).String()
+	}
+	return c.c.Check.Latency.Round(time.Millisecond).String()
+}
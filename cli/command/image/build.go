@@ -27,6 +27,7 @@
 	"github.com/moby/moby/api/types/container"
 	registrytypes "github.com/moby/moby/api/types/registry"
 	"github.com/moby/moby/client"
+	"github.com/opencontainers/go-digest"
 	"github.com/spf13/cobra"
 )
 
@@ -61,6 +62,9 @@ type buildOptions struct {
 	target         string
 	imageIDFile    string
 	platform       string
+	contextReport  bool
+	maxContextSize opts.MemBytes
+	contextCache   bool
 }
 
 // dockerfileFromStdin returns true when the user specified that the Dockerfile
@@ -153,6 +157,10 @@ func newBuildCommand(dockerCLI command.Cli) *cobra.Command {
 	flags.StringVar(&options.platform, "platform", os.Getenv("DOCKER_DEFAULT_PLATFORM"), "Set platform if server is multi-platform capable")
 	flags.SetAnnotation("platform", "version", []string{"1.38"})
 
+	flags.BoolVar(&options.contextReport, "context-report", false, "Print the largest files and directories in the build context, and check .dockerignore")
+	flags.Var(&options.maxContextSize, "max-context-size", "Fail if the build context is larger than this size")
+	flags.BoolVar(&options.contextCache, "context-cache", false, "Skip sending the build context if it and the options did not change")
+
 	flags.BoolVar(&options.squash, "squash", false, "Squash newly built layers into a single new layer")
 	flags.SetAnnotation("squash", "experimental", nil)
 	flags.SetAnnotation("squash", "version", []string{"1.25"})
@@ -188,6 +196,9 @@ func runBuild(ctx context.Context, dockerCli command.Cli, options buildOptions)
 		progBuff      io.Writer
 		buildBuff     io.Writer
 		remote        string
+		cache         *contextCache
+		cacheKey      digest.Digest
+		cacheHit      bool
 	)
 
 	contextType, err := build.DetectContextType(options.context)
@@ -245,6 +256,14 @@ func runBuild(ctx context.Context, dockerCli command.Cli, options buildOptions)
 			_ = os.RemoveAll(tempDir)
 		}()
 		contextDir = tempDir
+	case build.ContextTypeOCI:
+		buildCtx, relDockerfile, err = build.GetContextFromOCI(ctx, progBuff, command.NewRegistryClient(dockerCli, false), options.context, options.dockerfileName)
+		if err != nil {
+			if options.quiet {
+				_, _ = fmt.Fprintln(dockerCli.Err(), progBuff)
+			}
+			return err
+		}
 	case build.ContextTypeRemote:
 		buildCtx, relDockerfile, err = build.GetContextFromURL(progBuff, options.context, options.dockerfileName)
 		if err != nil && options.quiet {
@@ -254,6 +273,13 @@ func runBuild(ctx context.Context, dockerCli command.Cli, options buildOptions)
 		return fmt.Errorf("unable to prepare context: path %q not found", options.context)
 	}
 
+	if buildCtx != nil && (options.contextReport || options.maxContextSize > 0) {
+		return errors.New("--context-report and --max-context-size require a local directory or Git repository as build context")
+	}
+	if options.contextCache && (buildCtx != nil || options.dockerfileFromStdin()) {
+		return errors.New("--context-cache requires a local directory or Git repository as build context, and cannot be used with a Dockerfile from stdin")
+	}
+
 	// read from a directory into tar archive
 	if buildCtx == nil {
 		excludes, err := build.ReadDockerignore(contextDir)
@@ -268,18 +294,57 @@ func runBuild(ctx context.Context, dockerCli command.Cli, options buildOptions)
 		// And canonicalize dockerfile name to a platform-independent one
 		relDockerfile = filepath.ToSlash(relDockerfile)
 
+		patterns := excludes
 		excludes = build.TrimBuildFilesFromExcludes(excludes, relDockerfile, options.dockerfileFromStdin())
-		buildCtx, err = archive.TarWithOptions(contextDir, &archive.TarOptions{
-			ExcludePatterns: excludes,
-			ChownOpts:       &archive.ChownOpts{UID: 0, GID: 0},
-		})
-		if err != nil {
-			return err
+		if options.contextReport || options.maxContextSize > 0 {
+			if err := checkContext(dockerCli.Err(), contextDir, patterns, excludes, options); err != nil {
+				return err
+			}
+		}
+		if options.contextCache {
+			cache = loadContextCache(dockerCli, options.context)
+			var contextDigest, dockerfileDigest digest.Digest
+			contextDigest, cache.Files, err = build.DigestContext(contextDir, excludes, cache.Files)
+			if err != nil {
+				return fmt.Errorf("checking context: %w", err)
+			}
+			if dockerfileCtx != nil {
+				// Dockerfile is outside build-context
+				dt, err := os.ReadFile(options.dockerfileName)
+				if err != nil {
+					return fmt.Errorf("unable to read Dockerfile: %w", err)
+				}
+				dockerfileDigest = digest.FromBytes(dt)
+			}
+			buildOpts := imageBuildOptions(dockerCli, options)
+			buildOpts.Dockerfile = relDockerfile
+			cacheKey, err = contextCacheKey(contextDigest, patterns, dockerfileDigest, buildOpts)
+			if err != nil {
+				return err
+			}
+			if cache.Key == cacheKey && cache.ImageID != "" && !options.noCache && !options.pull {
+				buildCtx, cacheHit, err = cachedImageContext(ctx, dockerCli, cache.ImageID)
+				if err != nil {
+					return err
+				}
+				if cacheHit {
+					_, _ = fmt.Fprintf(progBuff, "Build context and options unchanged, using image %s from a previous build\n", cache.ImageID)
+				}
+			}
+		}
+		if !cacheHit {
+			buildCtx, err = archive.TarWithOptions(contextDir, &archive.TarOptions{
+				ExcludePatterns: excludes,
+				ChownOpts:       &archive.ChownOpts{UID: 0, GID: 0},
+			})
+			if err != nil {
+				return err
+			}
 		}
 	}
 
 	// replace Dockerfile if it was added from stdin or a file outside the build-context, and there is archive context
-	if dockerfileCtx != nil && buildCtx != nil {
+	if dockerfileCtx != nil && buildCtx != nil && !cacheHit {
 		buildCtx, relDockerfile, err = build.AddDockerfileToBuildContext(dockerfileCtx, buildCtx)
 		if err != nil {
 			return err
@@ -311,7 +376,7 @@ func runBuild(ctx context.Context, dockerCli command.Cli, options buildOptions)
 	}
 
 	configFile := dockerCli.ConfigFile()
-	creds, _ := configFile.GetAllCredentials()
+	creds, _ := command.GetAllCredentials(configFile)
 	authConfigs := make(map[string]registrytypes.AuthConfig, len(creds))
 	for k, authConfig := range creds {
 		authConfigs[k] = registrytypes.AuthConfig{
@@ -329,6 +394,9 @@ func runBuild(ctx context.Context, dockerCli command.Cli, options buildOptions)
 	buildOpts.Dockerfile = relDockerfile
 	buildOpts.AuthConfigs = authConfigs
 	buildOpts.RemoteContext = remote
+	if cacheHit {
+		buildOpts = cachedImageBuildOptions(buildOpts)
+	}
 
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
@@ -375,6 +443,14 @@ func runBuild(ctx context.Context, dockerCli command.Cli, options buildOptions)
 		_, _ = fmt.Fprint(dockerCli.Out(), imageID)
 	}
 
+	if cache != nil && imageID != "" {
+		cache.Key = cacheKey
+		cache.ImageID = strings.TrimSpace(imageID)
+		if err := cache.save(); err != nil {
+			_, _ = fmt.Fprintln(dockerCli.Err(), "WARNING: failed to update the build context cache:", err)
+		}
+	}
+
 	if options.imageIDFile != "" {
 		if imageID == "" {
 			return fmt.Errorf("server did not provide an image ID. Cannot write %s", options.imageIDFile)
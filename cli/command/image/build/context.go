@@ -41,6 +41,27 @@
 // can be read and returns an error if some files can't be read
 // symlinks which point to non-existing files don't trigger an error
 func ValidateContextDirectory(srcPath string, excludes []string) error {
+	return walkContext(srcPath, excludes, func(filePath string, _ string, f os.FileInfo) error {
+		// skip checking if symlinks point to non-existing files, such symlinks can be useful
+		// also skip named pipes, because they hanging on open
+		if f.Mode()&(os.ModeSymlink|os.ModeNamedPipe) != 0 {
+			return nil
+		}
+
+		if !f.IsDir() {
+			currentFile, err := os.Open(filePath)
+			if err != nil && os.IsPermission(err) {
+				return fmt.Errorf("no permission to read from '%s'", filePath)
+			}
+			_ = currentFile.Close()
+		}
+		return nil
+	})
+}
+
+// walkContext walks the files and directories in the context directory that
+// are not excluded, and calls fn for each of them.
+func walkContext(srcPath string, excludes []string, fn func(filePath, relFilePath string, f os.FileInfo) error) error {
 	contextRoot, err := getContextRoot(srcPath)
 	if err != nil {
 		return err
@@ -63,32 +84,36 @@ func ValidateContextDirectory(srcPath string, excludes []string) error {
 		}
 
 		// skip this directory/file if it's not in the path, it won't get added to the context
-		if relFilePath, err := filepath.Rel(contextRoot, filePath); err != nil {
+		relFilePath, err := filepath.Rel(contextRoot, filePath)
+		if err != nil {
 			return err
 		} else if skip, err := filepathMatches(pm, relFilePath); err != nil {
 			return err
 		} else if skip {
-			if f.IsDir() {
+			if f.IsDir() && !hasExclusionUnder(pm, relFilePath) {
 				return filepath.SkipDir
 			}
 			return nil
 		}
+		return fn(filePath, relFilePath, f)
+	})
+}
 
-		// skip checking if symlinks point to non-existing files, such symlinks can be useful
-		// also skip named pipes, because they hanging on open
-		if f.Mode()&(os.ModeSymlink|os.ModeNamedPipe) != 0 {
-			return nil
+// hasExclusionUnder returns whether an exclusion pattern (e.g. "!dir/file")
+// may re-include files under dir, in which case the excluded directory must
+// still be walked. It mirrors the logic used by go-archive when sending the
+// context.
+func hasExclusionUnder(pm *patternmatcher.PatternMatcher, dir string) bool {
+	if !pm.Exclusions() {
+		return false
+	}
+	dirSlash := dir + string(filepath.Separator)
+	for _, pat := range pm.Patterns() {
+		if pat.Exclusion() && strings.HasPrefix(pat.String()+string(filepath.Separator), dirSlash) {
+			return true
 		}
-
-		if !f.IsDir() {
-			currentFile, err := os.Open(filePath)
-			if err != nil && os.IsPermission(err) {
-				return fmt.Errorf("no permission to read from '%s'", filePath)
-			}
-			_ = currentFile.Close()
-		}
-		return nil
-	})
+	}
+	return false
 }
 
 func filepathMatches(matcher *patternmatcher.PatternMatcher, file string) (bool, error) {
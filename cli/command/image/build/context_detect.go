@@ -3,6 +3,7 @@
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/docker/cli/cli/command/image/build/internal/urlutil"
 )
@@ -15,6 +16,7 @@
 	ContextTypeLocal  ContextType = "local"  // ContextTypeLocal indicates that the build-context is a local directory.
 	ContextTypeRemote ContextType = "remote" // ContextTypeRemote indicates that the build-context is a remote URL.
 	ContextTypeGit    ContextType = "git"    // ContextTypeGit indicates that the build-context is a GIT URL.
+	ContextTypeOCI    ContextType = "oci"    // ContextTypeOCI indicates that the build-context is an OCI artifact in a registry ("oci://<reference>").
 )
 
 // DetectContextType detects the type (source) of the build-context.
@@ -22,6 +24,8 @@ func DetectContextType(specifiedContext string) (ContextType, error) {
 	switch {
 	case specifiedContext == "-":
 		return ContextTypeStdin, nil
+	case strings.HasPrefix(specifiedContext, ociContextPrefix):
+		return ContextTypeOCI, nil
 	case isLocalDir(specifiedContext):
 		return ContextTypeLocal, nil
 	case urlutil.IsGitURL(specifiedContext):
@@ -0,0 +1,73 @@
+package build
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+)
+
+// FileDigest is the digest of a file in the build context, and the size and
+// modification time of the file when the digest was calculated.
+type FileDigest struct {
+	Size    int64         `json:"size"`
+	ModTime time.Time     `json:"modTime"`
+	Digest  digest.Digest `json:"digest"`
+}
+
+// DigestContext returns a digest of the files and directories in the
+// context directory that are not excluded, including their paths and file
+// modes. Digests of files are taken from previous if the size and
+// modification time of the file did not change. It returns the digests of
+// all files in the context, which can be passed as previous to a next call.
+func DigestContext(srcPath string, excludes []string, previous map[string]FileDigest) (digest.Digest, map[string]FileDigest, error) {
+	digester := digest.Canonical.Digester()
+	files := make(map[string]FileDigest)
+	err := walkContext(srcPath, excludes, func(filePath, relFilePath string, f os.FileInfo) error {
+		relFilePath = filepath.ToSlash(relFilePath)
+
+		var content string
+		switch {
+		case f.Mode().IsRegular():
+			fd, ok := previous[relFilePath]
+			if !ok || fd.Size != f.Size() || !fd.ModTime.Equal(f.ModTime()) {
+				dgst, err := digestFile(filePath)
+				if err != nil {
+					return err
+				}
+				fd = FileDigest{Size: f.Size(), ModTime: f.ModTime(), Digest: dgst}
+			}
+			files[relFilePath] = fd
+			content = fd.Digest.String()
+		case f.Mode()&os.ModeSymlink != 0:
+			target, err := os.Readlink(filePath)
+			if err != nil {
+				return err
+			}
+			content = target
+		}
+		_, err := fmt.Fprintf(digester.Hash(), "%q %s %q\n", relFilePath, f.Mode(), content)
+		return err
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	return digester.Digest(), files, nil
+}
+
+func digestFile(filePath string) (digest.Digest, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	digester := digest.Canonical.Digester()
+	if _, err := io.Copy(digester.Hash(), f); err != nil {
+		return "", err
+	}
+	return digester.Digest(), nil
+}
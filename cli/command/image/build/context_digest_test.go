@@ -0,0 +1,72 @@
+package build
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/fs"
+)
+
+func TestDigestContext(t *testing.T) {
+	dir := fs.NewDir(t, t.Name(),
+		fs.WithFile("Dockerfile", "FROM scratch\n"),
+		fs.WithFile("ignored.log", "log"),
+		fs.WithDir("src", fs.WithFile("main.go", "package main\n")),
+	)
+	excludes := []string{"*.log"}
+
+	dgst, files, err := DigestContext(dir.Path(), excludes, nil)
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(files, 2))
+	assert.Check(t, is.Contains(files, "src/main.go"))
+
+	// Unchanged context, and changes to excluded files don't change the digest.
+	assert.NilError(t, os.WriteFile(filepath.Join(dir.Path(), "ignored.log"), []byte("more logs"), 0o644))
+	dgst2, _, err := DigestContext(dir.Path(), excludes, files)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(dgst2, dgst))
+
+	// Digests of files that did not change (by size and modification time)
+	// are re-used from the previous digests.
+	fd := files["src/main.go"]
+	fd.Digest = "sha256:0000000000000000000000000000000000000000000000000000000000000000"
+	files["src/main.go"] = fd
+	_, files2, err := DigestContext(dir.Path(), excludes, files)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(files2["src/main.go"].Digest, fd.Digest))
+
+	assert.NilError(t, os.WriteFile(filepath.Join(dir.Path(), "src", "main.go"), []byte("package main\n\nfunc main() {}\n"), 0o644))
+	dgst3, files3, err := DigestContext(dir.Path(), excludes, files)
+	assert.NilError(t, err)
+	assert.Check(t, dgst3 != dgst)
+	assert.Check(t, files3["src/main.go"].Digest != fd.Digest)
+}
+
+func TestDigestContextReincluded(t *testing.T) {
+	dir := fs.NewDir(t, t.Name(),
+		fs.WithFile("Dockerfile", "FROM scratch\n"),
+		fs.WithDir("vendor",
+			fs.WithFile("ignored.txt", "ignored"),
+			fs.WithDir("keep", fs.WithFile("a.txt", "a")),
+		),
+	)
+	excludes := []string{"vendor", "!vendor/keep"}
+
+	dgst, files, err := DigestContext(dir.Path(), excludes, nil)
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(files, 2))
+	assert.Check(t, is.Contains(files, "vendor/keep/a.txt"))
+
+	assert.NilError(t, os.WriteFile(filepath.Join(dir.Path(), "vendor", "ignored.txt"), []byte("changed"), 0o644))
+	dgst2, _, err := DigestContext(dir.Path(), excludes, files)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(dgst2, dgst))
+
+	assert.NilError(t, os.WriteFile(filepath.Join(dir.Path(), "vendor", "keep", "a.txt"), []byte("changed"), 0o644))
+	dgst3, _, err := DigestContext(dir.Path(), excludes, files)
+	assert.NilError(t, err)
+	assert.Check(t, dgst3 != dgst)
+}
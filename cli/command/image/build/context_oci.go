@@ -0,0 +1,54 @@
+package build
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/distribution/reference"
+	"github.com/docker/cli/internal/registryclient"
+	"github.com/docker/distribution"
+	"github.com/moby/moby/api/pkg/progress"
+	"github.com/moby/moby/api/pkg/streamformatter"
+)
+
+// ociContextPrefix is the prefix of a build-context that is an OCI artifact
+// in a registry.
+const ociContextPrefix = "oci://"
+
+// GetContextFromOCI uses an artifact in a registry ("oci://<reference>") as
+// context for a `docker build`. The artifact must have a single layer, which
+// is a (compressed) tar archive, or a Dockerfile. Returns the tar archive used
+// for the context and a path of the dockerfile inside the tar.
+func GetContextFromOCI(ctx context.Context, out io.Writer, registryClient registryclient.RegistryClient, ociRef, dockerfileName string) (io.ReadCloser, string, error) {
+	ref, err := reference.ParseNormalizedNamed(strings.TrimPrefix(ociRef, ociContextPrefix))
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid build context %s: %w", ociRef, err)
+	}
+	ref = reference.TagNameOnly(ref)
+
+	m, err := registryClient.GetManifest(ctx, ref)
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to fetch build context %s: %w", ociRef, err)
+	}
+	var layers []distribution.Descriptor
+	switch {
+	case m.OCIManifest != nil:
+		layers = m.OCIManifest.Layers
+	case m.SchemaV2Manifest != nil:
+		layers = m.SchemaV2Manifest.Layers
+	}
+	if len(layers) != 1 {
+		return nil, "", fmt.Errorf("unable to use %s as build context: expected an artifact with a single layer, found %d layers", ociRef, len(layers))
+	}
+
+	rc, err := registryClient.OpenBlob(ctx, ref, layers[0].Digest)
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to download build context %s: %w", ociRef, err)
+	}
+	progressOutput := streamformatter.NewProgressOutput(out)
+	progReader := progress.NewProgressReader(rc, progressOutput, layers[0].Size, "", "Downloading build context from "+reference.FamiliarString(ref))
+
+	return GetContextFromReader(newReadCloserWrapper(progReader, rc.Close), dockerfileName)
+}
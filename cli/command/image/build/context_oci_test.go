@@ -0,0 +1,86 @@
+package build
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/distribution/reference"
+	manifesttypes "github.com/docker/cli/cli/manifest/types"
+	"github.com/docker/cli/internal/registryclient"
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/manifest/ocischema"
+	"github.com/moby/go-archive"
+	"github.com/moby/go-archive/compression"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/fs"
+)
+
+type fakeRegistryClient struct {
+	registryclient.RegistryClient
+	manifest *ocischema.DeserializedManifest
+	blobs    map[digest.Digest][]byte
+}
+
+func (c *fakeRegistryClient) GetManifest(_ context.Context, ref reference.Named) (manifesttypes.ImageManifest, error) {
+	return manifesttypes.NewOCIImageManifest(ref, ocispec.Descriptor{}, c.manifest), nil
+}
+
+func (c *fakeRegistryClient) OpenBlob(_ context.Context, _ reference.Named, dgst digest.Digest) (io.ReadCloser, error) {
+	return io.NopCloser(bytes.NewReader(c.blobs[dgst])), nil
+}
+
+func newFakeArtifact(t *testing.T, layers ...[]byte) *fakeRegistryClient {
+	t.Helper()
+	c := &fakeRegistryClient{blobs: make(map[digest.Digest][]byte)}
+	descs := make([]distribution.Descriptor, 0, len(layers))
+	for _, l := range layers {
+		dgst := digest.FromBytes(l)
+		c.blobs[dgst] = l
+		descs = append(descs, distribution.Descriptor{MediaType: ocispec.MediaTypeImageLayerGzip, Digest: dgst, Size: int64(len(l))})
+	}
+	var err error
+	c.manifest, err = ocischema.FromStruct(ocischema.Manifest{
+		Versioned: ocischema.SchemaVersion,
+		Config:    distribution.Descriptor{MediaType: ocispec.MediaTypeEmptyJSON, Digest: ocispec.DescriptorEmptyJSON.Digest, Size: 2},
+		Layers:    descs,
+	})
+	assert.NilError(t, err)
+	return c
+}
+
+func TestGetContextFromOCI(t *testing.T) {
+	dir := fs.NewDir(t, t.Name(), fs.WithFile("Dockerfile.ci", dockerfileContents))
+	tarball, err := archive.Tar(dir.Path(), compression.Gzip)
+	assert.NilError(t, err)
+	layer, err := io.ReadAll(tarball)
+	assert.NilError(t, err)
+
+	var out bytes.Buffer
+	rc, relDockerfile, err := GetContextFromOCI(context.Background(), &out, newFakeArtifact(t, layer), "oci://registry.example.com/contexts/app:v1", "Dockerfile.ci")
+	assert.NilError(t, err)
+	defer rc.Close()
+	assert.Check(t, is.Equal(relDockerfile, "Dockerfile.ci"))
+	content, err := io.ReadAll(rc)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(content, layer))
+	assert.Check(t, is.Contains(out.String(), "Downloading build context from registry.example.com/contexts/app:v1"))
+}
+
+func TestGetContextFromOCIErrors(t *testing.T) {
+	_, _, err := GetContextFromOCI(context.Background(), io.Discard, newFakeArtifact(t, []byte("a"), []byte("b")), "oci://example.com/ctx", "")
+	assert.Check(t, is.Error(err, "unable to use oci://example.com/ctx as build context: expected an artifact with a single layer, found 2 layers"))
+
+	_, _, err = GetContextFromOCI(context.Background(), io.Discard, newFakeArtifact(t), "oci://Invalid", "")
+	assert.Check(t, is.ErrorContains(err, "invalid build context oci://Invalid"))
+}
+
+func TestDetectContextTypeOCI(t *testing.T) {
+	contextType, err := DetectContextType("oci://registry.example.com/ctx:v1")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(contextType, ContextTypeOCI))
+}
@@ -0,0 +1,167 @@
+package build
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/moby/patternmatcher"
+)
+
+// PathSize is the total size of the files in a path of the build context.
+type PathSize struct {
+	Path string
+	Size int64
+}
+
+// ContextUsage describes the files that are sent as part of a build context.
+type ContextUsage struct {
+	// Files is the number of files in the context.
+	Files int
+	// Size is the total size of the files in the context.
+	Size int64
+	// LargestDirs and LargestFiles are the largest directories and files
+	// in the context, largest first.
+	LargestDirs  []PathSize
+	LargestFiles []PathSize
+}
+
+// GetContextUsage returns the number and size of the files in the context
+// directory that are not excluded, and the (at most) limit largest files and
+// directories.
+func GetContextUsage(srcPath string, excludes []string, limit int) (ContextUsage, error) {
+	var (
+		usage ContextUsage
+		files []PathSize
+		dirs  = make(map[string]int64)
+	)
+	err := walkContext(srcPath, excludes, func(_, relFilePath string, f os.FileInfo) error {
+		if !f.Mode().IsRegular() {
+			return nil
+		}
+		relFilePath = filepath.ToSlash(relFilePath)
+		usage.Files++
+		usage.Size += f.Size()
+		files = append(files, PathSize{Path: relFilePath, Size: f.Size()})
+		for dir := filepath.ToSlash(filepath.Dir(relFilePath)); dir != "."; dir = filepath.ToSlash(filepath.Dir(dir)) {
+			dirs[dir] += f.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		return ContextUsage{}, err
+	}
+
+	usage.LargestFiles = largest(files, limit)
+	dirSizes := make([]PathSize, 0, len(dirs))
+	for dir, size := range dirs {
+		dirSizes = append(dirSizes, PathSize{Path: dir, Size: size})
+	}
+	usage.LargestDirs = largest(dirSizes, limit)
+	return usage, nil
+}
+
+func largest(paths []PathSize, limit int) []PathSize {
+	sort.Slice(paths, func(i, j int) bool {
+		if paths[i].Size != paths[j].Size {
+			return paths[i].Size > paths[j].Size
+		}
+		return paths[i].Path < paths[j].Path
+	})
+	if len(paths) > limit {
+		paths = paths[:limit]
+	}
+	return paths
+}
+
+// LintDockerignore checks the patterns of a .dockerignore file against the
+// files in the context directory. It returns a warning for each pattern that
+// matches nothing, and for each pattern that has no effect because all files
+// it matches are re-included by a later "!" pattern.
+func LintDockerignore(srcPath string, patterns []string) ([]string, error) {
+	contextRoot, err := getContextRoot(srcPath)
+	if err != nil {
+		return nil, err
+	}
+	pm, err := patternmatcher.New(patterns)
+	if err != nil {
+		return nil, err
+	}
+
+	// Match each pattern on its own, so that we know which patterns match
+	// a path, and which one of them decides whether the path is excluded.
+	rules := pm.Patterns()
+	matchers := make([]*patternmatcher.PatternMatcher, len(rules))
+	for i, rule := range rules {
+		matchers[i], err = patternmatcher.New([]string{rule.String()})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var (
+		matched    = make([]bool, len(rules))
+		effective  = make([]bool, len(rules))
+		redundant  = make([]bool, len(rules))
+		shadowedBy = make([]int, len(rules))
+		matches    = make([]int, 0, len(rules))
+	)
+	for i := range shadowedBy {
+		shadowedBy[i] = -1
+	}
+	err = filepath.Walk(contextRoot, func(filePath string, _ os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		relFilePath, err := filepath.Rel(contextRoot, filePath)
+		if err != nil || relFilePath == "." {
+			return err
+		}
+		matches = matches[:0]
+		for i, m := range matchers {
+			if ok, err := m.MatchesOrParentMatches(relFilePath); err != nil {
+				return err
+			} else if ok {
+				matched[i] = true
+				matches = append(matches, i)
+			}
+		}
+		if len(matches) == 0 {
+			return nil
+		}
+		// The last pattern that matches decides whether the path is excluded.
+		last := matches[len(matches)-1]
+		effective[last] = true
+		for _, i := range matches[:len(matches)-1] {
+			switch {
+			case !rules[last].Exclusion():
+				redundant[i] = true
+			case !rules[i].Exclusion() && shadowedBy[i] == -1:
+				shadowedBy[i] = last
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var warnings []string
+	for i, rule := range rules {
+		switch {
+		case !matched[i]:
+			warnings = append(warnings, fmt.Sprintf("pattern %q does not match any files", patternString(rule)))
+		case !effective[i] && !redundant[i] && shadowedBy[i] != -1:
+			warnings = append(warnings, fmt.Sprintf("pattern %q has no effect: all files it matches are re-included by %q", patternString(rule), patternString(rules[shadowedBy[i]])))
+		}
+	}
+	return warnings, nil
+}
+
+func patternString(p *patternmatcher.Pattern) string {
+	if p.Exclusion() {
+		return "!" + filepath.ToSlash(p.String())
+	}
+	return filepath.ToSlash(p.String())
+}
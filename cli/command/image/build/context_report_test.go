@@ -0,0 +1,79 @@
+package build
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/fs"
+)
+
+func TestGetContextUsage(t *testing.T) {
+	dir := fs.NewDir(t, t.Name(),
+		fs.WithFile("Dockerfile", "FROM scratch\n"),
+		fs.WithDir("src",
+			fs.WithFile("main.go", "package main\n"),
+			fs.WithDir("assets", fs.WithFile("logo.png", "0123456789012345678901234567890123456789")),
+		),
+		fs.WithDir("node_modules", fs.WithFile("big.js", "0123456789012345678901234567890123456789012345678901234567890123456789")),
+	)
+
+	usage, err := GetContextUsage(dir.Path(), []string{"node_modules"}, 2)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(usage.Files, 3))
+	assert.Check(t, is.Equal(usage.Size, int64(13+13+40)))
+	assert.Check(t, is.DeepEqual(usage.LargestDirs, []PathSize{
+		{Path: "src", Size: 53},
+		{Path: "src/assets", Size: 40},
+	}))
+	assert.Check(t, is.DeepEqual(usage.LargestFiles, []PathSize{
+		{Path: "src/assets/logo.png", Size: 40},
+		{Path: "Dockerfile", Size: 13},
+	}))
+}
+
+func TestGetContextUsageReincluded(t *testing.T) {
+	dir := fs.NewDir(t, t.Name(),
+		fs.WithFile("Dockerfile", "FROM scratch\n"),
+		fs.WithDir("vendor",
+			fs.WithFile("big.bin", "0123456789012345678901234567890123456789"),
+			fs.WithDir("keep", fs.WithFile("a.txt", "0123456789")),
+		),
+	)
+
+	usage, err := GetContextUsage(dir.Path(), []string{"vendor", "!vendor/keep"}, 2)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(usage.Files, 2))
+	assert.Check(t, is.Equal(usage.Size, int64(13+10)))
+	assert.Check(t, is.DeepEqual(usage.LargestFiles, []PathSize{
+		{Path: "Dockerfile", Size: 13},
+		{Path: "vendor/keep/a.txt", Size: 10},
+	}))
+}
+
+func TestLintDockerignore(t *testing.T) {
+	dir := fs.NewDir(t, t.Name(),
+		fs.WithFile("Dockerfile", ""),
+		fs.WithFile("app.log", ""),
+		fs.WithFile("debug.log", ""),
+		fs.WithDir("docs", fs.WithFile("README.md", "")),
+		fs.WithDir("build", fs.WithFile("out.bin", "")),
+	)
+
+	warnings, err := LintDockerignore(dir.Path(), []string{
+		"*.log",
+		"docs",
+		"!docs/README.md",
+		"build/out.bin",
+		"!build",
+		"*.tmp",
+		"!vendor",
+		"**/*.md",
+	})
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(warnings, []string{
+		`pattern "build/out.bin" has no effect: all files it matches are re-included by "!build"`,
+		`pattern "*.tmp" does not match any files`,
+		`pattern "!vendor" does not match any files`,
+	}))
+}
@@ -5,19 +5,25 @@
 	"bytes"
 	"compress/gzip"
 	"context"
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 	"testing"
 
+	"github.com/docker/cli/cli/config"
 	"github.com/docker/cli/cli/streams"
 	"github.com/docker/cli/internal/test"
 	"github.com/google/go-cmp/cmp"
 	"github.com/moby/go-archive/compression"
+	"github.com/moby/moby/api/types/image"
 	"github.com/moby/moby/client"
 	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
 	"gotest.tools/v3/fs"
+	"gotest.tools/v3/golden"
 	"gotest.tools/v3/skip"
 )
 
@@ -113,6 +119,129 @@ func TestRunBuildDockerfileOutsideContext(t *testing.T) {
 	assert.DeepEqual(t, expected, fakeBuild.filenames(t))
 }
 
+func TestRunBuildContextReport(t *testing.T) {
+	t.Setenv("DOCKER_BUILDKIT", "0")
+	dir := fs.NewDir(t, t.Name(),
+		fs.WithFile("Dockerfile", "FROM busybox\n"),
+		fs.WithFile(".dockerignore", "*.tmp\nnode_modules\n"),
+		fs.WithDir("node_modules", fs.WithFile("big.js", "0123456789")),
+		fs.WithDir("src", fs.WithFile("main.go", "package main\n")),
+	)
+	defer dir.Remove()
+
+	fakeBuild := newFakeBuild()
+	cli := test.NewFakeCli(&fakeClient{imageBuildFunc: fakeBuild.build})
+
+	options := newBuildOptions()
+	options.context = dir.Path()
+	options.contextReport = true
+	assert.NilError(t, runBuild(context.TODO(), cli, options))
+	golden.Assert(t, cli.ErrBuffer().String(), "build-context-report.golden")
+
+	assert.NilError(t, options.maxContextSize.Set("16"))
+	options.contextReport = false
+	err := runBuild(context.TODO(), cli, options)
+	assert.Check(t, is.Error(err, "build context is 45B, which exceeds the maximum of 16B (--max-context-size); use .dockerignore to exclude files from the context"))
+}
+
+func TestRunBuildMaxContextSizeReincluded(t *testing.T) {
+	t.Setenv("DOCKER_BUILDKIT", "0")
+	dir := fs.NewDir(t, t.Name(),
+		fs.WithFile("Dockerfile", "FROM busybox\n"),
+		fs.WithFile(".dockerignore", "vendor\n!vendor/keep\n"),
+		fs.WithDir("vendor",
+			fs.WithFile("big.bin", "0123456789"),
+			fs.WithDir("keep", fs.WithFile("data.bin", "0123456789012345678901234567890123456789")),
+		),
+	)
+	defer dir.Remove()
+
+	cli := test.NewFakeCli(&fakeClient{imageBuildFunc: newFakeBuild().build})
+
+	options := newBuildOptions()
+	options.context = dir.Path()
+	assert.NilError(t, options.maxContextSize.Set("32"))
+	err := runBuild(context.TODO(), cli, options)
+	assert.Check(t, is.Error(err, "build context is 73B, which exceeds the maximum of 32B (--max-context-size); use .dockerignore to exclude files from the context"))
+}
+
+func TestRunBuildContextCache(t *testing.T) {
+	t.Setenv("DOCKER_BUILDKIT", "0")
+	config.SetDir(t.TempDir())
+	dir := fs.NewDir(t, t.Name(),
+		fs.WithFile("Dockerfile", "FROM busybox\nCOPY foo /\n"),
+		fs.WithFile("foo", "some content"),
+	)
+	defer dir.Remove()
+
+	var (
+		builds     int
+		uploads    int
+		dockerfile string
+		lastOpts   client.ImageBuildOptions
+	)
+	cli := test.NewFakeCli(&fakeClient{
+		imageBuildFunc: func(_ context.Context, buildContext io.Reader, options client.ImageBuildOptions) (client.ImageBuildResponse, error) {
+			builds++
+			lastOpts = options
+			tr := tar.NewReader(buildContext)
+			for {
+				hdr, err := tr.Next()
+				if err != nil {
+					break
+				}
+				if hdr.Name == "foo" {
+					uploads++
+				}
+				if hdr.Name == options.Dockerfile {
+					dt, _ := io.ReadAll(tr)
+					dockerfile = string(dt)
+				}
+			}
+			body := fmt.Sprintf(`{"aux":{"ID":"sha256:%d"}}`+"\n", uploads)
+			return client.ImageBuildResponse{Body: io.NopCloser(strings.NewReader(body))}, nil
+		},
+		imageInspectFunc: func(img string) (image.InspectResponse, error) {
+			return image.InspectResponse{ID: img}, nil
+		},
+	})
+
+	runBuildCmd := func(args ...string) {
+		t.Helper()
+		cmd := newBuildCommand(cli)
+		cmd.SetOut(io.Discard)
+		cmd.SetArgs(append(args, "--context-cache", dir.Path()))
+		assert.NilError(t, cmd.Execute())
+	}
+
+	runBuildCmd("-t", "app:1")
+	assert.Check(t, is.Equal(builds, 1))
+	assert.Check(t, is.Equal(uploads, 1))
+
+	// an unchanged context is not sent, but the image is still built (and
+	// tagged) by the daemon from the image of the previous build.
+	cli.OutBuffer().Reset()
+	runBuildCmd("-t", "app:2", "--label", "foo=bar")
+	runBuildCmd("-t", "app:2", "--label", "foo=bar")
+	assert.Check(t, is.Equal(builds, 3))
+	assert.Check(t, is.Equal(uploads, 2))
+	assert.Check(t, is.Equal(dockerfile, "FROM sha256:2\n"))
+	assert.Check(t, is.DeepEqual(lastOpts.Tags, []string{"app:2"}))
+	assert.Check(t, is.Len(lastOpts.Labels, 0))
+	assert.Check(t, is.Contains(cli.OutBuffer().String(), "Build context and options unchanged, using image sha256:2 from a previous build\n"))
+
+	// changing a build option or the context requires sending the context
+	runBuildCmd("--build-arg", "FOO=bar")
+	assert.Check(t, is.Equal(uploads, 3))
+	assert.NilError(t, os.WriteFile(filepath.Join(dir.Path(), "foo"), []byte("changed content"), 0o644))
+	runBuildCmd()
+	assert.Check(t, is.Equal(uploads, 4))
+	runBuildCmd()
+	assert.Check(t, is.Equal(uploads, 4))
+	runBuildCmd("--no-cache")
+	assert.Check(t, is.Equal(uploads, 5))
+}
+
 // TestRunBuildFromGitHubSpecialCase tests that build contexts
 // starting with `github.com/` are special-cased, and the build command attempts
 // to clone the remote repo.
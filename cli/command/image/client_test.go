@@ -2,13 +2,19 @@
 
 import (
 	"context"
+	"errors"
 	"io"
 	"strings"
 	"time"
 
+	"github.com/distribution/reference"
+	manifesttypes "github.com/docker/cli/cli/manifest/types"
+	"github.com/docker/cli/internal/registryclient"
+	"github.com/moby/moby/api/types/container"
 	"github.com/moby/moby/api/types/image"
 	"github.com/moby/moby/api/types/system"
 	"github.com/moby/moby/client"
+	"github.com/opencontainers/go-digest"
 )
 
 type fakeClient struct {
@@ -26,6 +32,9 @@ type fakeClient struct {
 	imageImportFunc  func(source client.ImageImportSource, ref string, options client.ImageImportOptions) (io.ReadCloser, error)
 	imageHistoryFunc func(img string, options ...client.ImageHistoryOption) ([]image.HistoryResponseItem, error)
 	imageBuildFunc   func(context.Context, io.Reader, client.ImageBuildOptions) (client.ImageBuildResponse, error)
+
+	containerListFunc    func(options client.ContainerListOptions) ([]container.Summary, error)
+	containerInspectFunc func(containerID string) (container.InspectResponse, error)
 }
 
 func (cli *fakeClient) ImageTag(_ context.Context, img, ref string) error {
@@ -122,3 +131,53 @@ func (cli *fakeClient) ImageBuild(ctx context.Context, buildContext io.Reader, o
 	}
 	return client.ImageBuildResponse{Body: io.NopCloser(strings.NewReader(""))}, nil
 }
+
+func (cli *fakeClient) ContainerList(_ context.Context, options client.ContainerListOptions) ([]container.Summary, error) {
+	if cli.containerListFunc != nil {
+		return cli.containerListFunc(options)
+	}
+	return []container.Summary{}, nil
+}
+
+func (cli *fakeClient) ContainerInspect(_ context.Context, containerID string) (container.InspectResponse, error) {
+	if cli.containerInspectFunc != nil {
+		return cli.containerInspectFunc(containerID)
+	}
+	return container.InspectResponse{}, nil
+}
+
+type fakeRegistryClient struct {
+	registryclient.RegistryClient
+	getManifestFunc     func(ctx context.Context, ref reference.Named) (manifesttypes.ImageManifest, error)
+	getManifestListFunc func(ctx context.Context, ref reference.Named) ([]manifesttypes.ImageManifest, error)
+	getBlobFunc         func(ctx context.Context, ref reference.Named, dgst digest.Digest) ([]byte, error)
+	openBlobFunc        func(ctx context.Context, ref reference.Named, dgst digest.Digest) (io.ReadCloser, error)
+}
+
+func (c *fakeRegistryClient) GetManifest(ctx context.Context, ref reference.Named) (manifesttypes.ImageManifest, error) {
+	if c.getManifestFunc != nil {
+		return c.getManifestFunc(ctx, ref)
+	}
+	return manifesttypes.ImageManifest{}, errors.New("no such manifest")
+}
+
+func (c *fakeRegistryClient) GetManifestList(ctx context.Context, ref reference.Named) ([]manifesttypes.ImageManifest, error) {
+	if c.getManifestListFunc != nil {
+		return c.getManifestListFunc(ctx, ref)
+	}
+	return nil, errors.New("no such manifest")
+}
+
+func (c *fakeRegistryClient) GetBlob(ctx context.Context, ref reference.Named, dgst digest.Digest) ([]byte, error) {
+	if c.getBlobFunc != nil {
+		return c.getBlobFunc(ctx, ref, dgst)
+	}
+	return nil, errors.New("no such blob")
+}
+
+func (c *fakeRegistryClient) OpenBlob(ctx context.Context, ref reference.Named, dgst digest.Digest) (io.ReadCloser, error) {
+	if c.openBlobFunc != nil {
+		return c.openBlobFunc(ctx, ref, dgst)
+	}
+	return nil, errors.New("no such blob")
+}
@@ -40,6 +40,8 @@ func newImageCommand(dockerCli command.Cli) *cobra.Command {
 		newPushCommand(dockerCli),
 		newSaveCommand(dockerCli),
 		newTagCommand(dockerCli),
+		newRetagCommand(dockerCli),
+		newLintCommand(dockerCli),
 		newListCommand(dockerCli),
 		newImageRemoveCommand(dockerCli),
 		newInspectCommand(dockerCli),
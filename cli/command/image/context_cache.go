@@ -0,0 +1,114 @@
+package image
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/image/build"
+	"github.com/docker/cli/cli/config"
+	"github.com/moby/go-archive"
+	"github.com/moby/moby/client"
+	"github.com/moby/sys/atomicwriter"
+	"github.com/opencontainers/go-digest"
+)
+
+// contextCache records the digests of the files in a build context, and the
+// image that was built from it, so that a build with an unchanged context
+// and options does not have to be sent to the daemon again.
+type contextCache struct {
+	fileName string
+
+	// Key is the digest of the build context, the .dockerignore patterns,
+	// and the build options of the last successful build.
+	Key     digest.Digest `json:"key,omitempty"`
+	ImageID string        `json:"imageID,omitempty"`
+	// Files are the digests of the files in the build context, which are
+	// re-used for files that did not change.
+	Files map[string]build.FileDigest `json:"files,omitempty"`
+}
+
+// loadContextCache loads the cache for a build context, which is identified
+// by the context as specified on the command line, and the daemon it is
+// built on. A missing or invalid cache is ignored.
+func loadContextCache(dockerCLI command.Cli, buildContext string) *contextCache {
+	if abs, err := filepath.Abs(buildContext); err == nil {
+		buildContext = abs
+	}
+	id := digest.FromString(dockerCLI.Client().DaemonHost() + "\n" + buildContext)
+	c := &contextCache{fileName: filepath.Join(config.Dir(), "build-context-cache", id.Encoded()+".json")}
+
+	data, err := os.ReadFile(c.fileName)
+	if err != nil {
+		return c
+	}
+	if err := json.Unmarshal(data, c); err != nil {
+		return &contextCache{fileName: c.fileName}
+	}
+	return c
+}
+
+func (c *contextCache) save() error {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.fileName), 0o700); err != nil {
+		return err
+	}
+	return atomicwriter.WriteFile(c.fileName, data, 0o600)
+}
+
+// contextCacheKey returns the key for a build with the given context,
+// .dockerignore patterns, and options. Options that do not affect the
+// resulting image, such as tags and credentials, are not part of the key.
+func contextCacheKey(contextDigest digest.Digest, patterns []string, dockerfile digest.Digest, buildOpts client.ImageBuildOptions) (digest.Digest, error) {
+	buildOpts.Tags = nil
+	buildOpts.SuppressOutput = false
+	buildOpts.AuthConfigs = nil
+	buildOpts.Context = nil
+	data, err := json.Marshal(struct {
+		Context    digest.Digest
+		Ignore     []string
+		Dockerfile digest.Digest `json:",omitempty"`
+		Options    client.ImageBuildOptions
+	}{
+		Context:    contextDigest,
+		Ignore:     patterns,
+		Dockerfile: dockerfile,
+		Options:    buildOpts,
+	})
+	if err != nil {
+		return "", err
+	}
+	return digest.FromBytes(data), nil
+}
+
+// cachedImageContext returns a build context that builds the image of a
+// previous build of an unchanged context, so that the build context does not
+// have to be sent to the daemon. It returns false if the image no longer exists.
+func cachedImageContext(ctx context.Context, dockerCLI command.Cli, imageID string) (io.ReadCloser, bool, error) {
+	if _, err := dockerCLI.Client().ImageInspect(ctx, imageID); err != nil {
+		return nil, false, nil //nolint:nilerr // send the build context if the image is no longer present
+	}
+	buildCtx, err := archive.Generate("Dockerfile", "FROM "+imageID+"\n")
+	if err != nil {
+		return nil, false, err
+	}
+	return io.NopCloser(buildCtx), true, nil
+}
+
+// cachedImageBuildOptions returns the options to build the image of a previous
+// build; all other options are already applied to the image.
+func cachedImageBuildOptions(buildOpts client.ImageBuildOptions) client.ImageBuildOptions {
+	return client.ImageBuildOptions{
+		Version:        buildOpts.Version,
+		Tags:           buildOpts.Tags,
+		SuppressOutput: buildOpts.SuppressOutput,
+		Dockerfile:     "Dockerfile",
+		Remove:         buildOpts.Remove,
+	}
+}
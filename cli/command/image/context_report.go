@@ -0,0 +1,67 @@
+package image
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/docker/cli/cli/command/formatter/tabwriter"
+	"github.com/docker/cli/cli/command/image/build"
+	"github.com/docker/go-units"
+)
+
+// contextReportLimit is the number of files and directories shown by
+// "docker build --context-report".
+const contextReportLimit = 10
+
+// checkContext prints a report of the files in the build context when
+// requested, and checks the size of the context against --max-context-size.
+// patterns are the patterns from the .dockerignore file, and excludes the
+// patterns that are used to create the context.
+func checkContext(out io.Writer, contextDir string, patterns, excludes []string, options buildOptions) error {
+	usage, err := build.GetContextUsage(contextDir, excludes, contextReportLimit)
+	if err != nil {
+		return fmt.Errorf("checking context: %w", err)
+	}
+	if options.contextReport {
+		warnings, err := build.LintDockerignore(contextDir, patterns)
+		if err != nil {
+			return fmt.Errorf("checking .dockerignore: %w", err)
+		}
+		writeContextReport(out, usage, warnings)
+	}
+	if maxSize := options.maxContextSize.Value(); maxSize > 0 && usage.Size > maxSize {
+		return fmt.Errorf("build context is %s, which exceeds the maximum of %s (--max-context-size); use .dockerignore to exclude files from the context",
+			units.HumanSize(float64(usage.Size)), units.HumanSize(float64(maxSize)))
+	}
+	return nil
+}
+
+func writeContextReport(out io.Writer, usage build.ContextUsage, warnings []string) {
+	_, _ = fmt.Fprintf(out, "Build context: %d file(s), %s\n", usage.Files, units.HumanSize(float64(usage.Size)))
+
+	w := tabwriter.NewWriter(out, 10, 1, 3, ' ', 0)
+	for _, section := range []struct {
+		header string
+		paths  []build.PathSize
+	}{
+		{header: "LARGEST DIRECTORIES", paths: usage.LargestDirs},
+		{header: "LARGEST FILES", paths: usage.LargestFiles},
+	} {
+		if len(section.paths) == 0 {
+			continue
+		}
+		_, _ = fmt.Fprintf(w, "\n%s\tSIZE\n", section.header)
+		for _, p := range section.paths {
+			_, _ = fmt.Fprintf(w, "%s\t%s\n", p.Path, units.HumanSize(float64(p.Size)))
+		}
+	}
+	_ = w.Flush()
+
+	if len(warnings) > 0 {
+		_, _ = fmt.Fprintln(out, "\n.dockerignore warnings:")
+		for _, warning := range warnings {
+			_, _ = fmt.Fprintln(out, " - "+warning)
+		}
+	}
+	_, _ = fmt.Fprintln(out)
+}
@@ -1,12 +1,20 @@
 package image
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"testing"
 
+	"github.com/distribution/reference"
+	manifesttypes "github.com/docker/cli/cli/manifest/types"
 	"github.com/docker/cli/internal/test"
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/manifest/ocischema"
 	"github.com/moby/moby/api/types/image"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"gotest.tools/v3/assert"
 	is "gotest.tools/v3/assert/cmp"
 	"gotest.tools/v3/golden"
@@ -91,3 +99,94 @@ func TestNewInspectCommandSuccess(t *testing.T) {
 		})
 	}
 }
+
+func remoteManifest(t *testing.T, ref reference.Named, config []byte, platform *ocispec.Platform) manifesttypes.ImageManifest {
+	t.Helper()
+	man, err := ocischema.FromStruct(ocischema.Manifest{
+		Versioned: ocischema.SchemaVersion,
+		Config: distribution.Descriptor{
+			MediaType: ocispec.MediaTypeImageConfig,
+			Digest:    digest.FromBytes(config),
+			Size:      int64(len(config)),
+		},
+		Layers: []distribution.Descriptor{
+			{MediaType: ocispec.MediaTypeImageLayerGzip, Digest: digest.FromString("layer1"), Size: 1000},
+			{MediaType: ocispec.MediaTypeImageLayerGzip, Digest: digest.FromString("layer2"), Size: 234},
+		},
+	})
+	assert.NilError(t, err)
+	mt, raw, err := man.Payload()
+	assert.NilError(t, err)
+	desc := ocispec.Descriptor{
+		Digest:    digest.FromBytes(raw),
+		Size:      int64(len(raw)),
+		MediaType: mt,
+		Platform:  platform,
+	}
+	return manifesttypes.NewOCIImageManifest(ref, desc, man)
+}
+
+func TestInspectRemote(t *testing.T) {
+	amd64Config := []byte(`{"created":"2025-01-02T03:04:05Z","architecture":"amd64","os":"linux","config":{"Env":["PATH=/usr/bin"],"Labels":{"org.opencontainers.image.version":"1.0"}},"rootfs":{"type":"layers","diff_ids":[]}}`)
+	arm64Config := []byte(`{"created":"2025-01-02T03:04:05Z","architecture":"arm64","variant":"v8","os":"linux","config":{"Labels":{"org.opencontainers.image.version":"1.0"}},"rootfs":{"type":"layers","diff_ids":[]}}`)
+	blobs := map[digest.Digest][]byte{
+		digest.FromBytes(amd64Config): amd64Config,
+		digest.FromBytes(arm64Config): arm64Config,
+	}
+	getBlob := func(_ context.Context, _ reference.Named, dgst digest.Digest) ([]byte, error) {
+		b, ok := blobs[dgst]
+		if !ok {
+			return nil, errors.New("blob unknown")
+		}
+		return b, nil
+	}
+
+	t.Run("manifest", func(t *testing.T) {
+		cli := test.NewFakeCli(&fakeClient{})
+		cli.SetRegistryClient(&fakeRegistryClient{
+			getManifestFunc: func(_ context.Context, ref reference.Named) (manifesttypes.ImageManifest, error) {
+				assert.Check(t, is.Equal(ref.String(), "docker.io/library/app:1.0"))
+				return remoteManifest(t, ref, amd64Config, nil), nil
+			},
+			getBlobFunc: getBlob,
+		})
+		cmd := newInspectCommand(cli)
+		cmd.SetOut(io.Discard)
+		cmd.SetArgs([]string{"--remote", "app:1.0"})
+		assert.NilError(t, cmd.Execute())
+		golden.Assert(t, cli.OutBuffer().String(), "inspect-command-success.remote.golden")
+	})
+
+	list := func(_ context.Context, ref reference.Named) ([]manifesttypes.ImageManifest, error) {
+		return []manifesttypes.ImageManifest{
+			remoteManifest(t, ref, amd64Config, &ocispec.Platform{OS: "linux", Architecture: "amd64"}),
+			remoteManifest(t, ref, arm64Config, &ocispec.Platform{OS: "linux", Architecture: "arm64", Variant: "v8"}),
+			remoteManifest(t, ref, []byte(`{}`), &ocispec.Platform{OS: "unknown", Architecture: "unknown"}),
+		}, nil
+	}
+	t.Run("index", func(t *testing.T) {
+		cli := test.NewFakeCli(&fakeClient{})
+		cli.SetRegistryClient(&fakeRegistryClient{getManifestListFunc: list, getBlobFunc: getBlob})
+		cmd := newInspectCommand(cli)
+		cmd.SetOut(io.Discard)
+		cmd.SetArgs([]string{"--remote", "--format", `{{range .Platforms}}{{.Os}}/{{.Architecture}} {{.Size}} {{index .Config.Labels "org.opencontainers.image.version"}}{{println}}{{end}}`, "app:1.0"})
+		assert.NilError(t, cmd.Execute())
+		assert.Check(t, is.Equal(cli.OutBuffer().String(), "linux/amd64 1234 1.0\nlinux/arm64 1234 1.0\n\n"))
+	})
+
+	t.Run("platform", func(t *testing.T) {
+		cli := test.NewFakeCli(&fakeClient{})
+		cli.SetRegistryClient(&fakeRegistryClient{getManifestListFunc: list, getBlobFunc: getBlob})
+		cmd := newInspectCommand(cli)
+		cmd.SetOut(io.Discard)
+		cmd.SetArgs([]string{"--remote", "--platform", "linux/arm64", "--format", `{{range .Platforms}}{{.Variant}} {{.Created}}{{end}}`, "app:1.0"})
+		assert.NilError(t, cmd.Execute())
+		assert.Check(t, is.Equal(cli.OutBuffer().String(), "v8 2025-01-02 03:04:05 +0000 UTC\n"))
+
+		cmd = newInspectCommand(cli)
+		cmd.SetOut(io.Discard)
+		cmd.SetErr(io.Discard)
+		cmd.SetArgs([]string{"--remote", "--platform", "windows/amd64", "app:1.0"})
+		assert.Check(t, is.ErrorContains(cmd.Execute(), "image app:1.0 has no manifest for platform windows/amd64"))
+	})
+}
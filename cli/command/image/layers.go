@@ -0,0 +1,370 @@
+package image
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/moby/go-archive/compression"
+	"github.com/moby/moby/client"
+)
+
+// archiveManifestFile is the name of the legacy Docker manifest included
+// in archives produced by "docker save".
+const archiveManifestFile = "manifest.json"
+
+// archiveManifestItem is an entry in the "manifest.json" of an image archive.
+type archiveManifestItem struct {
+	Config   string
+	RepoTags []string
+	Layers   []string
+}
+
+// archiveImageConfig contains the subset of the image config that is
+// needed to map layers in an archive to their uncompressed digests.
+type archiveImageConfig struct {
+	RootFS struct {
+		DiffIDs []string `json:"diff_ids"`
+	} `json:"rootfs"`
+}
+
+// baseImageLayers returns the set of layer digests (DiffIDs) used by the
+// given images.
+func baseImageLayers(ctx context.Context, dockerCLI command.Cli, images []string) (map[string]struct{}, error) {
+	layers := make(map[string]struct{})
+	for _, img := range images {
+		inspect, err := dockerCLI.Client().ImageInspect(ctx, img)
+		if err != nil {
+			return nil, err
+		}
+		for _, l := range inspect.RootFS.Layers {
+			layers[l] = struct{}{}
+		}
+	}
+	return layers, nil
+}
+
+// blobDigest returns the digest of a blob stored at the given path in an
+// OCI image layout ("blobs/<algorithm>/<encoded>"), or an empty string if
+// the path does not refer to a blob.
+func blobDigest(name string) string {
+	p := strings.Split(path.Clean(name), "/")
+	if len(p) != 3 || p[0] != "blobs" || p[1] == "" || p[2] == "" {
+		return ""
+	}
+	return p[1] + ":" + p[2]
+}
+
+// maxMetadataBlobSize is the maximum size of blobs in an image archive that
+// are read to find image manifests and configs.
+const maxMetadataBlobSize = 4 << 20
+
+// archiveImageManifest contains the subset of an image manifest that is
+// needed to map layer blobs to their uncompressed digests.
+type archiveImageManifest struct {
+	Config struct {
+		Digest string `json:"digest"`
+	} `json:"config"`
+	Layers []struct {
+		Digest string `json:"digest"`
+	} `json:"layers"`
+}
+
+// excludeLayers copies the image archive from src to dst, omitting the
+// layer blobs whose DiffID is in the exclude set. It returns the number of
+// blobs that were omitted.
+//
+// With the graphdriver image store, layers are stored uncompressed, so the
+// blob digest matches the DiffID of the layer. With the containerd image
+// store, layers can be compressed, so the blob digests are mapped to DiffIDs
+// through the image manifests and configs in the archive. To do so, the
+// archive is buffered in a temporary file. Other files in the archive,
+// including the manifests, are copied as-is; the resulting archive can only
+// be loaded on a host that already has the excluded layers.
+func excludeLayers(dst io.Writer, src io.Reader, exclude map[string]struct{}) (int, error) {
+	input, cleanup, err := seekableInput(src)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read image archive: %w", err)
+	}
+	defer cleanup()
+
+	diffIDs, err := archiveLayerDiffIDs(input)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read image archive: %w", err)
+	}
+	if _, err := input.Seek(0, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	tr := tar.NewReader(input)
+	tw := tar.NewWriter(dst)
+
+	var excluded int
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return excluded, fmt.Errorf("failed to read image archive: %w", err)
+		}
+		if hdr.Typeflag == tar.TypeReg {
+			dgst := blobDigest(hdr.Name)
+			if diffID, ok := diffIDs[dgst]; ok {
+				dgst = diffID
+			}
+			if _, ok := exclude[dgst]; ok {
+				excluded++
+				continue
+			}
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return excluded, err
+		}
+		if _, err := io.Copy(tw, tr); err != nil {
+			return excluded, err
+		}
+	}
+	return excluded, tw.Close()
+}
+
+// archiveLayerDiffIDs returns the DiffIDs of the layer blobs in the image
+// archive, indexed by blob digest, as described by the image manifests and
+// configs in the archive.
+func archiveLayerDiffIDs(input io.ReadSeeker) (map[string]string, error) {
+	metadata := make(map[string][]byte)
+	err := walkArchive(input, func(hdr *tar.Header, r io.Reader) error {
+		dgst := blobDigest(hdr.Name)
+		if dgst == "" || hdr.Size > maxMetadataBlobSize {
+			return nil
+		}
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		if json.Valid(data) {
+			metadata[dgst] = data
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	diffIDs := make(map[string]string)
+	for _, data := range metadata {
+		var mfst archiveImageManifest
+		if err := json.Unmarshal(data, &mfst); err != nil || mfst.Config.Digest == "" || len(mfst.Layers) == 0 {
+			continue
+		}
+		var cfg archiveImageConfig
+		if err := json.Unmarshal(metadata[mfst.Config.Digest], &cfg); err != nil {
+			continue
+		}
+		for i, l := range mfst.Layers {
+			if i < len(cfg.RootFS.DiffIDs) {
+				diffIDs[l.Digest] = cfg.RootFS.DiffIDs[i]
+			}
+		}
+	}
+	return diffIDs, nil
+}
+
+// seekableInput returns r as an io.ReadSeeker. If r isn't seekable, it's
+// copied to a temporary file, which is removed by the returned cleanup
+// function.
+func seekableInput(r io.Reader) (io.ReadSeeker, func(), error) {
+	if rs, ok := r.(io.ReadSeeker); ok {
+		if _, err := rs.Seek(0, io.SeekCurrent); err == nil {
+			return rs, func() {}, nil
+		}
+	}
+	f, err := os.CreateTemp("", "docker-image-archive-")
+	if err != nil {
+		return nil, nil, err
+	}
+	cleanup := func() {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	return f, cleanup, nil
+}
+
+// missingArchiveLayers returns the DiffIDs of layers that are referenced by
+// the images in the archive, but for which the archive does not contain a
+// blob. The archive is read twice, so input must be seekable. Compressed
+// archives, input that is not a tar archive, and archives without a
+// "manifest.json" are not checked, and left to the daemon to validate.
+func missingArchiveLayers(input io.ReadSeeker) ([]string, error) {
+	if compressed, err := isCompressedArchive(input); err != nil || compressed {
+		return nil, err
+	}
+
+	var (
+		files    = make(map[string]struct{})
+		manifest []archiveManifestItem
+	)
+	err := walkArchive(input, func(hdr *tar.Header, r io.Reader) error {
+		name := path.Clean(hdr.Name)
+		files[name] = struct{}{}
+		if name == archiveManifestFile {
+			return json.NewDecoder(r).Decode(&manifest)
+		}
+		return nil
+	})
+	if errors.Is(err, tar.ErrHeader) || errors.Is(err, io.ErrUnexpectedEOF) {
+		// Not a tar archive; leave it to the daemon to reject it.
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image archive: %w", err)
+	}
+	if len(manifest) == 0 {
+		// Not an archive produced by "docker save"; leave it to the daemon.
+		return nil, nil
+	}
+
+	configs := make(map[string][]int)
+	for i, m := range manifest {
+		for _, l := range m.Layers {
+			if _, ok := files[path.Clean(l)]; !ok {
+				configs[path.Clean(m.Config)] = append(configs[path.Clean(m.Config)], i)
+				break
+			}
+		}
+	}
+	if len(configs) == 0 {
+		return nil, nil
+	}
+
+	var missing []string
+	seen := make(map[string]struct{})
+	err = walkArchive(input, func(hdr *tar.Header, r io.Reader) error {
+		items, ok := configs[path.Clean(hdr.Name)]
+		if !ok {
+			return nil
+		}
+		var cfg archiveImageConfig
+		if err := json.NewDecoder(r).Decode(&cfg); err != nil {
+			return fmt.Errorf("invalid image config %s: %w", hdr.Name, err)
+		}
+		for _, i := range items {
+			for n, l := range manifest[i].Layers {
+				if _, ok := files[path.Clean(l)]; ok {
+					continue
+				}
+				if n >= len(cfg.RootFS.DiffIDs) {
+					return fmt.Errorf("invalid image config %s: no diff_id for layer %s", hdr.Name, l)
+				}
+				diffID := cfg.RootFS.DiffIDs[n]
+				if _, ok := seen[diffID]; !ok {
+					seen[diffID] = struct{}{}
+					missing = append(missing, diffID)
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return missing, nil
+}
+
+// isCompressedArchive returns whether the archive is compressed, for example
+// with gzip, which "docker load" accepts.
+func isCompressedArchive(input io.ReadSeeker) (bool, error) {
+	if _, err := input.Seek(0, io.SeekStart); err != nil {
+		return false, err
+	}
+	header := make([]byte, 10)
+	n, err := io.ReadFull(input, header)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return false, err
+	}
+	return compression.Detect(header[:n]) != compression.None, nil
+}
+
+// walkArchive calls fn for each regular file in the tar archive, starting
+// from the beginning of input.
+func walkArchive(input io.ReadSeeker, fn func(*tar.Header, io.Reader) error) error {
+	if _, err := input.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	tr := tar.NewReader(input)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		if err := fn(hdr, tr); err != nil {
+			return err
+		}
+	}
+}
+
+// checkArchiveLayers verifies that layers missing from an image archive,
+// for example one produced by "docker save --exclude-layers-of", are
+// present on the daemon.
+func checkArchiveLayers(ctx context.Context, apiClient client.APIClient, input io.ReadSeeker) error {
+	missing, err := missingArchiveLayers(input)
+	if err != nil {
+		return err
+	}
+	if _, err := input.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	remaining := make(map[string]struct{}, len(missing))
+	for _, l := range missing {
+		remaining[l] = struct{}{}
+	}
+	images, err := apiClient.ImageList(ctx, client.ImageListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, img := range images {
+		inspect, err := apiClient.ImageInspect(ctx, img.ID)
+		if err != nil {
+			continue
+		}
+		for _, l := range inspect.RootFS.Layers {
+			delete(remaining, l)
+		}
+		if len(remaining) == 0 {
+			return nil
+		}
+	}
+
+	var notFound []string
+	for _, l := range missing {
+		if _, ok := remaining[l]; ok {
+			notFound = append(notFound, l)
+		}
+	}
+	return fmt.Errorf("image archive is missing %d layer(s) that are not present locally; load the base image first: %s", len(notFound), strings.Join(notFound, ", "))
+}
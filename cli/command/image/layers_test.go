@@ -0,0 +1,279 @@
+package image
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/docker/cli/cli/streams"
+	"github.com/docker/cli/internal/test"
+	"github.com/moby/moby/api/types/image"
+	"github.com/moby/moby/client"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+const (
+	testBaseLayer = "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+	testAppLayer  = "sha256:2222222222222222222222222222222222222222222222222222222222222222"
+)
+
+// writeTestArchive creates an image archive with a single image using
+// testBaseLayer and testAppLayer, omitting the blobs in omit.
+func writeTestArchive(t *testing.T, omit ...string) []byte {
+	t.Helper()
+	files := []struct{ name, content string }{
+		{name: "oci-layout", content: `{"imageLayoutVersion":"1.0.0"}`},
+		{name: "blobs/sha256/1111111111111111111111111111111111111111111111111111111111111111", content: "base"},
+		{name: "blobs/sha256/2222222222222222222222222222222222222222222222222222222222222222", content: "app"},
+		{name: "blobs/sha256/cccc", content: `{"rootfs":{"type":"layers","diff_ids":["` + testBaseLayer + `","` + testAppLayer + `"]}}`},
+		{name: "manifest.json", content: `[{"Config":"blobs/sha256/cccc","RepoTags":["app:latest"],"Layers":["blobs/sha256/1111111111111111111111111111111111111111111111111111111111111111","blobs/sha256/2222222222222222222222222222222222222222222222222222222222222222"]}]`},
+	}
+	skip := make(map[string]bool)
+	for _, o := range omit {
+		skip[blobDigest(o)] = true
+	}
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, f := range files {
+		if skip[blobDigest(f.name)] {
+			continue
+		}
+		assert.NilError(t, tw.WriteHeader(&tar.Header{Name: f.name, Mode: 0o644, Size: int64(len(f.content)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte(f.content))
+		assert.NilError(t, err)
+	}
+	assert.NilError(t, tw.Close())
+	return buf.Bytes()
+}
+
+func archiveFiles(t *testing.T, archive []byte) []string {
+	t.Helper()
+	var names []string
+	tr := tar.NewReader(bytes.NewReader(archive))
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return names
+		}
+		assert.NilError(t, err)
+		names = append(names, hdr.Name)
+	}
+}
+
+func TestBlobDigest(t *testing.T) {
+	assert.Check(t, is.Equal(blobDigest("blobs/sha256/abcd"), "sha256:abcd"))
+	assert.Check(t, is.Equal(blobDigest("./blobs/sha256/abcd"), "sha256:abcd"))
+	assert.Check(t, is.Equal(blobDigest("manifest.json"), ""))
+	assert.Check(t, is.Equal(blobDigest("blobs/sha256"), ""))
+	assert.Check(t, is.Equal(blobDigest("abcd/layer.tar"), ""))
+}
+
+func TestExcludeLayers(t *testing.T) {
+	var out bytes.Buffer
+	n, err := excludeLayers(&out, bytes.NewReader(writeTestArchive(t)), map[string]struct{}{testBaseLayer: {}})
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(n, 1))
+	assert.Check(t, is.DeepEqual(archiveFiles(t, out.Bytes()), []string{
+		"oci-layout",
+		"blobs/sha256/2222222222222222222222222222222222222222222222222222222222222222",
+		"blobs/sha256/cccc",
+		"manifest.json",
+	}))
+}
+
+func TestMissingArchiveLayers(t *testing.T) {
+	t.Run("complete", func(t *testing.T) {
+		missing, err := missingArchiveLayers(bytes.NewReader(writeTestArchive(t)))
+		assert.NilError(t, err)
+		assert.Check(t, is.Len(missing, 0))
+	})
+	t.Run("incremental", func(t *testing.T) {
+		missing, err := missingArchiveLayers(bytes.NewReader(writeTestArchive(t, "blobs/sha256/1111111111111111111111111111111111111111111111111111111111111111")))
+		assert.NilError(t, err)
+		assert.Check(t, is.DeepEqual(missing, []string{testBaseLayer}))
+	})
+	t.Run("not an archive", func(t *testing.T) {
+		missing, err := missingArchiveLayers(bytes.NewReader([]byte("not a tar")))
+		assert.NilError(t, err)
+		assert.Check(t, is.Len(missing, 0))
+	})
+}
+
+func TestCheckArchiveLayers(t *testing.T) {
+	archive := writeTestArchive(t, "blobs/sha256/1111111111111111111111111111111111111111111111111111111111111111")
+	apiClient := func(layers ...string) *fakeClient {
+		return &fakeClient{
+			imageListFunc: func(client.ImageListOptions) ([]image.Summary, error) {
+				return []image.Summary{{ID: "sha256:base"}}, nil
+			},
+			imageInspectFunc: func(string) (image.InspectResponse, error) {
+				return image.InspectResponse{RootFS: image.RootFS{Type: "layers", Layers: layers}}, nil
+			},
+		}
+	}
+
+	t.Run("present", func(t *testing.T) {
+		input := bytes.NewReader(archive)
+		assert.NilError(t, checkArchiveLayers(context.Background(), apiClient(testBaseLayer), input))
+		offset, err := input.Seek(0, io.SeekCurrent)
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(offset, int64(0)))
+	})
+	t.Run("missing", func(t *testing.T) {
+		err := checkArchiveLayers(context.Background(), apiClient(), bytes.NewReader(archive))
+		assert.Check(t, is.ErrorContains(err, "image archive is missing 1 layer(s) that are not present locally"))
+		assert.Check(t, is.ErrorContains(err, testBaseLayer))
+	})
+}
+
+func TestLoadIncrementalArchiveMissingLayers(t *testing.T) {
+	archive := filepath.Join(t.TempDir(), "app.tar")
+	assert.NilError(t, os.WriteFile(archive, writeTestArchive(t, "blobs/sha256/1111111111111111111111111111111111111111111111111111111111111111"), 0o644))
+
+	var loadCalled bool
+	cmd := newLoadCommand(test.NewFakeCli(&fakeClient{
+		imageLoadFunc: func(io.Reader, ...client.ImageLoadOption) (client.LoadResponse, error) {
+			loadCalled = true
+			return client.LoadResponse{}, nil
+		},
+	}))
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"--input", archive})
+	assert.Check(t, is.ErrorContains(cmd.Execute(), "image archive is missing 1 layer(s)"))
+	assert.Check(t, !loadCalled)
+}
+
+func TestSaveExcludeLayersOf(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		imageSaveFunc: func([]string, ...client.ImageSaveOption) (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(writeTestArchive(t))), nil
+		},
+		imageInspectFunc: func(img string) (image.InspectResponse, error) {
+			assert.Check(t, is.Equal(img, "base:latest"))
+			return image.InspectResponse{RootFS: image.RootFS{Type: "layers", Layers: []string{testBaseLayer}}}, nil
+		},
+	})
+	cmd := newSaveCommand(cli)
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"--exclude-layers-of", "base:latest", "app:latest"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Len(archiveFiles(t, cli.OutBuffer().Bytes()), 4))
+}
+
+func writeTar(t *testing.T, files ...[2]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, f := range files {
+		assert.NilError(t, tw.WriteHeader(&tar.Header{Name: f[0], Mode: 0o644, Size: int64(len(f[1])), Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte(f[1]))
+		assert.NilError(t, err)
+	}
+	assert.NilError(t, tw.Close())
+	return buf.Bytes()
+}
+
+func TestExcludeLayersCompressed(t *testing.T) {
+	// With the containerd image store, layer blobs are compressed, and mapped
+	// to their DiffIDs through the manifest and config.
+	const (
+		baseBlob = "blobs/sha256/aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+		appBlob  = "blobs/sha256/bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+	)
+	archive := writeTar(t,
+		[2]string{"oci-layout", `{"imageLayoutVersion":"1.0.0"}`},
+		[2]string{baseBlob, "compressed base"},
+		[2]string{appBlob, "compressed app"},
+		[2]string{"blobs/sha256/cccc", `{"rootfs":{"type":"layers","diff_ids":["` + testBaseLayer + `","` + testAppLayer + `"]}}`},
+		[2]string{"blobs/sha256/dddd", `{"mediaType":"application/vnd.oci.image.manifest.v1+json","config":{"digest":"sha256:cccc"},"layers":[{"digest":"` + blobDigest(baseBlob) + `"},{"digest":"` + blobDigest(appBlob) + `"}]}`},
+		[2]string{"manifest.json", `[{"Config":"blobs/sha256/cccc","RepoTags":["app:latest"],"Layers":["` + baseBlob + `","` + appBlob + `"]}]`},
+	)
+
+	var out bytes.Buffer
+	n, err := excludeLayers(&out, io.NopCloser(bytes.NewReader(archive)), map[string]struct{}{testBaseLayer: {}})
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(n, 1))
+	assert.Check(t, is.DeepEqual(archiveFiles(t, out.Bytes()), []string{
+		"oci-layout",
+		appBlob,
+		"blobs/sha256/cccc",
+		"blobs/sha256/dddd",
+		"manifest.json",
+	}))
+
+	missing, err := missingArchiveLayers(bytes.NewReader(out.Bytes()))
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(missing, []string{testBaseLayer}))
+}
+
+func TestMissingArchiveLayersErrors(t *testing.T) {
+	t.Run("invalid manifest", func(t *testing.T) {
+		_, err := missingArchiveLayers(bytes.NewReader(writeTar(t, [2]string{"manifest.json", "{invalid"})))
+		assert.Check(t, is.ErrorContains(err, "failed to read image archive"))
+	})
+	t.Run("compressed", func(t *testing.T) {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		_, err := gz.Write(writeTestArchive(t, "blobs/sha256/1111111111111111111111111111111111111111111111111111111111111111"))
+		assert.NilError(t, err)
+		assert.NilError(t, gz.Close())
+
+		missing, err := missingArchiveLayers(bytes.NewReader(buf.Bytes()))
+		assert.NilError(t, err)
+		assert.Check(t, is.Len(missing, 0))
+	})
+}
+
+func TestLoadIncrementalArchiveStdinNotChecked(t *testing.T) {
+	// Archives from STDIN are streamed to the daemon as-is, without
+	// buffering them to check for missing layers.
+	archive := writeTestArchive(t, "blobs/sha256/1111111111111111111111111111111111111111111111111111111111111111")
+	var loaded []byte
+	cli := test.NewFakeCli(&fakeClient{
+		imageLoadFunc: func(input io.Reader, _ ...client.ImageLoadOption) (client.LoadResponse, error) {
+			var err error
+			loaded, err = io.ReadAll(input)
+			return client.LoadResponse{Body: io.NopCloser(strings.NewReader(""))}, err
+		},
+		imageListFunc: func(client.ImageListOptions) ([]image.Summary, error) {
+			t.Error("unexpected image list")
+			return nil, nil
+		},
+	})
+	cli.SetIn(streams.NewIn(io.NopCloser(bytes.NewReader(archive))))
+	cmd := newLoadCommand(cli)
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.DeepEqual(loaded, archive))
+}
+
+func TestSaveExcludeLayersOfNoneExcluded(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		imageSaveFunc: func([]string, ...client.ImageSaveOption) (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(writeTestArchive(t))), nil
+		},
+		imageInspectFunc: func(string) (image.InspectResponse, error) {
+			return image.InspectResponse{RootFS: image.RootFS{Type: "layers", Layers: []string{"sha256:3333"}}}, nil
+		},
+	})
+	cmd := newSaveCommand(cli)
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"--exclude-layers-of", "other:latest", "app:latest"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Len(archiveFiles(t, cli.OutBuffer().Bytes()), 5))
+	assert.Check(t, is.Contains(cli.ErrBuffer().String(), "WARNING: the image archive contains no layers of other:latest"))
+}
@@ -0,0 +1,195 @@
+package image
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/image/build"
+	"github.com/spf13/cobra"
+)
+
+type lintOptions struct {
+	dockerfile string
+	context    string
+	format     string
+}
+
+// newLintCommand creates a new "docker image lint" command.
+func newLintCommand(dockerCLI command.Cli) *cobra.Command {
+	var options lintOptions
+
+	cmd := &cobra.Command{
+		Use:   "lint [OPTIONS] [DOCKERFILE]",
+		Short: "Check a Dockerfile for common mistakes",
+		Args:  cli.RequiresMaxArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.dockerfile = "Dockerfile"
+			if len(args) > 0 {
+				options.dockerfile = args[0]
+			}
+			return runLint(dockerCLI, options)
+		},
+		ValidArgsFunction: func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+			return nil, cobra.ShellCompDirectiveDefault
+		},
+		DisableFlagsInUseLine: true,
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&options.context, "context", "", `Build context to check COPY and ADD sources against .dockerignore (default is the directory of the Dockerfile)`)
+	flags.StringVar(&options.format, "format", "", `Format the output. Values: [json | sarif] (default is a list of warnings)`)
+	_ = cmd.RegisterFlagCompletionFunc("context", func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return nil, cobra.ShellCompDirectiveFilterDirs
+	})
+	_ = cmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions([]string{"json", "sarif"}, cobra.ShellCompDirectiveNoFileComp))
+
+	return cmd
+}
+
+func runLint(dockerCLI command.Cli, options lintOptions) error {
+	switch options.format {
+	case "", "json", "sarif":
+	default:
+		return fmt.Errorf("invalid format %q: must be one of json, sarif", options.format)
+	}
+
+	var r io.Reader
+	if options.dockerfile == "-" {
+		r = dockerCLI.In()
+	} else {
+		f, err := os.Open(options.dockerfile)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+		if options.context == "" {
+			options.context = filepath.Dir(options.dockerfile)
+		}
+	}
+	instructions, err := parseDockerfile(r)
+	if err != nil {
+		return fmt.Errorf("failed to read Dockerfile: %w", err)
+	}
+	if len(instructions) == 0 {
+		return errors.New("the Dockerfile is empty")
+	}
+
+	var excludes []string
+	if options.context != "" {
+		excludes, err = build.ReadDockerignore(options.context)
+		if err != nil {
+			return err
+		}
+	}
+	fileName := filepath.ToSlash(options.dockerfile)
+	findings, err := lintDockerfile(fileName, instructions, options.context, excludes)
+	if err != nil {
+		return err
+	}
+
+	switch options.format {
+	case "json":
+		if findings == nil {
+			findings = []lintFinding{}
+		}
+		enc := json.NewEncoder(dockerCLI.Out())
+		enc.SetIndent("", "    ")
+		if err := enc.Encode(findings); err != nil {
+			return err
+		}
+	case "sarif":
+		if err := writeSARIF(dockerCLI.Out(), findings); err != nil {
+			return err
+		}
+	default:
+		for _, f := range findings {
+			_, _ = fmt.Fprintf(dockerCLI.Out(), "%s:%d: %s: %s\n", f.File, f.Line, f.Rule, f.Message)
+		}
+	}
+	if len(findings) > 0 {
+		return cli.StatusError{StatusCode: 1}
+	}
+	return nil
+}
+
+// writeSARIF writes the findings in the Static Analysis Results Interchange
+// Format (SARIF) 2.1.0, which is supported by code scanning tools in CI.
+func writeSARIF(out io.Writer, findings []lintFinding) error {
+	type message struct {
+		Text string `json:"text"`
+	}
+	type rule struct {
+		ID               string  `json:"id"`
+		ShortDescription message `json:"shortDescription"`
+	}
+	type location struct {
+		PhysicalLocation struct {
+			ArtifactLocation struct {
+				URI string `json:"uri"`
+			} `json:"artifactLocation"`
+			Region struct {
+				StartLine int `json:"startLine"`
+			} `json:"region"`
+		} `json:"physicalLocation"`
+	}
+	type result struct {
+		RuleID    string     `json:"ruleId"`
+		Level     string     `json:"level"`
+		Message   message    `json:"message"`
+		Locations []location `json:"locations"`
+	}
+
+	rules := make([]rule, 0, len(lintRules))
+	for _, r := range lintRules {
+		rules = append(rules, rule{ID: r.ID, ShortDescription: message{Text: r.Description}})
+	}
+	results := make([]result, 0, len(findings))
+	for _, f := range findings {
+		var loc location
+		loc.PhysicalLocation.ArtifactLocation.URI = f.File
+		loc.PhysicalLocation.Region.StartLine = f.Line
+		results = append(results, result{
+			RuleID:    f.Rule,
+			Level:     f.Level,
+			Message:   message{Text: f.Message},
+			Locations: []location{loc},
+		})
+	}
+
+	type driver struct {
+		Name           string `json:"name"`
+		InformationURI string `json:"informationUri"`
+		Rules          []rule `json:"rules"`
+	}
+	type run struct {
+		Tool struct {
+			Driver driver `json:"driver"`
+		} `json:"tool"`
+		Results []result `json:"results"`
+	}
+	r := run{Results: results}
+	r.Tool.Driver = driver{
+		Name:           "docker image lint",
+		InformationURI: "https://docs.docker.com/reference/cli/docker/image/lint/",
+		Rules:          rules,
+	}
+
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		Schema  string `json:"$schema"`
+		Version string `json:"version"`
+		Runs    []run  `json:"runs"`
+	}{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []run{r},
+	})
+}
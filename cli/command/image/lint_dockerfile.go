@@ -0,0 +1,144 @@
+package image
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// instruction is a Dockerfile instruction, as parsed by parseDockerfile.
+type instruction struct {
+	// Cmd is the instruction in upper case, e.g. "RUN".
+	Cmd string
+	// Flags are the flags of the instruction, e.g. "--from=builder".
+	Flags []string
+	// Args are the arguments of the instruction, after the flags. Arguments
+	// in JSON (exec) form are returned as a list, otherwise they are split
+	// on whitespace.
+	Args []string
+	// Body is the arguments as written, with line continuations removed,
+	// followed by the content of heredocs, if any.
+	Body string
+	// Line is the line number of the start of the instruction.
+	Line int
+}
+
+// flag returns the value of the flag with the given name, or false if the
+// instruction does not have the flag.
+func (i instruction) flag(name string) (string, bool) {
+	for _, f := range i.Flags {
+		k, v, _ := strings.Cut(strings.TrimPrefix(f, "--"), "=")
+		if k == name {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+var (
+	escapeDirectiveRe = regexp.MustCompile(`^#\s*escape\s*=\s*(\S)\s*$`)
+	heredocRe         = regexp.MustCompile(`<<(-?)["']?([A-Za-z_][A-Za-z0-9_]*)["']?`)
+)
+
+// parseDockerfile parses a Dockerfile into its instructions. It is a
+// minimal parser for static checks, which handles parser directives, comments,
+// line continuations, and heredocs, but does not validate instructions or
+// expand variables.
+func parseDockerfile(r io.Reader) ([]instruction, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, strings.TrimRight(scanner.Text(), "\r"))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	escape := `\`
+	for _, l := range lines {
+		if !strings.HasPrefix(l, "#") {
+			break
+		}
+		if m := escapeDirectiveRe.FindStringSubmatch(l); m != nil {
+			escape = m[1]
+		}
+	}
+
+	var instructions []instruction
+	for n := 0; n < len(lines); n++ {
+		trimmed := strings.TrimSpace(lines[n])
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		start := n
+		var b strings.Builder
+		for {
+			l := strings.TrimRightFunc(lines[n], isSpace)
+			continued := strings.HasSuffix(l, escape)
+			if continued {
+				l = strings.TrimSuffix(l, escape)
+			}
+			b.WriteString(l)
+			if !continued || n+1 >= len(lines) {
+				break
+			}
+			n++
+			// Skip comments and empty lines in continuations.
+			for n+1 < len(lines) && (strings.TrimSpace(lines[n]) == "" || strings.HasPrefix(strings.TrimSpace(lines[n]), "#")) {
+				n++
+			}
+			b.WriteString(" ")
+		}
+
+		cmd, rest, _ := strings.Cut(strings.TrimSpace(b.String()), " ")
+		inst := instruction{Cmd: strings.ToUpper(cmd), Line: start + 1}
+		rest = strings.TrimSpace(rest)
+		for strings.HasPrefix(rest, "--") {
+			var f string
+			f, rest, _ = strings.Cut(rest, " ")
+			inst.Flags = append(inst.Flags, f)
+			rest = strings.TrimSpace(rest)
+		}
+		inst.Body = rest
+		inst.Args = splitArgs(rest)
+
+		switch inst.Cmd {
+		case "RUN", "COPY", "ADD":
+			for _, m := range heredocRe.FindAllStringSubmatch(rest, -1) {
+				trim, word := m[1] == "-", m[2]
+				for n+1 < len(lines) {
+					n++
+					l := lines[n]
+					if trim {
+						l = strings.TrimLeft(l, "\t")
+					}
+					if l == word {
+						break
+					}
+					inst.Body += "\n" + l
+				}
+			}
+		}
+		instructions = append(instructions, inst)
+	}
+	return instructions, nil
+}
+
+// splitArgs splits the arguments of an instruction, which can be in JSON
+// form (`["a", "b"]`), or separated by whitespace.
+func splitArgs(s string) []string {
+	if strings.HasPrefix(s, "[") {
+		var args []string
+		if err := json.Unmarshal([]byte(s), &args); err == nil {
+			return args
+		}
+	}
+	return strings.Fields(s)
+}
+
+func isSpace(r rune) bool {
+	return r == ' ' || r == '\t'
+}
@@ -0,0 +1,382 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.23
+
+package image
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/distribution/reference"
+	"github.com/moby/patternmatcher"
+)
+
+// lintRule describes a check performed by "docker image lint".
+type lintRule struct {
+	ID          string
+	Description string
+}
+
+var lintRules = []lintRule{
+	{ID: "AptGetUpdateWithoutInstall", Description: "apt-get update is used without apt-get install in the same RUN instruction"},
+	{ID: "LatestBaseImageTag", Description: "A base image uses the latest tag, or no tag"},
+	{ID: "MissingUser", Description: "The final stage runs as root"},
+	{ID: "AddInsteadOfCopy", Description: "ADD is used to copy local files, where COPY would do"},
+	{ID: "SecretInEnvOrArg", Description: "An ENV or ARG instruction may contain a secret"},
+	{ID: "UnusedStage", Description: "A build stage is not used to build the final stage"},
+	{ID: "CopyIgnoredSource", Description: "A COPY or ADD source is excluded by .dockerignore"},
+}
+
+// lintFinding is a problem found by "docker image lint".
+type lintFinding struct {
+	Rule    string
+	Level   string
+	Message string
+	File    string
+	Line    int
+}
+
+// stage is a build stage of a Dockerfile.
+type stage struct {
+	index        int
+	name         string
+	base         string
+	instructions []instruction
+}
+
+func (s stage) String() string {
+	if s.name != "" {
+		return s.name
+	}
+	return strconv.Itoa(s.index)
+}
+
+// lintDockerfile checks the instructions of a Dockerfile. If excludes is
+// not empty, COPY and ADD sources are checked against the patterns, and
+// against the files in contextDir for sources with wildcards.
+func lintDockerfile(fileName string, instructions []instruction, contextDir string, excludes []string) ([]lintFinding, error) {
+	var findings []lintFinding
+	report := func(rule string, inst instruction, format string, args ...any) {
+		findings = append(findings, lintFinding{
+			Rule:    rule,
+			Level:   "warning",
+			Message: fmt.Sprintf(format, args...),
+			File:    fileName,
+			Line:    inst.Line,
+		})
+	}
+
+	var ignored *patternmatcher.PatternMatcher
+	if len(excludes) > 0 {
+		var err error
+		ignored, err = patternmatcher.New(excludes)
+		if err != nil {
+			return nil, fmt.Errorf("error reading .dockerignore: %w", err)
+		}
+	}
+
+	globalArgs := make(map[string]string)
+	var stages []stage
+	for _, inst := range instructions {
+		switch inst.Cmd {
+		case "FROM":
+			s := stage{index: len(stages), instructions: []instruction{inst}}
+			if len(inst.Args) > 0 {
+				s.base = expandArgs(inst.Args[0], globalArgs)
+			}
+			if len(inst.Args) == 3 && strings.EqualFold(inst.Args[1], "AS") {
+				s.name = strings.ToLower(inst.Args[2])
+			}
+			stages = append(stages, s)
+			continue
+		case "ARG":
+			if len(stages) == 0 {
+				for _, a := range inst.Args {
+					k, v, _ := strings.Cut(a, "=")
+					globalArgs[k] = strings.Trim(v, `"'`)
+				}
+			}
+		}
+		if len(stages) > 0 {
+			s := &stages[len(stages)-1]
+			s.instructions = append(s.instructions, inst)
+		}
+	}
+
+	stageNames := make(map[string]int, len(stages))
+	for _, s := range stages {
+		if s.name != "" {
+			stageNames[s.name] = s.index
+		}
+	}
+	stageRef := func(name string) (int, bool) {
+		if i, ok := stageNames[strings.ToLower(name)]; ok {
+			return i, true
+		}
+		if i, err := strconv.Atoi(name); err == nil && i >= 0 && i < len(stages) {
+			return i, true
+		}
+		return 0, false
+	}
+
+	for _, inst := range instructions {
+		switch inst.Cmd {
+		case "FROM":
+			if len(stages) == 0 || len(inst.Args) == 0 {
+				continue
+			}
+			base := expandArgs(inst.Args[0], globalArgs)
+			if _, isStage := stageRef(base); isStage || base == "scratch" || strings.Contains(base, "$") {
+				continue
+			}
+			if ref, err := reference.ParseNormalizedNamed(base); err == nil {
+				if _, digested := ref.(reference.Canonical); !digested {
+					if tagged, ok := ref.(reference.Tagged); !ok || tagged.Tag() == "latest" {
+						report("LatestBaseImageTag", inst, "base image %q uses the latest tag; use a specific version to make builds reproducible", base)
+					}
+				}
+			}
+		case "RUN":
+			if aptGetUpdateWithoutInstall(inst.Body) {
+				report("AptGetUpdateWithoutInstall", inst, "apt-get update is used without apt-get install in the same RUN instruction; the package index is cached in this layer and may be outdated in later RUN instructions")
+			}
+		case "ENV", "ARG":
+			for _, k := range envKeys(inst) {
+				if isSecretName(k) {
+					report("SecretInEnvOrArg", inst, "%s %q may contain a secret; values of ENV and ARG are stored in the image, use build secrets instead", inst.Cmd, k)
+				}
+			}
+		case "ADD", "COPY":
+			if _, hasFrom := inst.flag("from"); hasFrom || len(inst.Args) < 2 {
+				continue
+			}
+			sources := inst.Args[:len(inst.Args)-1]
+			if inst.Cmd == "ADD" {
+				if _, hasChecksum := inst.flag("checksum"); !hasChecksum && allPlainFiles(sources) {
+					report("AddInsteadOfCopy", inst, "use COPY instead of ADD to copy files and directories; ADD also extracts archives and downloads URLs")
+				}
+			}
+			if ignored == nil {
+				continue
+			}
+			for _, src := range sources {
+				if isRemoteSource(src) || strings.HasPrefix(src, "<<") {
+					continue
+				}
+				if excluded, err := isExcludedSource(contextDir, ignored, src); err != nil {
+					return nil, err
+				} else if excluded {
+					report("CopyIgnoredSource", inst, "%s source %q is excluded by .dockerignore, and is not sent to the builder", inst.Cmd, src)
+				}
+			}
+		}
+	}
+
+	if len(stages) > 0 {
+		final := stages[len(stages)-1]
+		if !runsAsNonRoot(final, stages, stageRef) {
+			report("MissingUser", final.instructions[0], "the final stage runs as root; add a USER instruction to run as a non-root user")
+		}
+
+		used := make([]bool, len(stages))
+		var visit func(i int)
+		visit = func(i int) {
+			if used[i] {
+				return
+			}
+			used[i] = true
+			for _, dep := range stageDeps(stages[i], stageRef) {
+				visit(dep)
+			}
+		}
+		visit(final.index)
+		for i, s := range stages {
+			if !used[i] {
+				report("UnusedStage", s.instructions[0], "stage %q is not used to build the final stage", s.String())
+			}
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Line < findings[j].Line
+	})
+	return findings, nil
+}
+
+var argRe = regexp.MustCompile(`\$\{?(\w+)\}?`)
+
+// expandArgs replaces references to global build-args with their default
+// values. References to build-args without a default are left as-is.
+func expandArgs(s string, args map[string]string) string {
+	return argRe.ReplaceAllStringFunc(s, func(m string) string {
+		if v, ok := args[argRe.FindStringSubmatch(m)[1]]; ok && v != "" {
+			return v
+		}
+		return m
+	})
+}
+
+var shellSeparatorRe = regexp.MustCompile(`&&|\|\||[;|\n]`)
+
+// aptGetUpdateWithoutInstall returns true if the command runs
+// "apt-get update", but not "apt-get install".
+func aptGetUpdateWithoutInstall(cmd string) bool {
+	var update, install bool
+	for _, c := range shellSeparatorRe.Split(cmd, -1) {
+		fields := strings.Fields(c)
+		for i, f := range fields {
+			if f != "apt-get" && f != "apt" {
+				continue
+			}
+			for _, arg := range fields[i+1:] {
+				if strings.HasPrefix(arg, "-") {
+					continue
+				}
+				switch arg {
+				case "update":
+					update = true
+				case "install":
+					install = true
+				}
+				break
+			}
+		}
+	}
+	return update && !install
+}
+
+// envKeys returns the names of the variables defined by an ENV or ARG
+// instruction.
+func envKeys(inst instruction) []string {
+	if inst.Cmd == "ENV" && len(inst.Args) > 0 && !strings.Contains(inst.Args[0], "=") {
+		// Legacy "ENV key value" form.
+		return inst.Args[:1]
+	}
+	keys := make([]string, 0, len(inst.Args))
+	for _, a := range inst.Args {
+		if k, _, ok := strings.Cut(a, "="); ok || inst.Cmd == "ARG" {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+var secretNames = []string{"password", "passwd", "secret", "token", "apikey", "api_key", "access_key", "private_key", "credential"}
+
+func isSecretName(name string) bool {
+	name = strings.ToLower(name)
+	for _, s := range secretNames {
+		if strings.Contains(name, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func isRemoteSource(src string) bool {
+	return strings.Contains(src, "://") || strings.HasPrefix(src, "git@")
+}
+
+// allPlainFiles returns true if none of the sources of an ADD instruction
+// are URLs, Git repositories, or archives that are extracted by ADD.
+func allPlainFiles(sources []string) bool {
+	for _, src := range sources {
+		if isRemoteSource(src) || strings.Contains(src, "$") || strings.HasPrefix(src, "<<") {
+			return false
+		}
+		for _, ext := range []string{".tar", ".tar.gz", ".tgz", ".tar.bz2", ".tbz2", ".tar.xz", ".txz", ".tar.zst"} {
+			if strings.HasSuffix(strings.ToLower(src), ext) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// isExcludedSource returns true if a COPY or ADD source is excluded from the
+// build context. For sources with wildcards, it returns true if the
+// wildcard matches files in the context, and all of them are excluded.
+func isExcludedSource(contextDir string, ignored *patternmatcher.PatternMatcher, src string) (bool, error) {
+	src = path.Clean("/" + filepath.ToSlash(src))[1:]
+	if src == "" || strings.Contains(src, "$") {
+		return false, nil
+	}
+	if !strings.ContainsAny(src, `*?[`) {
+		return ignored.MatchesOrParentMatches(src)
+	}
+	if contextDir == "" {
+		return false, nil
+	}
+	matches, err := filepath.Glob(filepath.Join(contextDir, filepath.FromSlash(src)))
+	if err != nil || len(matches) == 0 {
+		return false, nil //nolint:nilerr // invalid patterns are reported by the builder
+	}
+	for _, m := range matches {
+		rel, err := filepath.Rel(contextDir, m)
+		if err != nil {
+			return false, err
+		}
+		if excluded, err := ignored.MatchesOrParentMatches(filepath.ToSlash(rel)); err != nil || !excluded {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// runsAsNonRoot returns true if the last USER instruction of the stage, or
+// of the stages it is based on, sets a non-root user.
+func runsAsNonRoot(s stage, stages []stage, stageRef func(string) (int, bool)) bool {
+	for seen := make(map[int]bool); !seen[s.index]; {
+		seen[s.index] = true
+		for i := len(s.instructions) - 1; i >= 0; i-- {
+			if inst := s.instructions[i]; inst.Cmd == "USER" && len(inst.Args) > 0 {
+				user, _, _ := strings.Cut(inst.Args[0], ":")
+				return user != "root" && user != "0"
+			}
+		}
+		base, ok := stageRef(s.base)
+		if !ok {
+			return false
+		}
+		s = stages[base]
+	}
+	return false
+}
+
+// stageDeps returns the stages that a stage depends on, through FROM,
+// COPY --from, or RUN --mount=from=.
+func stageDeps(s stage, stageRef func(string) (int, bool)) []int {
+	var deps []int
+	if i, ok := stageRef(s.base); ok {
+		deps = append(deps, i)
+	}
+	for _, inst := range s.instructions {
+		if from, ok := inst.flag("from"); ok {
+			if i, ok := stageRef(from); ok {
+				deps = append(deps, i)
+			}
+		}
+		if inst.Cmd != "RUN" {
+			continue
+		}
+		for _, f := range inst.Flags {
+			mount, ok := strings.CutPrefix(f, "--mount=")
+			if !ok {
+				continue
+			}
+			for _, opt := range strings.Split(mount, ",") {
+				if k, v, _ := strings.Cut(opt, "="); k == "from" {
+					if i, ok := stageRef(v); ok {
+						deps = append(deps, i)
+					}
+				}
+			}
+		}
+	}
+	return deps
+}
@@ -0,0 +1,149 @@
+package image
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/streams"
+	"github.com/docker/cli/internal/test"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/fs"
+	"gotest.tools/v3/golden"
+)
+
+func TestParseDockerfile(t *testing.T) {
+	instructions, err := parseDockerfile(strings.NewReader(`# escape=` + "`" + `
+FROM --platform=$BUILDPLATFORM alpine:3.20 AS build
+
+# comment
+RUN apk add ` + "`" + `
+    # comment in continuation
+    curl
+COPY ["a b", "/dst/"]
+RUN <<EOT
+echo hello
+EOT
+CMD ["/bin/sh"]
+`))
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(instructions, 5))
+
+	assert.Check(t, is.Equal(instructions[0].Cmd, "FROM"))
+	assert.Check(t, is.DeepEqual(instructions[0].Flags, []string{"--platform=$BUILDPLATFORM"}))
+	assert.Check(t, is.DeepEqual(instructions[0].Args, []string{"alpine:3.20", "AS", "build"}))
+	assert.Check(t, is.Equal(instructions[0].Line, 2))
+
+	assert.Check(t, is.DeepEqual(instructions[1].Args, []string{"apk", "add", "curl"}))
+	assert.Check(t, is.Equal(instructions[1].Line, 5))
+
+	assert.Check(t, is.DeepEqual(instructions[2].Args, []string{"a b", "/dst/"}))
+
+	assert.Check(t, is.Equal(instructions[3].Body, "<<EOT\necho hello"))
+	assert.Check(t, is.Equal(instructions[4].Cmd, "CMD"))
+	assert.Check(t, is.Equal(instructions[4].Line, 12))
+}
+
+func TestLintDockerfile(t *testing.T) {
+	dir := fs.NewDir(t, t.Name(),
+		fs.WithFile(".dockerignore", "node_modules\n*.log\n"),
+		fs.WithFile("app.log", ""),
+	)
+	instructions, err := parseDockerfile(strings.NewReader(`ARG GO_VERSION=1.24
+FROM golang:${GO_VERSION} AS builder
+RUN apt-get update
+RUN apt-get update && \
+    apt-get install -y git
+ENV API_TOKEN=abc FOO=bar
+ARG DB_PASSWORD
+COPY node_modules/ *.log go.mod /src/
+ADD config.json /etc/app/
+ADD app.tar.gz https://example.com/file /opt/
+
+FROM ubuntu AS unused
+
+FROM builder AS runtime
+USER app
+
+FROM runtime
+COPY --from=builder /src/app /app
+`))
+	assert.NilError(t, err)
+	findings, err := lintDockerfile("Dockerfile", instructions, dir.Path(), []string{"node_modules", "*.log"})
+	assert.NilError(t, err)
+
+	var actual []string
+	for _, f := range findings {
+		actual = append(actual, fmt.Sprintf("%s:%d %s", f.File, f.Line, f.Rule))
+	}
+	assert.Check(t, is.DeepEqual(actual, []string{
+		"Dockerfile:3 AptGetUpdateWithoutInstall",
+		"Dockerfile:6 SecretInEnvOrArg",
+		"Dockerfile:7 SecretInEnvOrArg",
+		"Dockerfile:8 CopyIgnoredSource",
+		"Dockerfile:8 CopyIgnoredSource",
+		"Dockerfile:9 AddInsteadOfCopy",
+		"Dockerfile:12 LatestBaseImageTag",
+		"Dockerfile:12 UnusedStage",
+	}))
+}
+
+func TestLintCommand(t *testing.T) {
+	fakeCLI := test.NewFakeCli(&fakeClient{})
+	fakeCLI.SetIn(streams.NewIn(io.NopCloser(strings.NewReader("FROM alpine:3.20\nUSER nobody\n"))))
+	cmd := newLintCommand(fakeCLI)
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"-"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(fakeCLI.OutBuffer().String(), ""))
+
+	fakeCLI = test.NewFakeCli(&fakeClient{})
+	fakeCLI.SetIn(streams.NewIn(io.NopCloser(strings.NewReader("FROM alpine\nADD . /app\n"))))
+	cmd = newLintCommand(fakeCLI)
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"--format", "sarif", "-"})
+	err := cmd.Execute()
+	var statusErr cli.StatusError
+	assert.Check(t, errors.As(err, &statusErr))
+	assert.Check(t, is.Equal(statusErr.StatusCode, 1))
+	golden.Assert(t, fakeCLI.OutBuffer().String(), "lint-command.sarif.golden")
+}
+
+func TestLintCommandErrors(t *testing.T) {
+	testCases := []struct {
+		name          string
+		args          []string
+		expectedError string
+	}{
+		{
+			name:          "too many args",
+			args:          []string{"Dockerfile", "Dockerfile.dev"},
+			expectedError: "requires at most 1 argument",
+		},
+		{
+			name:          "invalid format",
+			args:          []string{"--format", "xml", "Dockerfile"},
+			expectedError: `invalid format "xml": must be one of json, sarif`,
+		},
+		{
+			name:          "missing file",
+			args:          []string{"no-such-Dockerfile"},
+			expectedError: "no-such-Dockerfile",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := newLintCommand(test.NewFakeCli(&fakeClient{}))
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			cmd.SetArgs(tc.args)
+			assert.ErrorContains(t, cmd.Execute(), tc.expectedError)
+		})
+	}
+}
@@ -63,6 +63,10 @@ func runLoad(ctx context.Context, dockerCli command.Cli, opts loadOptions) error
 		if dockerCli.In().IsTerminal() {
 			return errors.New("requested load from stdin, but stdin is empty")
 		}
+
+		// The archive is streamed to the daemon as-is, and not checked for
+		// missing layers, because that would require buffering it; the daemon
+		// rejects archives with layers that are not present locally.
 	default:
 		// We use sequential.Open to use sequential file access on Windows, avoiding
 		// depleting the standby list un-necessarily. On Linux, this equates to a regular os.Open.
@@ -71,6 +75,14 @@ func runLoad(ctx context.Context, dockerCli command.Cli, opts loadOptions) error
 			return err
 		}
 		defer file.Close()
+
+		// Archives produced with "docker save --exclude-layers-of" omit
+		// layers of the base image; make sure those are present before
+		// sending the archive to the daemon. Only the tar headers and the
+		// manifest are read, unless layers are missing from the archive.
+		if err := checkArchiveLayers(ctx, dockerCli.Client(), file); err != nil {
+			return err
+		}
 		input = file
 	}
 
@@ -0,0 +1,103 @@
+package image
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/distribution/reference"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/internal/jsonstream"
+)
+
+// defaultParallel is the default number of concurrent pulls or pushes when
+// operating on multiple images.
+const defaultParallel = 4
+
+// imageReferences returns the image references passed as arguments, and
+// those read from fileName, if set.
+func imageReferences(dockerCLI command.Cli, args []string, fileName string) ([]string, error) {
+	refs := append([]string{}, args...)
+	if fileName != "" {
+		fromFile, err := readReferences(dockerCLI, fileName)
+		if err != nil {
+			return nil, err
+		}
+		if len(fromFile) == 0 && len(args) == 0 {
+			return nil, fmt.Errorf("no image references found in %s", fileName)
+		}
+		refs = append(refs, fromFile...)
+	}
+	return refs, nil
+}
+
+// parseReferences parses the given image references, adding the default
+// tag to references without a tag or digest, and removing duplicates.
+func parseReferences(remotes []string) ([]reference.Named, error) {
+	refs := make([]reference.Named, 0, len(remotes))
+	seen := make(map[string]struct{}, len(remotes))
+	for _, remote := range remotes {
+		ref, err := reference.ParseNormalizedNamed(remote)
+		if err != nil {
+			return nil, err
+		}
+		ref = reference.TagNameOnly(ref)
+		if _, ok := seen[ref.String()]; ok {
+			continue
+		}
+		seen[ref.String()] = struct{}{}
+		refs = append(refs, ref)
+	}
+	return refs, nil
+}
+
+// readReferences reads image references from a file, one per line. Empty
+// lines and lines starting with "#" are ignored.
+func readReferences(dockerCLI command.Cli, fileName string) ([]string, error) {
+	var r io.Reader
+	if fileName == "-" {
+		r = dockerCLI.In()
+	} else {
+		f, err := os.Open(fileName)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	var refs []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		refs = append(refs, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read references from %s: %w", fileName, err)
+	}
+	return refs, nil
+}
+
+// summarizeResults prints the jobs that failed, and returns an error if
+// any of them failed.
+func summarizeResults(out io.Writer, action string, results []jsonstream.Result) error {
+	var failed int
+	for _, r := range results {
+		if r.Err != nil {
+			if failed == 0 {
+				_, _ = fmt.Fprintln(out)
+			}
+			failed++
+			_, _ = fmt.Fprintf(out, "Failed to %s %s: %v\n", action, r.Name, r.Err)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to %s %d of %d image(s)", action, failed, len(results))
+	}
+	return nil
+}
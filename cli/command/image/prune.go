@@ -6,6 +6,7 @@
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
@@ -25,9 +26,14 @@ func init() {
 }
 
 type pruneOptions struct {
-	force  bool
-	all    bool
-	filter opts.FilterOpt
+	force          bool
+	all            bool
+	filter         opts.FilterOpt
+	dryRun         bool
+	policyFile     string
+	keepLast       int
+	keepUsedWithin time.Duration
+	keep           []string
 }
 
 // newPruneCommand returns a new cobra prune command for images
@@ -39,15 +45,20 @@ func newPruneCommand(dockerCLI command.Cli) *cobra.Command {
 		Short: "Remove unused images",
 		Args:  cli.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if options.dryRun {
+				return runPruneDryRun(cmd.Context(), dockerCLI, options)
+			}
 			spaceReclaimed, output, err := runPrune(cmd.Context(), dockerCLI, options)
-			if err != nil {
+			if err != nil && output == "" && spaceReclaimed == 0 {
 				return err
 			}
+			// Print the images that were removed, even if removing other
+			// images failed.
 			if output != "" {
 				fmt.Fprintln(dockerCLI.Out(), output)
 			}
 			fmt.Fprintln(dockerCLI.Out(), "Total reclaimed space:", units.HumanSize(float64(spaceReclaimed)))
-			return nil
+			return err
 		},
 		Annotations:           map[string]string{"version": "1.25"},
 		ValidArgsFunction:     cobra.NoFileCompletions,
@@ -58,6 +69,11 @@ func newPruneCommand(dockerCLI command.Cli) *cobra.Command {
 	flags.BoolVarP(&options.force, "force", "f", false, "Do not prompt for confirmation")
 	flags.BoolVarP(&options.all, "all", "a", false, "Remove all unused images, not just dangling ones")
 	flags.Var(&options.filter, "filter", `Provide filter values (e.g. "until=<timestamp>")`)
+	flags.StringVar(&options.policyFile, "policy", "", "Path to a retention policy file")
+	flags.IntVar(&options.keepLast, "keep-last", 0, "Keep the given number of most recent tags per repository")
+	flags.DurationVar(&options.keepUsedWithin, "keep-used-within", 0, "Keep images used by a container within the given duration (ns|us|ms|s|m|h)")
+	flags.StringSliceVar(&options.keep, "keep", nil, `Keep images matching the given reference pattern (e.g. "ubuntu:*")`)
+	flags.BoolVar(&options.dryRun, "dry-run", false, "Show which images would be removed by the retention policy, without removing them")
 
 	return cmd
 }
@@ -66,10 +82,73 @@ func newPruneCommand(dockerCLI command.Cli) *cobra.Command {
 	allImageWarning = `WARNING! This will remove all images without at least one container associated to them.
 Are you sure you want to continue?`
 	danglingWarning = `WARNING! This will remove all dangling images.
+Are you sure you want to continue?`
+	policyWarning = `WARNING! This will remove all image tags that are not retained by the retention policy.
 Are you sure you want to continue?`
 )
 
+// retentionPolicy returns the retention policy for the given options,
+// combining the policy file (if any) with the policy flags, which take
+// precedence.
+func (o pruneOptions) retentionPolicy() (retentionPolicy, error) {
+	var policy retentionPolicy
+	if o.policyFile != "" {
+		var err error
+		policy, err = loadRetentionPolicy(o.policyFile)
+		if err != nil {
+			return retentionPolicy{}, err
+		}
+	}
+	if o.keepLast != 0 {
+		policy.KeepLast = o.keepLast
+	}
+	if o.keepUsedWithin != 0 {
+		policy.KeepUsedWithin = policyDuration(o.keepUsedWithin)
+	}
+	if len(o.keep) > 0 {
+		policy.Keep = append(policy.Keep, o.keep...)
+	}
+	if err := policy.validate(); err != nil {
+		return retentionPolicy{}, err
+	}
+	if !policy.isZero() {
+		if o.all {
+			return retentionPolicy{}, errors.New("conflicting options: --all cannot be used with a retention policy")
+		}
+		if len(o.filter.Value()) > 0 {
+			return retentionPolicy{}, errors.New("conflicting options: --filter cannot be used with a retention policy")
+		}
+	}
+	return policy, nil
+}
+
+// runPruneDryRun prints which image tags would be kept or removed by the
+// retention policy.
+func runPruneDryRun(ctx context.Context, dockerCli command.Cli, options pruneOptions) error {
+	policy, err := options.retentionPolicy()
+	if err != nil {
+		return err
+	}
+	if policy.isZero() {
+		return errors.New("--dry-run requires a retention policy")
+	}
+	now := time.Now()
+	decisions, err := evaluateRetention(ctx, dockerCli.Client(), policy, now)
+	if err != nil {
+		return err
+	}
+	return writeRetentionTable(dockerCli.Out(), decisions, now)
+}
+
 func runPrune(ctx context.Context, dockerCli command.Cli, options pruneOptions) (spaceReclaimed uint64, output string, err error) {
+	policy, err := options.retentionPolicy()
+	if err != nil {
+		return 0, "", err
+	}
+	if !policy.isZero() {
+		return runPolicyPrune(ctx, dockerCli, options, policy)
+	}
+
 	pruneFilters := command.PruneFilters(dockerCli, options.filter.Value())
 	pruneFilters.Add("dangling", strconv.FormatBool(!options.all))
 
@@ -113,6 +192,26 @@ func runPrune(ctx context.Context, dockerCli command.Cli, options pruneOptions)
 	return res.Report.SpaceReclaimed, sb.String(), nil
 }
 
+// runPolicyPrune removes all image tags that are not retained by the
+// retention policy.
+func runPolicyPrune(ctx context.Context, dockerCli command.Cli, options pruneOptions, policy retentionPolicy) (spaceReclaimed uint64, output string, err error) {
+	if !options.force {
+		r, err := prompt.Confirm(ctx, dockerCli.In(), dockerCli.Out(), policyWarning)
+		if err != nil {
+			return 0, "", err
+		}
+		if !r {
+			return 0, "", cancelledErr{errors.New("image prune has been cancelled")}
+		}
+	}
+
+	decisions, err := evaluateRetention(ctx, dockerCli.Client(), policy, time.Now())
+	if err != nil {
+		return 0, "", err
+	}
+	return removeByPolicy(ctx, dockerCli.Client(), decisions)
+}
+
 type cancelledErr struct{ error }
 
 func (cancelledErr) Cancelled() {}
@@ -120,6 +219,26 @@ func (cancelledErr) Cancelled() {}
 // pruneFn calls the Image Prune API for use in "docker system prune",
 // and returns the amount of space reclaimed and a detailed output string.
 func pruneFn(ctx context.Context, dockerCLI command.Cli, options pruner.PruneOptions) (uint64, string, error) {
+	if options.ImagePolicy != "" {
+		if options.All {
+			return 0, "", errors.New("conflicting options: --all cannot be used with --image-policy")
+		}
+		if len(options.Filter.Value()) > 0 {
+			return 0, "", errors.New("conflicting options: --filter cannot be used with --image-policy")
+		}
+		if !options.Confirmed {
+			// Dry-run: validate the policy and produce confirmation before pruning.
+			if _, err := loadRetentionPolicy(options.ImagePolicy); err != nil {
+				return 0, "", err
+			}
+			return 0, "all image tags not retained by the retention policy in " + options.ImagePolicy, cancelledErr{errors.New("image prune has been cancelled")}
+		}
+		return runPrune(ctx, dockerCLI, pruneOptions{
+			force:      true,
+			policyFile: options.ImagePolicy,
+			filter:     opts.NewFilterOpt(),
+		})
+	}
 	if !options.Confirmed {
 		// Dry-run: perform validation and produce confirmation before pruning.
 		var confirmMsg string
@@ -0,0 +1,278 @@
+package image
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/distribution/reference"
+	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/cli/cli/command/formatter/tabwriter"
+	"github.com/docker/go-units"
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/image"
+	"github.com/moby/moby/client"
+)
+
+// retentionPolicy describes which tagged images to keep when pruning images
+// with a policy. Tags that are not retained by any of the rules are removed.
+type retentionPolicy struct {
+	// KeepLast is the number of most recently created tags to keep for
+	// each repository.
+	KeepLast int `json:"keepLast,omitempty"`
+
+	// KeepUsedWithin keeps images that are used by a container that is
+	// running, or that was used within the given duration.
+	KeepUsedWithin policyDuration `json:"keepUsedWithin,omitempty"`
+
+	// Keep is a list of reference patterns (see [path.Match]) of images
+	// to always keep, for example "ubuntu:*" or "myorg/*".
+	Keep []string `json:"keep,omitempty"`
+}
+
+// policyDuration is a [time.Duration] that is represented as a string
+// (for example, "168h") in a policy file.
+type policyDuration time.Duration
+
+func (d *policyDuration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("invalid duration: %s", string(b))
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("invalid duration %q: %w", s, err)
+	}
+	*d = policyDuration(v)
+	return nil
+}
+
+func (d policyDuration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// isZero returns whether the policy has no rules configured.
+func (p retentionPolicy) isZero() bool {
+	return p.KeepLast == 0 && p.KeepUsedWithin == 0 && len(p.Keep) == 0
+}
+
+func (p retentionPolicy) validate() error {
+	if p.KeepLast < 0 {
+		return fmt.Errorf("invalid retention policy: keep-last must be a positive number: %d", p.KeepLast)
+	}
+	if p.KeepUsedWithin < 0 {
+		return fmt.Errorf("invalid retention policy: keep-used-within must be a positive duration: %s", time.Duration(p.KeepUsedWithin))
+	}
+	for _, pattern := range p.Keep {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid retention policy: invalid reference pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// loadRetentionPolicy reads a retention policy from a JSON file.
+func loadRetentionPolicy(fileName string) (retentionPolicy, error) {
+	var policy retentionPolicy
+	f, err := os.Open(fileName)
+	if err != nil {
+		return policy, err
+	}
+	defer f.Close()
+
+	dec := json.NewDecoder(f)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&policy); err != nil && !errors.Is(err, io.EOF) {
+		return policy, fmt.Errorf("invalid retention policy %s: %w", fileName, err)
+	}
+	return policy, policy.validate()
+}
+
+// retentionDecision is the result of evaluating a retention policy for a
+// single image reference.
+type retentionDecision struct {
+	Reference string
+	ID        string
+	Created   time.Time
+	Size      int64
+	Keep      bool
+	Reason    string
+}
+
+// evaluateRetention applies the retention policy to all tagged images, and
+// returns a decision for each tag, ordered by repository and creation date
+// (most recent first).
+func evaluateRetention(ctx context.Context, apiClient client.APIClient, policy retentionPolicy, now time.Time) ([]retentionDecision, error) {
+	images, err := apiClient.ImageList(ctx, client.ImageListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	lastUsed, err := imagesLastUsed(ctx, apiClient, now)
+	if err != nil {
+		return nil, err
+	}
+
+	type taggedImage struct {
+		ref reference.NamedTagged
+		img image.Summary
+	}
+	repos := make(map[string][]taggedImage)
+	for _, img := range images {
+		for _, t := range img.RepoTags {
+			ref, err := reference.ParseNormalizedNamed(t)
+			if err != nil {
+				continue
+			}
+			tagged, ok := ref.(reference.NamedTagged)
+			if !ok {
+				continue
+			}
+			repo := reference.FamiliarName(tagged)
+			repos[repo] = append(repos[repo], taggedImage{ref: tagged, img: img})
+		}
+	}
+
+	repoNames := make([]string, 0, len(repos))
+	for repo := range repos {
+		repoNames = append(repoNames, repo)
+	}
+	sort.Strings(repoNames)
+
+	var decisions []retentionDecision
+	for _, repo := range repoNames {
+		tags := repos[repo]
+		sort.SliceStable(tags, func(i, j int) bool {
+			if tags[i].img.Created != tags[j].img.Created {
+				return tags[i].img.Created > tags[j].img.Created
+			}
+			return tags[i].ref.Tag() < tags[j].ref.Tag()
+		})
+		for i, t := range tags {
+			d := retentionDecision{
+				Reference: reference.FamiliarString(t.ref),
+				ID:        t.img.ID,
+				Created:   time.Unix(t.img.Created, 0),
+				Size:      t.img.Size,
+			}
+			d.Keep, d.Reason = policy.retain(t.ref, i, lastUsed[t.img.ID], now)
+			decisions = append(decisions, d)
+		}
+	}
+	return decisions, nil
+}
+
+// retain returns whether the policy retains the given reference, and the
+// reason for the decision. rank is the position of the reference in its
+// repository, ordered by creation date (most recent first), and lastUsed
+// the last time the image was used by a container, if any.
+func (p retentionPolicy) retain(ref reference.Named, rank int, lastUsed time.Time, now time.Time) (bool, string) {
+	for _, pattern := range p.Keep {
+		if ok, _ := reference.FamiliarMatch(pattern, ref); ok {
+			return true, "matches " + pattern
+		}
+	}
+	if !lastUsed.IsZero() {
+		switch {
+		case lastUsed.Equal(now):
+			return true, "in use by a running container"
+		case p.KeepUsedWithin == 0:
+			return true, "in use by a container"
+		case now.Sub(lastUsed) <= time.Duration(p.KeepUsedWithin):
+			return true, "used by a container within " + time.Duration(p.KeepUsedWithin).String()
+		}
+	}
+	if rank < p.KeepLast {
+		return true, fmt.Sprintf("one of the %d most recent tags", p.KeepLast)
+	}
+	if p.KeepLast > 0 {
+		return false, fmt.Sprintf("older than the %d most recent tags", p.KeepLast)
+	}
+	return false, "not retained by policy"
+}
+
+// imagesLastUsed returns the last time each image was used by a container.
+// Images used by running containers are considered in use at "now".
+func imagesLastUsed(ctx context.Context, apiClient client.APIClient, now time.Time) (map[string]time.Time, error) {
+	containers, err := apiClient.ContainerList(ctx, client.ContainerListOptions{All: true})
+	if err != nil {
+		return nil, err
+	}
+	lastUsed := make(map[string]time.Time)
+	for _, c := range containers {
+		used := time.Unix(c.Created, 0)
+		switch c.State {
+		case container.StateRunning, container.StatePaused, container.StateRestarting:
+			used = now
+		default:
+			if ctr, err := apiClient.ContainerInspect(ctx, c.ID); err == nil && ctr.State != nil {
+				if t, err := time.Parse(time.RFC3339Nano, ctr.State.FinishedAt); err == nil && t.After(used) {
+					used = t
+				}
+			}
+		}
+		if used.After(lastUsed[c.ImageID]) {
+			lastUsed[c.ImageID] = used
+		}
+	}
+	return lastUsed, nil
+}
+
+// writeRetentionTable writes the retention decisions as a table.
+func writeRetentionTable(out io.Writer, decisions []retentionDecision, now time.Time) error {
+	w := tabwriter.NewWriter(out, 10, 1, 3, ' ', 0)
+	_, _ = fmt.Fprintln(w, "IMAGE\tID\tCREATED\tACTION\tREASON")
+	for _, d := range decisions {
+		action := "delete"
+		if d.Keep {
+			action = "keep"
+		}
+		created := units.HumanDuration(now.Sub(d.Created)) + " ago"
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", d.Reference, formatter.TruncateID(d.ID), created, action, d.Reason)
+	}
+	return w.Flush()
+}
+
+// removeByPolicy removes the references that are not retained by the policy,
+// and returns the amount of space reclaimed and the removal output.
+func removeByPolicy(ctx context.Context, apiClient client.APIClient, decisions []retentionDecision) (uint64, string, error) {
+	var (
+		spaceReclaimed uint64
+		sb             strings.Builder
+		errs           []error
+	)
+	for _, d := range decisions {
+		if d.Keep {
+			continue
+		}
+		res, err := apiClient.ImageRemove(ctx, d.Reference, client.ImageRemoveOptions{PruneChildren: true})
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if sb.Len() == 0 {
+			sb.WriteString("Deleted Images:\n")
+		}
+		for _, st := range res {
+			if st.Untagged != "" {
+				sb.WriteString("untagged: ")
+				sb.WriteString(st.Untagged)
+				sb.WriteByte('\n')
+			} else {
+				sb.WriteString("deleted: ")
+				sb.WriteString(st.Deleted)
+				sb.WriteByte('\n')
+				if st.Deleted == d.ID && d.Size > 0 {
+					spaceReclaimed += uint64(d.Size)
+				}
+			}
+		}
+	}
+	return spaceReclaimed, sb.String(), errors.Join(errs...)
+}
@@ -0,0 +1,252 @@
+package image
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/distribution/reference"
+	"github.com/docker/cli/cli/command/system/pruner"
+	"github.com/docker/cli/internal/test"
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/image"
+	"github.com/moby/moby/client"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/golden"
+)
+
+func TestRetentionPolicyRetain(t *testing.T) {
+	now := time.Now()
+	ref, err := reference.ParseNormalizedNamed("myorg/app:v1")
+	assert.NilError(t, err)
+
+	testCases := []struct {
+		name     string
+		policy   retentionPolicy
+		rank     int
+		lastUsed time.Time
+		keep     bool
+		reason   string
+	}{
+		{
+			name:   "matches pattern",
+			policy: retentionPolicy{Keep: []string{"myorg/*"}},
+			rank:   10,
+			keep:   true,
+			reason: "matches myorg/*",
+		},
+		{
+			name:     "running container",
+			policy:   retentionPolicy{KeepLast: 1},
+			rank:     10,
+			lastUsed: now,
+			keep:     true,
+			reason:   "in use by a running container",
+		},
+		{
+			name:     "stopped container",
+			policy:   retentionPolicy{KeepLast: 1},
+			rank:     10,
+			lastUsed: now.Add(-30 * 24 * time.Hour),
+			keep:     true,
+			reason:   "in use by a container",
+		},
+		{
+			name:     "used within",
+			policy:   retentionPolicy{KeepUsedWithin: policyDuration(168 * time.Hour)},
+			lastUsed: now.Add(-24 * time.Hour),
+			keep:     true,
+			reason:   "used by a container within 168h0m0s",
+		},
+		{
+			name:     "not used within",
+			policy:   retentionPolicy{KeepUsedWithin: policyDuration(168 * time.Hour)},
+			lastUsed: now.Add(-30 * 24 * time.Hour),
+			keep:     false,
+			reason:   "not retained by policy",
+		},
+		{
+			name:   "keep last",
+			policy: retentionPolicy{KeepLast: 2},
+			rank:   1,
+			keep:   true,
+			reason: "one of the 2 most recent tags",
+		},
+		{
+			name:   "older than keep last",
+			policy: retentionPolicy{KeepLast: 2},
+			rank:   2,
+			keep:   false,
+			reason: "older than the 2 most recent tags",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			keep, reason := tc.policy.retain(ref, tc.rank, tc.lastUsed, now)
+			assert.Check(t, is.Equal(keep, tc.keep))
+			assert.Check(t, is.Equal(reason, tc.reason))
+		})
+	}
+}
+
+func TestLoadRetentionPolicy(t *testing.T) {
+	dir := t.TempDir()
+	writePolicy := func(content string) string {
+		fileName := filepath.Join(dir, "policy.json")
+		assert.NilError(t, os.WriteFile(fileName, []byte(content), 0o644))
+		return fileName
+	}
+
+	policy, err := loadRetentionPolicy(writePolicy(`{"keepLast": 3, "keepUsedWithin": "168h", "keep": ["ubuntu:*"]}`))
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(policy, retentionPolicy{
+		KeepLast:       3,
+		KeepUsedWithin: policyDuration(168 * time.Hour),
+		Keep:           []string{"ubuntu:*"},
+	}))
+
+	_, err = loadRetentionPolicy(writePolicy(`{"keepUsedWithin": "7 days"}`))
+	assert.Check(t, is.ErrorContains(err, "invalid duration"))
+
+	_, err = loadRetentionPolicy(writePolicy(`{"keepLatest": 3}`))
+	assert.Check(t, is.ErrorContains(err, `unknown field "keepLatest"`))
+
+	_, err = loadRetentionPolicy(writePolicy(`{"keepLast": -1}`))
+	assert.Check(t, is.ErrorContains(err, "keep-last must be a positive number"))
+
+	_, err = loadRetentionPolicy(writePolicy(`{"keep": ["[-"]}`))
+	assert.Check(t, is.ErrorContains(err, "invalid reference pattern"))
+}
+
+func retentionTestClient(removed *[]string) *fakeClient {
+	now := time.Now()
+	return &fakeClient{
+		imageListFunc: func(client.ImageListOptions) ([]image.Summary, error) {
+			return []image.Summary{
+				{ID: "sha256:aaaaaaaaaaaaaaaaaaaa", RepoTags: []string{"app:v3"}, Created: now.Add(-24 * time.Hour).Unix(), Size: 100},
+				{ID: "sha256:bbbbbbbbbbbbbbbbbbbb", RepoTags: []string{"app:v2"}, Created: now.Add(-48 * time.Hour).Unix(), Size: 100},
+				{ID: "sha256:cccccccccccccccccccc", RepoTags: []string{"app:v1"}, Created: now.Add(-72 * time.Hour).Unix(), Size: 100},
+				{ID: "sha256:dddddddddddddddddddd", RepoTags: []string{"ubuntu:24.04"}, Created: now.Add(-96 * time.Hour).Unix(), Size: 100},
+				{ID: "sha256:eeeeeeeeeeeeeeeeeeee", RepoTags: []string{"<none>:<none>"}, Created: now.Add(-96 * time.Hour).Unix(), Size: 100},
+			}, nil
+		},
+		containerListFunc: func(client.ContainerListOptions) ([]container.Summary, error) {
+			return []container.Summary{
+				{ID: "running", ImageID: "sha256:cccccccccccccccccccc", State: container.StateRunning},
+			}, nil
+		},
+		imageRemoveFunc: func(img string, options client.ImageRemoveOptions) ([]image.DeleteResponse, error) {
+			*removed = append(*removed, img)
+			return []image.DeleteResponse{{Untagged: img}, {Deleted: "sha256:bbbbbbbbbbbbbbbbbbbb"}}, nil
+		},
+	}
+}
+
+func TestPruneDryRun(t *testing.T) {
+	var removed []string
+	cli := test.NewFakeCli(retentionTestClient(&removed))
+	cmd := newPruneCommand(cli)
+	cmd.SetOut(io.Discard)
+	cmd.SetArgs([]string{"--dry-run", "--keep-last", "1", "--keep", "ubuntu:*"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Len(removed, 0))
+	golden.Assert(t, cli.OutBuffer().String(), "prune-command-success.dry-run.golden")
+}
+
+func TestPruneWithPolicy(t *testing.T) {
+	var removed []string
+	cli := test.NewFakeCli(retentionTestClient(&removed))
+	cmd := newPruneCommand(cli)
+	cmd.SetOut(io.Discard)
+	cmd.SetArgs([]string{"--force", "--keep-last", "1", "--keep", "ubuntu:*"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.DeepEqual(removed, []string{"app:v2"}))
+	golden.Assert(t, cli.OutBuffer().String(), "prune-command-success.policy.golden")
+}
+
+func TestPruneWithPolicyRemoveError(t *testing.T) {
+	var removed []string
+	apiClient := retentionTestClient(&removed)
+	listFunc := apiClient.imageListFunc
+	apiClient.imageListFunc = func(options client.ImageListOptions) ([]image.Summary, error) {
+		images, err := listFunc(options)
+		return append(images, image.Summary{ID: "sha256:ffffffffffffffffffff", RepoTags: []string{"app:v0"}, Created: time.Now().Add(-120 * time.Hour).Unix(), Size: 100}), err
+	}
+	removeFunc := apiClient.imageRemoveFunc
+	apiClient.imageRemoveFunc = func(img string, options client.ImageRemoveOptions) ([]image.DeleteResponse, error) {
+		if img == "app:v0" {
+			return nil, errors.New("image is in use")
+		}
+		return removeFunc(img, options)
+	}
+	cli := test.NewFakeCli(apiClient)
+	cmd := newPruneCommand(cli)
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"--force", "--keep-last", "1"})
+
+	// the images that were removed are printed before the error is returned.
+	assert.Check(t, is.ErrorContains(cmd.Execute(), "image is in use"))
+	assert.Check(t, is.Contains(cli.OutBuffer().String(), "untagged: app:v2"))
+	assert.Check(t, is.Contains(cli.OutBuffer().String(), "Total reclaimed space: 100B"))
+}
+
+func TestPruneWithPolicyErrors(t *testing.T) {
+	testCases := []struct {
+		name          string
+		args          []string
+		expectedError string
+	}{
+		{
+			name:          "dry-run without policy",
+			args:          []string{"--dry-run"},
+			expectedError: "--dry-run requires a retention policy",
+		},
+		{
+			name:          "policy with all",
+			args:          []string{"--keep-last", "1", "--all"},
+			expectedError: "conflicting options: --all cannot be used with a retention policy",
+		},
+		{
+			name:          "policy with filter",
+			args:          []string{"--keep-last", "1", "--filter", "label=foo"},
+			expectedError: "conflicting options: --filter cannot be used with a retention policy",
+		},
+		{
+			name:          "missing policy file",
+			args:          []string{"--policy", "no-such-file.json"},
+			expectedError: "no-such-file.json",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := newPruneCommand(test.NewFakeCli(&fakeClient{}))
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			cmd.SetArgs(tc.args)
+			assert.ErrorContains(t, cmd.Execute(), tc.expectedError)
+		})
+	}
+}
+
+func TestPruneFnWithPolicy(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "policy.json")
+	assert.NilError(t, os.WriteFile(fileName, []byte(`{"keepLast": 1, "keep": ["ubuntu:*"]}`), 0o644))
+
+	var removed []string
+	cli := test.NewFakeCli(retentionTestClient(&removed))
+	_, msg, err := pruneFn(context.Background(), cli, pruner.PruneOptions{ImagePolicy: fileName})
+	assert.Check(t, is.ErrorContains(err, "image prune has been cancelled"))
+	assert.Check(t, is.Equal(msg, "all image tags not retained by the retention policy in "+fileName))
+	assert.Check(t, is.Len(removed, 0))
+
+	spaceReclaimed, _, err := pruneFn(context.Background(), cli, pruner.PruneOptions{Confirmed: true, ImagePolicy: fileName})
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(spaceReclaimed, uint64(100)))
+	assert.Check(t, is.DeepEqual(removed, []string{"app:v2"}))
+}
@@ -14,6 +14,7 @@
 	"github.com/docker/cli/cli/streams"
 	"github.com/docker/cli/cli/trust"
 	"github.com/docker/cli/internal/jsonstream"
+	"github.com/docker/cli/internal/registry"
 	"github.com/moby/moby/api/pkg/authconfig"
 	registrytypes "github.com/moby/moby/api/types/registry"
 	"github.com/moby/moby/client"
@@ -27,6 +28,8 @@ type pullOptions struct {
 	platform  string
 	quiet     bool
 	untrusted bool
+	file      string
+	parallel  int
 }
 
 // newPullCommand creates a new `docker pull` command
@@ -34,11 +37,23 @@ func newPullCommand(dockerCLI command.Cli) *cobra.Command {
 	var opts pullOptions
 
 	cmd := &cobra.Command{
-		Use:   "pull [OPTIONS] NAME[:TAG|@DIGEST]",
+		Use:   "pull [OPTIONS] NAME[:TAG|@DIGEST] [NAME[:TAG|@DIGEST]...]",
 		Short: "Download an image from a registry",
-		Args:  cli.ExactArgs(1),
+		Args: func(cmd *cobra.Command, args []string) error {
+			if opts.file != "" {
+				return nil
+			}
+			return cli.RequiresMinArgs(1)(cmd, args)
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			opts.remote = args[0]
+			remotes, err := imageReferences(dockerCLI, args, opts.file)
+			if err != nil {
+				return err
+			}
+			if len(remotes) != 1 {
+				return runPullMultiple(cmd.Context(), dockerCLI, remotes, opts)
+			}
+			opts.remote = remotes[0]
 			return runPull(cmd.Context(), dockerCLI, opts)
 		},
 		Annotations: map[string]string{
@@ -47,7 +62,7 @@ func newPullCommand(dockerCLI command.Cli) *cobra.Command {
 		},
 		// Complete with local images to help pulling the latest version
 		// of images that are in the image cache.
-		ValidArgsFunction:     completion.ImageNames(dockerCLI, 1),
+		ValidArgsFunction:     completion.ImageNames(dockerCLI, -1),
 		DisableFlagsInUseLine: true,
 	}
 
@@ -59,6 +74,8 @@ func newPullCommand(dockerCLI command.Cli) *cobra.Command {
 	flags.StringVar(&opts.platform, "platform", os.Getenv("DOCKER_DEFAULT_PLATFORM"), "Set platform if server is multi-platform capable")
 	_ = flags.SetAnnotation("platform", "version", []string{"1.32"})
 	_ = cmd.RegisterFlagCompletionFunc("platform", completion.Platforms())
+	flags.StringVarP(&opts.file, "file", "f", "", `Read image references from a file ("-" for STDIN)`)
+	flags.IntVar(&opts.parallel, "parallel", defaultParallel, "Maximum number of concurrent pulls when pulling multiple images")
 
 	return cmd
 }
@@ -121,3 +138,55 @@ func imagePullPrivileged(ctx context.Context, dockerCLI command.Cli, ref referen
 	}
 	return jsonstream.Display(ctx, responseBody, out)
 }
+
+// runPullMultiple pulls multiple images concurrently, and displays their
+// progress as a single stream.
+func runPullMultiple(ctx context.Context, dockerCLI command.Cli, remotes []string, opts pullOptions) error {
+	switch {
+	case opts.all:
+		return errors.New("--all-tags can't be used when pulling multiple images")
+	case !opts.untrusted:
+		return errors.New("content trust is not supported when pulling multiple images; use --disable-content-trust to pull without verification")
+	case opts.parallel < 1:
+		return errors.New("--parallel must be at least 1")
+	}
+
+	refs, err := parseReferences(remotes)
+	if err != nil {
+		return err
+	}
+	jobs := make([]jsonstream.Job, 0, len(refs))
+	for _, ref := range refs {
+		jobs = append(jobs, pullJob(dockerCLI, ref, opts.platform))
+	}
+
+	out := dockerCLI.Out()
+	if opts.quiet {
+		out = streams.NewOut(io.Discard)
+	}
+	results := jsonstream.RunParallel(ctx, out, opts.parallel, jobs)
+	for i, r := range results {
+		if r.Err == nil {
+			_, _ = fmt.Fprintln(dockerCLI.Out(), refs[i].String())
+		}
+	}
+	return summarizeResults(dockerCLI.Err(), "pull", results)
+}
+
+// pullJob returns a job that pulls the given reference.
+func pullJob(dockerCLI command.Cli, ref reference.Named, platform string) jsonstream.Job {
+	return jsonstream.Job{
+		Name: reference.FamiliarString(ref),
+		Run: func(ctx context.Context) (io.ReadCloser, error) {
+			authConfig := command.ResolveAuthConfig(dockerCLI.ConfigFile(), registry.NewIndexInfo(ref))
+			encodedAuth, err := authconfig.Encode(authConfig)
+			if err != nil {
+				return nil, err
+			}
+			return dockerCLI.Client().ImagePull(ctx, reference.FamiliarString(ref), client.ImagePullOptions{
+				RegistryAuth: encodedAuth,
+				Platform:     platform,
+			})
+		},
+	}
+}
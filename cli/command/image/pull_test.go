@@ -4,6 +4,10 @@
 	"errors"
 	"fmt"
 	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
 	"testing"
 
 	"github.com/docker/cli/internal/test"
@@ -22,7 +26,7 @@ func TestNewPullCommandErrors(t *testing.T) {
 	}{
 		{
 			name:          "wrong-args",
-			expectedError: "requires 1 argument",
+			expectedError: "requires at least 1 argument",
 			args:          []string{},
 		},
 		{
@@ -133,3 +137,72 @@ func TestNewPullCommandWithContentTrustErrors(t *testing.T) {
 		})
 	}
 }
+
+func TestPullMultiple(t *testing.T) {
+	refs := filepath.Join(t.TempDir(), "images.txt")
+	assert.NilError(t, os.WriteFile(refs, []byte("# CI cache\nredis:7\nbroken:1.0\nalpine\n"), 0o644))
+
+	var (
+		mu     sync.Mutex
+		pulled []string
+	)
+	cli := test.NewFakeCli(&fakeClient{
+		imagePullFunc: func(ref string, options client.ImagePullOptions) (client.ImagePullResponse, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			pulled = append(pulled, ref)
+			if ref == "broken:1.0" {
+				return client.ImagePullResponse{}, errors.New("manifest unknown")
+			}
+			return client.ImagePullResponse{}, nil
+		},
+	})
+	cmd := newPullCommand(cli)
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"--parallel", "2", "--file", refs, "alpine:latest", "busybox"})
+	assert.Check(t, is.Error(cmd.Execute(), "failed to pull 1 of 4 image(s)"))
+
+	sort.Strings(pulled)
+	assert.Check(t, is.DeepEqual(pulled, []string{"alpine:latest", "broken:1.0", "busybox:latest", "redis:7"}))
+	assert.Check(t, is.Contains(cli.OutBuffer().String(), "docker.io/library/alpine:latest\ndocker.io/library/busybox:latest\ndocker.io/library/redis:7\n"))
+	assert.Check(t, is.Contains(cli.ErrBuffer().String(), "Failed to pull broken:1.0: manifest unknown\n"))
+}
+
+func TestPullMultipleErrors(t *testing.T) {
+	testCases := []struct {
+		name          string
+		args          []string
+		expectedError string
+	}{
+		{
+			name:          "all-tags",
+			args:          []string{"--all-tags", "alpine", "busybox"},
+			expectedError: "--all-tags can't be used when pulling multiple images",
+		},
+		{
+			name:          "content-trust",
+			args:          []string{"--disable-content-trust=false", "alpine", "busybox"},
+			expectedError: "content trust is not supported when pulling multiple images",
+		},
+		{
+			name:          "invalid-parallel",
+			args:          []string{"--parallel", "0", "alpine", "busybox"},
+			expectedError: "--parallel must be at least 1",
+		},
+		{
+			name:          "missing-file",
+			args:          []string{"--file", "no-such-file.txt"},
+			expectedError: "no-such-file.txt",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := newPullCommand(test.NewFakeCli(&fakeClient{}))
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			cmd.SetArgs(tc.args)
+			assert.ErrorContains(t, cmd.Execute(), tc.expectedError)
+		})
+	}
+}
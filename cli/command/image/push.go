@@ -32,6 +32,8 @@ type pushOptions struct {
 	untrusted bool
 	quiet     bool
 	platform  string
+	file      string
+	parallel  int
 }
 
 // newPushCommand creates a new `docker push` command
@@ -39,18 +41,30 @@ func newPushCommand(dockerCLI command.Cli) *cobra.Command {
 	var opts pushOptions
 
 	cmd := &cobra.Command{
-		Use:   "push [OPTIONS] NAME[:TAG]",
+		Use:   "push [OPTIONS] NAME[:TAG] [NAME[:TAG]...]",
 		Short: "Upload an image to a registry",
-		Args:  cli.ExactArgs(1),
+		Args: func(cmd *cobra.Command, args []string) error {
+			if opts.file != "" {
+				return nil
+			}
+			return cli.RequiresMinArgs(1)(cmd, args)
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			opts.remote = args[0]
+			remotes, err := imageReferences(dockerCLI, args, opts.file)
+			if err != nil {
+				return err
+			}
+			if len(remotes) != 1 {
+				return runPushMultiple(cmd.Context(), dockerCLI, remotes, opts)
+			}
+			opts.remote = remotes[0]
 			return runPush(cmd.Context(), dockerCLI, opts)
 		},
 		Annotations: map[string]string{
 			"category-top": "6",
 			"aliases":      "docker image push, docker push",
 		},
-		ValidArgsFunction:     completion.ImageNames(dockerCLI, 1),
+		ValidArgsFunction:     completion.ImageNames(dockerCLI, -1),
 		DisableFlagsInUseLine: true,
 	}
 
@@ -67,6 +81,8 @@ func newPushCommand(dockerCLI command.Cli) *cobra.Command {
 Image index won't be pushed, meaning that other manifests, including attestations won't be preserved.
 'os[/arch[/variant]]': Explicit platform (eg. linux/amd64)`)
 	flags.SetAnnotation("platform", "version", []string{"1.46"})
+	flags.StringVarP(&opts.file, "file", "f", "", `Read image references from a file ("-" for STDIN)`)
+	flags.IntVar(&opts.parallel, "parallel", defaultParallel, "Maximum number of concurrent pushes when pushing multiple images")
 
 	_ = cmd.RegisterFlagCompletionFunc("platform", completion.Platforms())
 
@@ -143,6 +159,69 @@ func runPush(ctx context.Context, dockerCli command.Cli, opts pushOptions) error
 	return jsonstream.Display(ctx, responseBody, dockerCli.Out(), jsonstream.WithAuxCallback(handleAux()))
 }
 
+// runPushMultiple pushes multiple images concurrently, and displays their
+// progress as a single stream.
+func runPushMultiple(ctx context.Context, dockerCLI command.Cli, remotes []string, opts pushOptions) error {
+	switch {
+	case opts.all:
+		return errors.New("--all-tags can't be used when pushing multiple images")
+	case !opts.untrusted:
+		return errors.New("content trust is not supported when pushing multiple images; use --disable-content-trust to push without signing")
+	case opts.parallel < 1:
+		return errors.New("--parallel must be at least 1")
+	}
+
+	var platform *ocispec.Platform
+	if opts.platform != "" {
+		p, err := platforms.Parse(opts.platform)
+		if err != nil {
+			return fmt.Errorf("invalid platform %s: %w", opts.platform, err)
+		}
+		platform = &p
+	}
+
+	refs, err := parseReferences(remotes)
+	if err != nil {
+		return err
+	}
+	jobs := make([]jsonstream.Job, 0, len(refs))
+	for _, ref := range refs {
+		jobs = append(jobs, pushJob(dockerCLI, ref, platform))
+	}
+
+	out := dockerCLI.Out()
+	if opts.quiet {
+		out = streams.NewOut(io.Discard)
+	}
+	results := jsonstream.RunParallel(ctx, out, opts.parallel, jobs, jsonstream.WithAuxCallback(handleAux()))
+	for i, r := range results {
+		if r.Err == nil && opts.quiet {
+			_, _ = fmt.Fprintln(dockerCLI.Out(), refs[i].String())
+		}
+	}
+	for _, note := range notes {
+		tui.NewOutput(dockerCLI.Out()).PrintNote(note)
+	}
+	return summarizeResults(dockerCLI.Err(), "push", results)
+}
+
+// pushJob returns a job that pushes the given reference.
+func pushJob(dockerCLI command.Cli, ref reference.Named, platform *ocispec.Platform) jsonstream.Job {
+	return jsonstream.Job{
+		Name: reference.FamiliarString(ref),
+		Run: func(ctx context.Context) (io.ReadCloser, error) {
+			encodedAuth, err := command.RetrieveAuthTokenFromImage(dockerCLI.ConfigFile(), ref.String())
+			if err != nil {
+				return nil, err
+			}
+			return dockerCLI.Client().ImagePush(ctx, reference.FamiliarString(ref), client.ImagePushOptions{
+				RegistryAuth: encodedAuth,
+				Platform:     platform,
+			})
+		},
+	}
+}
+
 var notes []string
 
 func handleAux() func(jm jsonstream.JSONMessage) {
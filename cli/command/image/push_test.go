@@ -3,12 +3,15 @@
 import (
 	"errors"
 	"io"
+	"sort"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/docker/cli/internal/test"
 	"github.com/moby/moby/client"
 	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
 )
 
 func TestNewPushCommandErrors(t *testing.T) {
@@ -21,7 +24,7 @@ func TestNewPushCommandErrors(t *testing.T) {
 		{
 			name:          "wrong-args",
 			args:          []string{},
-			expectedError: "requires 1 argument",
+			expectedError: "requires at least 1 argument",
 		},
 		{
 			name:          "invalid-name",
@@ -84,3 +87,27 @@ func TestNewPushCommandSuccess(t *testing.T) {
 		})
 	}
 }
+
+func TestPushMultiple(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		pushed []string
+	)
+	cli := test.NewFakeCli(&fakeClient{
+		imagePushFunc: func(ref string, options client.ImagePushOptions) (io.ReadCloser, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			pushed = append(pushed, ref)
+			return io.NopCloser(strings.NewReader(`{"status":"Pushed","id":"abc123"}` + "\n")), nil
+		},
+	})
+	cmd := newPushCommand(cli)
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"--quiet", "registry.example.com/app:1.0", "registry.example.com/db", "registry.example.com/app:1.0"})
+	assert.NilError(t, cmd.Execute())
+
+	sort.Strings(pushed)
+	assert.Check(t, is.DeepEqual(pushed, []string{"registry.example.com/app:1.0", "registry.example.com/db:latest"}))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "registry.example.com/app:1.0\nregistry.example.com/db:latest\n"))
+}
@@ -0,0 +1,143 @@
+package image
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/containerd/platforms"
+	"github.com/distribution/reference"
+	manifesttypes "github.com/docker/cli/cli/manifest/types"
+	"github.com/docker/cli/internal/registryclient"
+	"github.com/docker/distribution"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// remoteImage is the information shown by "docker image inspect --remote".
+type remoteImage struct {
+	Name string
+	// Digest is the digest of the manifest, or of the index for
+	// multi-platform images. It is omitted for a multi-platform image that
+	// is referenced by tag, as the registry does not return it.
+	Digest digest.Digest `json:",omitempty"`
+	// Platforms contains an entry for each platform of a multi-platform
+	// image, or a single entry otherwise.
+	Platforms []remotePlatformImage
+}
+
+// remotePlatformImage describes a platform-specific image manifest and its
+// config.
+type remotePlatformImage struct {
+	Digest       digest.Digest
+	MediaType    string
+	Os           string
+	Architecture string
+	Variant      string     `json:",omitempty"`
+	Created      *time.Time `json:",omitempty"`
+	Author       string     `json:",omitempty"`
+	Config       ocispec.ImageConfig
+	Layers       []remoteLayer
+	// Size is the total (compressed) size of the layers.
+	Size int64
+}
+
+type remoteLayer struct {
+	Digest    digest.Digest
+	MediaType string
+	Size      int64
+}
+
+// inspectRemote fetches the manifest (or index) and image config for ref
+// from the registry, without pulling the image. If platform is set, only
+// the matching platform is included.
+func inspectRemote(ctx context.Context, registryClient registryclient.RegistryClient, ref string, platform *ocispec.Platform) (remoteImage, error) {
+	namedRef, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return remoteImage{}, err
+	}
+	namedRef = reference.TagNameOnly(namedRef)
+
+	result := remoteImage{Name: reference.FamiliarString(namedRef)}
+	if digested, ok := namedRef.(reference.Canonical); ok {
+		result.Digest = digested.Digest()
+	}
+
+	var manifests []manifesttypes.ImageManifest
+	if m, err := registryClient.GetManifest(ctx, namedRef); err == nil {
+		result.Digest = m.Descriptor.Digest
+		manifests = []manifesttypes.ImageManifest{m}
+	} else {
+		manifests, err = registryClient.GetManifestList(ctx, namedRef)
+		if err != nil {
+			return remoteImage{}, err
+		}
+	}
+
+	var matcher platforms.Matcher
+	if platform != nil {
+		matcher = platforms.NewMatcher(*platform)
+	}
+	for _, m := range manifests {
+		p := m.Descriptor.Platform
+		if p != nil && p.OS == "unknown" {
+			// Skip attestation manifests, which are not images.
+			continue
+		}
+		if matcher != nil && (p == nil || !matcher.Match(*p)) {
+			continue
+		}
+		img, err := remotePlatform(ctx, registryClient, namedRef, m)
+		if err != nil {
+			return remoteImage{}, err
+		}
+		result.Platforms = append(result.Platforms, img)
+	}
+	if len(result.Platforms) == 0 && platform != nil {
+		return remoteImage{}, fmt.Errorf("image %s has no manifest for platform %s", result.Name, platforms.FormatAll(*platform))
+	}
+	return result, nil
+}
+
+func remotePlatform(ctx context.Context, registryClient registryclient.RegistryClient, ref reference.Named, m manifesttypes.ImageManifest) (remotePlatformImage, error) {
+	var configDesc distribution.Descriptor
+	var layers []distribution.Descriptor
+	switch {
+	case m.SchemaV2Manifest != nil:
+		configDesc, layers = m.SchemaV2Manifest.Config, m.SchemaV2Manifest.Layers
+	case m.OCIManifest != nil:
+		configDesc, layers = m.OCIManifest.Config, m.OCIManifest.Layers
+	default:
+		return remotePlatformImage{}, fmt.Errorf("unsupported manifest for %s", m.Ref)
+	}
+
+	configJSON, err := registryClient.GetBlob(ctx, ref, configDesc.Digest)
+	if err != nil {
+		return remotePlatformImage{}, fmt.Errorf("failed to fetch image config for %s: %w", m.Ref, err)
+	}
+	var config ocispec.Image
+	if err := json.Unmarshal(configJSON, &config); err != nil {
+		return remotePlatformImage{}, fmt.Errorf("invalid image config for %s: %w", m.Ref, err)
+	}
+
+	img := remotePlatformImage{
+		Digest:       m.Descriptor.Digest,
+		MediaType:    m.Descriptor.MediaType,
+		Os:           config.OS,
+		Architecture: config.Architecture,
+		Variant:      config.Variant,
+		Created:      config.Created,
+		Author:       config.Author,
+		Config:       config.Config,
+		Layers:       make([]remoteLayer, 0, len(layers)),
+	}
+	if p := m.Descriptor.Platform; p != nil && p.OS != "" {
+		img.Os, img.Architecture, img.Variant = p.OS, p.Architecture, p.Variant
+	}
+	for _, l := range layers {
+		img.Layers = append(img.Layers, remoteLayer{Digest: l.Digest, MediaType: l.MediaType, Size: l.Size})
+		img.Size += l.Size
+	}
+	return img, nil
+}
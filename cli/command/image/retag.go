@@ -0,0 +1,256 @@
+package image
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/distribution/reference"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/completion"
+	"github.com/docker/cli/internal/jsonstream"
+	"github.com/docker/cli/opts"
+	"github.com/docker/cli/templates"
+	"github.com/moby/moby/client"
+	"github.com/spf13/cobra"
+)
+
+type retagOptions struct {
+	images   []string
+	from     string
+	to       string
+	file     string
+	filter   opts.FilterOpt
+	push     bool
+	parallel int
+	dryRun   bool
+}
+
+// newRetagCommand creates a new "docker image retag" command.
+func newRetagCommand(dockerCLI command.Cli) *cobra.Command {
+	options := retagOptions{filter: opts.NewFilterOpt()}
+
+	cmd := &cobra.Command{
+		Use:   "retag [OPTIONS] --to TEMPLATE [IMAGE...]",
+		Short: "Tag multiple images using a template",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.images = args
+			return runRetag(cmd.Context(), dockerCLI, options)
+		},
+		ValidArgsFunction:     completion.ImageNames(dockerCLI, -1),
+		DisableFlagsInUseLine: true,
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&options.from, "from", "", `Pattern to select and parse source images (e.g. "registry-a/{{.Repo}}:{{.Tag}}")`)
+	flags.StringVar(&options.to, "to", "", `Template for the new tag (e.g. "registry-b/{{.Repo}}:{{.Tag}}")`)
+	flags.StringVarP(&options.file, "file", "f", "", `Read image references from a file ("-" for STDIN)`)
+	flags.Var(&options.filter, "filter", `Select source images using a filter (e.g. "reference=registry-a/*")`)
+	flags.BoolVar(&options.push, "push", false, "Push the new tags after tagging")
+	flags.IntVar(&options.parallel, "parallel", defaultParallel, "Maximum number of concurrent pushes")
+	flags.BoolVar(&options.dryRun, "dry-run", false, "Print the new tags without tagging")
+	_ = cmd.MarkFlagRequired("to")
+
+	return cmd
+}
+
+// retagPair is a source image reference, and the new reference to tag it as.
+type retagPair struct {
+	source string
+	target reference.NamedTagged
+}
+
+func runRetag(ctx context.Context, dockerCLI command.Cli, options retagOptions) error {
+	if options.parallel < 1 {
+		return errors.New("--parallel must be at least 1")
+	}
+	var from *regexp.Regexp
+	if options.from != "" {
+		var err error
+		from, err = compileRetagPattern(options.from)
+		if err != nil {
+			return err
+		}
+	}
+	to, err := templates.Parse(options.to)
+	if err != nil {
+		return fmt.Errorf("invalid --to template: %w", err)
+	}
+
+	sources, err := retagSources(ctx, dockerCLI, options, from != nil)
+	if err != nil {
+		return err
+	}
+
+	pairs := make([]retagPair, 0, len(sources))
+	targets := make(map[string]string, len(sources))
+	for _, src := range sources {
+		ref, err := reference.ParseNormalizedNamed(src)
+		if err != nil {
+			return err
+		}
+		ref = reference.TagNameOnly(ref)
+		fields, ok := retagFields(ref, from)
+		if !ok {
+			if len(options.images) > 0 || options.file != "" {
+				return fmt.Errorf("image %s does not match %s", src, options.from)
+			}
+			continue
+		}
+		var b bytes.Buffer
+		if err := to.Execute(&b, fields); err != nil {
+			return fmt.Errorf("failed to execute --to template for %s: %w", src, err)
+		}
+		target, err := reference.ParseNormalizedNamed(b.String())
+		if err != nil {
+			return fmt.Errorf("invalid target reference for %s: %w", src, err)
+		}
+		tagged, ok := reference.TagNameOnly(target).(reference.NamedTagged)
+		if !ok {
+			return fmt.Errorf("invalid target reference for %s: must be a tag: %s", src, b.String())
+		}
+		if other, exists := targets[tagged.String()]; exists && other != src {
+			return fmt.Errorf("conflicting targets: %s and %s are both tagged as %s", other, src, reference.FamiliarString(tagged))
+		}
+		targets[tagged.String()] = src
+		pairs = append(pairs, retagPair{source: reference.FamiliarString(ref), target: tagged})
+	}
+	if len(pairs) == 0 {
+		return errors.New("no images to retag")
+	}
+
+	for _, p := range pairs {
+		if !options.dryRun {
+			if err := dockerCLI.Client().ImageTag(ctx, p.source, reference.FamiliarString(p.target)); err != nil {
+				return err
+			}
+		}
+		_, _ = fmt.Fprintf(dockerCLI.Out(), "%s -> %s\n", p.source, reference.FamiliarString(p.target))
+	}
+	if !options.push || options.dryRun {
+		return nil
+	}
+
+	jobs := make([]jsonstream.Job, 0, len(pairs))
+	for _, p := range pairs {
+		jobs = append(jobs, pushJob(dockerCLI, p.target, nil))
+	}
+	results := jsonstream.RunParallel(ctx, dockerCLI.Out(), options.parallel, jobs, jsonstream.WithAuxCallback(handleAux()))
+	return summarizeResults(dockerCLI.Err(), "push", results)
+}
+
+// retagSources returns the source image references, given as arguments,
+// read from a file, or selected through a filter. If none of those are
+// provided and all is set, all tagged images are returned.
+func retagSources(ctx context.Context, dockerCLI command.Cli, options retagOptions, all bool) ([]string, error) {
+	sources := append([]string{}, options.images...)
+	if options.file != "" {
+		refs, err := readReferences(dockerCLI, options.file)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, refs...)
+	}
+	filters := options.filter.Value()
+	if len(filters) > 0 || (all && len(sources) == 0) {
+		images, err := dockerCLI.Client().ImageList(ctx, client.ImageListOptions{Filters: filters})
+		if err != nil {
+			return nil, err
+		}
+		for _, img := range images {
+			for _, t := range img.RepoTags {
+				if t != "<none>:<none>" {
+					sources = append(sources, t)
+				}
+			}
+		}
+	}
+	if len(sources) == 0 {
+		return nil, errors.New("no images specified: pass images as arguments, or use --file, --filter, or --from")
+	}
+	return sources, nil
+}
+
+var retagFieldRe = regexp.MustCompile(`\{\{\s*\.(\w+)\s*\}\}`)
+
+// compileRetagPattern compiles a --from pattern to a regular expression,
+// with a named group for each field ("{{.Field}}") in the pattern.
+func compileRetagPattern(pattern string) (*regexp.Regexp, error) {
+	var (
+		expr strings.Builder
+		seen = make(map[string]bool)
+		last int
+	)
+	expr.WriteString("^")
+	literal := func(s string) error {
+		if strings.Contains(s, "{{") || strings.Contains(s, "}}") {
+			return fmt.Errorf("invalid --from pattern: only fields (e.g. {{.Repo}}) are supported: %s", pattern)
+		}
+		expr.WriteString(regexp.QuoteMeta(s))
+		return nil
+	}
+	for _, m := range retagFieldRe.FindAllStringSubmatchIndex(pattern, -1) {
+		if err := literal(pattern[last:m[0]]); err != nil {
+			return nil, err
+		}
+		name := pattern[m[2]:m[3]]
+		if seen[name] {
+			return nil, fmt.Errorf("invalid --from pattern: field %s is used more than once", name)
+		}
+		seen[name] = true
+
+		var group string
+		switch name {
+		case "Registry":
+			group = `[^/]+`
+		case "Repo", "Name":
+			group = `[^:@]+`
+		case "Tag":
+			group = `[\w][\w.-]*`
+		default:
+			group = `[^/:@]+`
+		}
+		expr.WriteString("(?P<" + name + ">" + group + ")")
+		last = m[1]
+	}
+	if err := literal(pattern[last:]); err != nil {
+		return nil, err
+	}
+	expr.WriteString("$")
+	return regexp.Compile(expr.String())
+}
+
+// retagFields returns the template fields for a source reference. Fields
+// are derived from the reference, and overridden with the fields captured
+// by the --from pattern, if set. It returns false if the reference does not
+// match the pattern.
+func retagFields(ref reference.Named, from *regexp.Regexp) (map[string]string, bool) {
+	fields := map[string]string{
+		"Registry": reference.Domain(ref),
+		"Repo":     reference.Path(ref),
+		"Name":     reference.FamiliarName(ref),
+	}
+	if tagged, ok := ref.(reference.Tagged); ok {
+		fields["Tag"] = tagged.Tag()
+	}
+	if from == nil {
+		return fields, true
+	}
+
+	for _, s := range []string{reference.FamiliarString(ref), ref.String()} {
+		m := from.FindStringSubmatch(s)
+		if m == nil {
+			continue
+		}
+		for i, name := range from.SubexpNames() {
+			if name != "" {
+				fields[name] = m[i]
+			}
+		}
+		return fields, true
+	}
+	return nil, false
+}
@@ -0,0 +1,221 @@
+package image
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/distribution/reference"
+	"github.com/docker/cli/internal/test"
+	"github.com/moby/moby/api/types/image"
+	"github.com/moby/moby/client"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestRetagFields(t *testing.T) {
+	testCases := []struct {
+		pattern  string
+		ref      string
+		expected map[string]string
+	}{
+		{
+			ref: "registry-a.example.com/team/app:1.0",
+			expected: map[string]string{
+				"Registry": "registry-a.example.com",
+				"Repo":     "team/app",
+				"Name":     "registry-a.example.com/team/app",
+				"Tag":      "1.0",
+			},
+		},
+		{
+			ref: "ubuntu",
+			expected: map[string]string{
+				"Registry": "docker.io",
+				"Repo":     "library/ubuntu",
+				"Name":     "ubuntu",
+				"Tag":      "latest",
+			},
+		},
+		{
+			pattern: "registry-a.example.com/{{.Repo}}:{{.Tag}}",
+			ref:     "registry-a.example.com/team/app:1.0",
+			expected: map[string]string{
+				"Registry": "registry-a.example.com",
+				"Repo":     "team/app",
+				"Name":     "registry-a.example.com/team/app",
+				"Tag":      "1.0",
+			},
+		},
+		{
+			pattern: "registry-a.example.com/{{.Team}}/{{.App}}:{{.Version}}-rc",
+			ref:     "registry-a.example.com/team/app:1.0-rc",
+			expected: map[string]string{
+				"Registry": "registry-a.example.com",
+				"Repo":     "team/app",
+				"Name":     "registry-a.example.com/team/app",
+				"Tag":      "1.0-rc",
+				"Team":     "team",
+				"App":      "app",
+				"Version":  "1.0",
+			},
+		},
+		{
+			pattern: "registry-a.example.com/{{.Repo}}:{{.Tag}}",
+			ref:     "registry-b.example.com/team/app:1.0",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.pattern+" "+tc.ref, func(t *testing.T) {
+			ref, err := reference.ParseNormalizedNamed(tc.ref)
+			assert.NilError(t, err)
+			ref = reference.TagNameOnly(ref)
+
+			var from *regexp.Regexp
+			if tc.pattern != "" {
+				from, err = compileRetagPattern(tc.pattern)
+				assert.NilError(t, err)
+			}
+			fields, ok := retagFields(ref, from)
+			assert.Check(t, is.Equal(ok, tc.expected != nil))
+			assert.Check(t, is.DeepEqual(fields, tc.expected))
+		})
+	}
+}
+
+func TestCompileRetagPatternErrors(t *testing.T) {
+	_, err := compileRetagPattern("{{.Repo}}/{{.Repo}}")
+	assert.Check(t, is.ErrorContains(err, "field Repo is used more than once"))
+
+	_, err = compileRetagPattern(`{{.Repo | lower}}:{{.Tag}}`)
+	assert.Check(t, is.ErrorContains(err, "only fields (e.g. {{.Repo}}) are supported"))
+}
+
+func TestRetagCommand(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		tagged []string
+		pushed []string
+	)
+	fakeCLI := test.NewFakeCli(&fakeClient{
+		imageListFunc: func(options client.ImageListOptions) ([]image.Summary, error) {
+			return []image.Summary{
+				{ID: "sha256:1", RepoTags: []string{"registry-a.example.com/team/app:1.0", "app:latest"}},
+				{ID: "sha256:2", RepoTags: []string{"registry-a.example.com/team/db:1.0"}},
+				{ID: "sha256:3", RepoTags: []string{"<none>:<none>"}},
+			}, nil
+		},
+		imageTagFunc: func(source string, target string) error {
+			tagged = append(tagged, source+" "+target)
+			return nil
+		},
+		imagePushFunc: func(ref string, options client.ImagePushOptions) (io.ReadCloser, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			pushed = append(pushed, ref)
+			if strings.Contains(ref, "db") {
+				return nil, errors.New("denied: requested access to the resource is denied")
+			}
+			return io.NopCloser(strings.NewReader(`{"status":"Pushed","id":"abc123"}` + "\n")), nil
+		},
+	})
+	cmd := newRetagCommand(fakeCLI)
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{
+		"--from", "registry-a.example.com/{{.Repo}}:{{.Tag}}",
+		"--to", "registry-b.example.com/{{.Repo}}:v1.2",
+		"--push",
+	})
+	err := cmd.Execute()
+	assert.Check(t, is.Error(err, "failed to push 1 of 2 image(s)"))
+
+	assert.Check(t, is.DeepEqual(tagged, []string{
+		"registry-a.example.com/team/app:1.0 registry-b.example.com/team/app:v1.2",
+		"registry-a.example.com/team/db:1.0 registry-b.example.com/team/db:v1.2",
+	}))
+	sort.Strings(pushed)
+	assert.Check(t, is.DeepEqual(pushed, []string{
+		"registry-b.example.com/team/app:v1.2",
+		"registry-b.example.com/team/db:v1.2",
+	}))
+	assert.Check(t, is.Contains(fakeCLI.OutBuffer().String(), "registry-a.example.com/team/app:1.0 -> registry-b.example.com/team/app:v1.2\n"))
+	assert.Check(t, is.Contains(fakeCLI.OutBuffer().String(), "registry-b.example.com/team/app:v1.2 abc123: Pushed\n"))
+	assert.Check(t, is.Contains(fakeCLI.ErrBuffer().String(), "Failed to push registry-b.example.com/team/db:v1.2: denied: requested access to the resource is denied\n"))
+}
+
+func TestRetagCommandFromFile(t *testing.T) {
+	refs := filepath.Join(t.TempDir(), "images.txt")
+	assert.NilError(t, os.WriteFile(refs, []byte("# images to promote\napp:1.0\n\ndb:1.0\n"), 0o644))
+
+	var tagged []string
+	fakeCLI := test.NewFakeCli(&fakeClient{
+		imageTagFunc: func(source string, target string) error {
+			tagged = append(tagged, source+" "+target)
+			return nil
+		},
+	})
+	cmd := newRetagCommand(fakeCLI)
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"--file", refs, "--to", "registry.example.com/{{.Name}}:{{.Tag}}"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.DeepEqual(tagged, []string{
+		"app:1.0 registry.example.com/app:1.0",
+		"db:1.0 registry.example.com/db:1.0",
+	}))
+}
+
+func TestRetagCommandErrors(t *testing.T) {
+	testCases := []struct {
+		name          string
+		args          []string
+		expectedError string
+	}{
+		{
+			name:          "missing to",
+			args:          []string{"app:1.0"},
+			expectedError: `required flag(s) "to" not set`,
+		},
+		{
+			name:          "no images",
+			args:          []string{"--to", "registry.example.com/{{.Name}}"},
+			expectedError: "no images specified",
+		},
+		{
+			name:          "not matching from",
+			args:          []string{"--from", "registry-a/{{.Repo}}:{{.Tag}}", "--to", "registry-b/{{.Repo}}", "app:1.0"},
+			expectedError: "image app:1.0 does not match registry-a/{{.Repo}}:{{.Tag}}",
+		},
+		{
+			name:          "conflicting targets",
+			args:          []string{"--to", "registry.example.com/{{.Name}}:latest", "app:1.0", "app:2.0"},
+			expectedError: "conflicting targets: app:1.0 and app:2.0 are both tagged as registry.example.com/app:latest",
+		},
+		{
+			name:          "invalid target",
+			args:          []string{"--to", "registry.example.com/{{.Name}}@{{.Tag}}", "app:1.0"},
+			expectedError: "invalid target reference for app:1.0",
+		},
+		{
+			name:          "invalid parallel",
+			args:          []string{"--to", "registry.example.com/{{.Name}}", "--parallel", "0", "app:1.0"},
+			expectedError: "--parallel must be at least 1",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := newRetagCommand(test.NewFakeCli(&fakeClient{}))
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			cmd.SetArgs(tc.args)
+			assert.ErrorContains(t, cmd.Execute(), tc.expectedError)
+		})
+	}
+}
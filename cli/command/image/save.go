@@ -5,6 +5,7 @@
 	"errors"
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/containerd/platforms"
 	"github.com/docker/cli/cli"
@@ -17,9 +18,10 @@
 )
 
 type saveOptions struct {
-	images   []string
-	output   string
-	platform []string
+	images        []string
+	output        string
+	platform      []string
+	excludeLayers []string
 }
 
 // newSaveCommand creates a new "docker image save" command.
@@ -46,8 +48,10 @@ func newSaveCommand(dockerCLI command.Cli) *cobra.Command {
 	flags.StringVarP(&opts.output, "output", "o", "", "Write to a file, instead of STDOUT")
 	flags.StringSliceVar(&opts.platform, "platform", []string{}, `Save only the given platform(s). Formatted as a comma-separated list of "os[/arch[/variant]]" (e.g., "linux/amd64,linux/arm64/v8")`)
 	_ = flags.SetAnnotation("platform", "version", []string{"1.48"})
+	flags.StringSliceVar(&opts.excludeLayers, "exclude-layers-of", []string{}, "Omit layers that are shared with the given base image(s)")
 
 	_ = cmd.RegisterFlagCompletionFunc("platform", completion.Platforms())
+	_ = cmd.RegisterFlagCompletionFunc("exclude-layers-of", completion.ImageNames(dockerCLI, -1))
 	return cmd
 }
 
@@ -67,6 +71,15 @@ func runSave(ctx context.Context, dockerCLI command.Cli, opts saveOptions) error
 		options = append(options, client.ImageSaveWithPlatforms(platformList...))
 	}
 
+	var exclude map[string]struct{}
+	if len(opts.excludeLayers) > 0 {
+		var err error
+		exclude, err = baseImageLayers(ctx, dockerCLI, opts.excludeLayers)
+		if err != nil {
+			return err
+		}
+	}
+
 	var output io.Writer
 	if opts.output == "" {
 		if dockerCLI.Out().IsTerminal() {
@@ -88,6 +101,17 @@ func runSave(ctx context.Context, dockerCLI command.Cli, opts saveOptions) error
 	}
 	defer responseBody.Close()
 
+	if len(exclude) > 0 {
+		excluded, err := excludeLayers(output, responseBody, exclude)
+		if err != nil {
+			return err
+		}
+		if excluded == 0 {
+			_, _ = fmt.Fprintf(dockerCLI.Err(), "WARNING: the image archive contains no layers of %s; saved the complete image\n", strings.Join(opts.excludeLayers, ", "))
+		}
+		return nil
+	}
+
 	_, err = io.Copy(output, responseBody)
 	return err
 }
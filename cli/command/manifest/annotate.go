@@ -1,17 +1,18 @@
 package manifest
 
 import (
-	"context"
+	"errors"
 	"fmt"
 	"path/filepath"
+	"strings"
 
 	"github.com/containerd/errdefs"
+	"github.com/distribution/reference"
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/config"
 	"github.com/docker/cli/cli/manifest/store"
-	"github.com/docker/cli/internal/registryclient"
-	"github.com/moby/moby/api/types/registry"
+	"github.com/docker/cli/opts"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/spf13/cobra"
 )
@@ -24,13 +25,15 @@ type annotateOptions struct {
 	arch       string
 	osFeatures []string
 	osVersion  string
+
+	annotations      opts.ListOpts // annotations of the manifest descriptor
+	indexAnnotations opts.ListOpts // annotations of the manifest list
 }
 
 // manifestStoreProvider is used in tests to provide a dummy store.
 type manifestStoreProvider interface {
 	// ManifestStore returns a store for local manifests
 	ManifestStore() store.Store
-	RegistryClient(bool) registryclient.RegistryClient
 }
 
 // newManifestStore returns a store for local manifests
@@ -44,43 +47,36 @@ func newManifestStore(dockerCLI command.Cli) store.Store {
 	return store.NewStore(filepath.Join(config.Dir(), "manifests"))
 }
 
-// newRegistryClient returns a client for communicating with a Docker distribution
-// registry
-func newRegistryClient(dockerCLI command.Cli, allowInsecure bool) registryclient.RegistryClient {
-	if msp, ok := dockerCLI.(manifestStoreProvider); ok {
-		// manifestStoreProvider is used in tests to provide a dummy store.
-		return msp.RegistryClient(allowInsecure)
-	}
-	resolver := func(ctx context.Context, index *registry.IndexInfo) registry.AuthConfig {
-		return command.ResolveAuthConfig(dockerCLI.ConfigFile(), index)
-	}
-	// FIXME(thaJeztah): this should use the userAgent as configured on the dockerCLI.
-	return registryclient.NewRegistryClient(resolver, command.UserAgent(), allowInsecure)
-}
-
 // NewAnnotateCommand creates a new `docker manifest annotate` command
 func newAnnotateCommand(dockerCLI command.Cli) *cobra.Command {
-	var opts annotateOptions
+	options := annotateOptions{
+		annotations:      opts.NewListOpts(validateAnnotation),
+		indexAnnotations: opts.NewListOpts(validateAnnotation),
+	}
 
 	cmd := &cobra.Command{
-		Use:   "annotate [OPTIONS] MANIFEST_LIST MANIFEST",
+		Use:   "annotate [OPTIONS] MANIFEST_LIST [MANIFEST]",
 		Short: "Add additional information to a local image manifest",
-		Args:  cli.ExactArgs(2),
+		Args:  cli.RequiresRangeArgs(1, 2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			opts.target = args[0]
-			opts.image = args[1]
-			return runManifestAnnotate(dockerCLI, opts)
+			options.target = args[0]
+			if len(args) > 1 {
+				options.image = args[1]
+			}
+			return runManifestAnnotate(dockerCLI, options)
 		},
 		DisableFlagsInUseLine: true,
 	}
 
 	flags := cmd.Flags()
 
-	flags.StringVar(&opts.os, "os", "", "Set operating system")
-	flags.StringVar(&opts.arch, "arch", "", "Set architecture")
-	flags.StringVar(&opts.osVersion, "os-version", "", "Set operating system version")
-	flags.StringSliceVar(&opts.osFeatures, "os-features", []string{}, "Set operating system feature")
-	flags.StringVar(&opts.variant, "variant", "", "Set architecture variant")
+	flags.StringVar(&options.os, "os", "", "Set operating system")
+	flags.StringVar(&options.arch, "arch", "", "Set architecture")
+	flags.StringVar(&options.osVersion, "os-version", "", "Set operating system version")
+	flags.StringSliceVar(&options.osFeatures, "os-features", []string{}, "Set operating system feature")
+	flags.StringVar(&options.variant, "variant", "", "Set architecture variant")
+	flags.Var(&options.annotations, "annotation", "Set an annotation on the descriptor of the manifest (KEY=VALUE)")
+	flags.Var(&options.indexAnnotations, "index-annotation", "Set an annotation on the manifest list (KEY=VALUE)")
 
 	return cmd
 }
@@ -90,12 +86,31 @@ func runManifestAnnotate(dockerCLI command.Cli, opts annotateOptions) error {
 	if err != nil {
 		return fmt.Errorf("annotate: error parsing name for manifest list %s: %w", opts.target, err)
 	}
+
+	if opts.image == "" {
+		if opts.os != "" || opts.arch != "" || opts.osVersion != "" || len(opts.osFeatures) > 0 || opts.variant != "" || opts.annotations.Len() > 0 {
+			return errors.New("annotate: a manifest is required to set its platform or annotations")
+		}
+		if opts.indexAnnotations.Len() == 0 {
+			return errors.New("annotate: nothing to annotate: specify a manifest, or use --index-annotation")
+		}
+	}
+
+	manifestStore := newManifestStore(dockerCLI)
+	if opts.indexAnnotations.Len() > 0 {
+		if err := annotateList(manifestStore, targetRef, opts.indexAnnotations.GetSlice()); err != nil {
+			return err
+		}
+	}
+	if opts.image == "" {
+		return nil
+	}
+
 	imgRef, err := normalizeReference(opts.image)
 	if err != nil {
 		return fmt.Errorf("annotate: error parsing name for manifest %s: %w", opts.image, err)
 	}
 
-	manifestStore := newManifestStore(dockerCLI)
 	imageManifest, err := manifestStore.Get(targetRef, imgRef)
 	switch {
 	case errdefs.IsNotFound(err):
@@ -123,6 +138,12 @@ func runManifestAnnotate(dockerCLI command.Cli, opts annotateOptions) error {
 	if opts.osVersion != "" {
 		imageManifest.Descriptor.Platform.OSVersion = opts.osVersion
 	}
+	if opts.annotations.Len() > 0 {
+		if err := checkListAnnotations(manifestStore, targetRef); err != nil {
+			return err
+		}
+		imageManifest.Descriptor.Annotations = mergeAnnotations(imageManifest.Descriptor.Annotations, opts.annotations.GetSlice())
+	}
 
 	if !isValidOSArch(imageManifest.Descriptor.Platform.OS, imageManifest.Descriptor.Platform.Architecture) {
 		return fmt.Errorf("manifest entry for image has unsupported os/arch combination: %s/%s", opts.os, opts.arch)
@@ -130,6 +151,63 @@ func runManifestAnnotate(dockerCLI command.Cli, opts annotateOptions) error {
 	return manifestStore.Save(targetRef, imgRef, imageManifest)
 }
 
+// annotateList sets annotations on a local manifest list.
+func annotateList(manifestStore store.Store, targetRef reference.Named, annotations []string) error {
+	if err := checkListAnnotations(manifestStore, targetRef); err != nil {
+		return err
+	}
+	listOptions, err := manifestStore.GetListOptions(targetRef)
+	if err != nil {
+		return err
+	}
+	listOptions.Annotations = mergeAnnotations(listOptions.Annotations, annotations)
+	return manifestStore.SaveListOptions(targetRef, listOptions)
+}
+
+// checkListAnnotations returns an error if the local manifest list does not
+// exist, or is not an OCI image index, which is required for annotations.
+func checkListAnnotations(manifestStore store.Store, targetRef reference.Named) error {
+	manifests, err := manifestStore.GetList(targetRef)
+	switch {
+	case errdefs.IsNotFound(err):
+		return fmt.Errorf("manifest list %s does not exist", targetRef)
+	case err != nil:
+		return err
+	}
+	listOptions, err := manifestStore.GetListOptions(targetRef)
+	if err != nil {
+		return err
+	}
+	var firstMediaType string
+	if len(manifests) > 0 {
+		firstMediaType = manifests[0].Descriptor.MediaType
+	}
+	if listMediaType(listOptions, firstMediaType) != ocispec.MediaTypeImageIndex {
+		return fmt.Errorf("annotations are only supported by OCI image indexes: use 'docker manifest create --amend --oci %s' to convert the manifest list to an OCI image index", reference.FamiliarString(targetRef))
+	}
+	return nil
+}
+
+// mergeAnnotations sets the KEY=VALUE annotations in the existing
+// annotations, replacing existing values.
+func mergeAnnotations(existing map[string]string, annotations []string) map[string]string {
+	if existing == nil {
+		existing = make(map[string]string, len(annotations))
+	}
+	for k, v := range opts.ConvertKVStringsToMap(annotations) {
+		existing[k] = v
+	}
+	return existing
+}
+
+// validateAnnotation validates that an annotation is in the KEY=VALUE format.
+func validateAnnotation(val string) (string, error) {
+	if k, _, ok := strings.Cut(val, "="); !ok || k == "" {
+		return "", fmt.Errorf("invalid annotation %q: must be in the form KEY=VALUE", val)
+	}
+	return val, nil
+}
+
 func appendIfUnique(list []string, str string) []string {
 	for _, s := range list {
 		if s == str {
@@ -1,10 +1,13 @@
 package manifest
 
 import (
+	"context"
 	"io"
 	"testing"
 
+	"github.com/distribution/reference"
 	"github.com/docker/cli/cli/manifest/store"
+	manifesttypes "github.com/docker/cli/cli/manifest/types"
 	"github.com/docker/cli/internal/test"
 	"gotest.tools/v3/assert"
 	is "gotest.tools/v3/assert/cmp"
@@ -17,8 +20,20 @@ func TestManifestAnnotateError(t *testing.T) {
 		expectedError string
 	}{
 		{
-			args:          []string{"too-few-arguments"},
-			expectedError: "requires 2 arguments",
+			args:          []string{},
+			expectedError: "requires at least 1 and at most 2 arguments",
+		},
+		{
+			args:          []string{"example.com/list:v1"},
+			expectedError: "nothing to annotate",
+		},
+		{
+			args:          []string{"example.com/list:v1", "--os", "linux"},
+			expectedError: "a manifest is required to set its platform or annotations",
+		},
+		{
+			args:          []string{"example.com/list:v1", "--index-annotation", "novalue"},
+			expectedError: `invalid annotation "novalue": must be in the form KEY=VALUE`,
 		},
 		{
 			args:          []string{"th!si'sa/fa!ke/li$t/name", "example.com/alpine:3.0"},
@@ -79,3 +94,45 @@ func TestManifestAnnotate(t *testing.T) {
 	expected := golden.Get(t, "inspect-annotate.golden")
 	assert.Check(t, is.Equal(string(expected), actual.String()))
 }
+
+func TestManifestAnnotateOCIIndex(t *testing.T) {
+	manifestStore := store.NewStore(t.TempDir())
+
+	cli := test.NewFakeCli(nil)
+	cli.SetManifestStore(manifestStore)
+	cli.SetRegistryClient(&fakeRegistryClient{
+		getManifestFunc: func(_ context.Context, ref reference.Named) (manifesttypes.ImageManifest, error) {
+			return fullImageManifest(t, ref), nil
+		},
+	})
+
+	cmd := newCreateListCommand(cli)
+	cmd.SetArgs([]string{"example.com/list:v1", "example.com/alpine:3.0"})
+	cmd.SetOut(io.Discard)
+	assert.NilError(t, cmd.Execute())
+
+	cmd = newAnnotateCommand(cli)
+	cmd.SetArgs([]string{"example.com/list:v1", "--index-annotation", "org.opencontainers.image.version=3.0"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.ErrorContains(t, cmd.Execute(), "annotations are only supported by OCI image indexes")
+
+	cmd = newCreateListCommand(cli)
+	cmd.SetArgs([]string{"--amend", "--oci", "example.com/list:v1", "example.com/alpine:3.0"})
+	cmd.SetOut(io.Discard)
+	assert.NilError(t, cmd.Execute())
+
+	cmd = newAnnotateCommand(cli)
+	cmd.SetArgs([]string{
+		"--index-annotation", "org.opencontainers.image.version=3.0",
+		"--annotation", "org.opencontainers.image.title=alpine",
+		"example.com/list:v1", "example.com/alpine:3.0",
+	})
+	assert.NilError(t, cmd.Execute())
+
+	cli.OutBuffer().Reset()
+	cmd = newInspectCommand(cli)
+	cmd.SetArgs([]string{"example.com/list:v1"})
+	assert.NilError(t, cmd.Execute())
+	golden.Assert(t, cli.OutBuffer().String(), "inspect-oci-index.golden")
+}
@@ -0,0 +1,113 @@
+package manifest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/distribution/reference"
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/opts"
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/spf13/cobra"
+)
+
+type attachOptions struct {
+	ref          string
+	artifactType string
+	files        []string
+	mediaType    string
+	annotations  opts.ListOpts
+	insecure     bool
+}
+
+func newAttachCommand(dockerCLI command.Cli) *cobra.Command {
+	options := attachOptions{
+		annotations: opts.NewListOpts(validateAnnotation),
+	}
+
+	cmd := &cobra.Command{
+		Use:   "attach [OPTIONS] IMAGE",
+		Short: "Attach an artifact, such as a signature or SBOM, to an image in a registry",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.ref = args[0]
+			return runAttach(cmd.Context(), dockerCLI, options)
+		},
+		DisableFlagsInUseLine: true,
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&options.artifactType, "artifact-type", "", "Artifact type of the artifact, for example \"application/spdx+json\"")
+	flags.StringArrayVar(&options.files, "file", nil, "File to include in the artifact")
+	flags.StringVar(&options.mediaType, "media-type", "application/octet-stream", "Media type of the files")
+	flags.Var(&options.annotations, "annotation", "Set an annotation on the artifact (KEY=VALUE)")
+	flags.BoolVar(&options.insecure, "insecure", false, "Allow communication with an insecure registry")
+	return cmd
+}
+
+func runAttach(ctx context.Context, dockerCLI command.Cli, options attachOptions) error {
+	if options.artifactType == "" {
+		return errors.New("an artifact type is required: use --artifact-type to set the artifact type")
+	}
+	if len(options.files) == 0 {
+		return errors.New("no files to attach: use --file to add files to the artifact")
+	}
+	namedRef, err := normalizeReference(options.ref)
+	if err != nil {
+		return err
+	}
+
+	registryClient := command.NewRegistryClient(dockerCLI, options.insecure)
+	subject, err := registryClient.GetDescriptor(ctx, namedRef)
+	if err != nil {
+		return err
+	}
+	repoRef := reference.TrimNamed(namedRef)
+
+	config, err := registryClient.PutBlob(ctx, repoRef, ocispec.MediaTypeEmptyJSON, ocispec.DescriptorEmptyJSON.Data)
+	if err != nil {
+		return err
+	}
+	layers := make([]ocispec.Descriptor, 0, len(options.files))
+	for _, fileName := range options.files {
+		content, err := os.ReadFile(fileName)
+		if err != nil {
+			return err
+		}
+		layer, err := registryClient.PutBlob(ctx, repoRef, options.mediaType, content)
+		if err != nil {
+			return err
+		}
+		layer.Annotations = map[string]string{ocispec.AnnotationTitle: filepath.Base(fileName)}
+		layers = append(layers, layer)
+	}
+
+	annotations := mergeAnnotations(nil, options.annotations.GetSlice())
+	if _, ok := annotations[ocispec.AnnotationCreated]; !ok {
+		annotations[ocispec.AnnotationCreated] = time.Now().UTC().Format(time.RFC3339)
+	}
+	desc, err := registryClient.PutReferrer(ctx, repoRef, ocispec.Manifest{
+		Versioned:    specs.Versioned{SchemaVersion: 2},
+		MediaType:    ocispec.MediaTypeImageManifest,
+		ArtifactType: options.artifactType,
+		Config:       config,
+		Layers:       layers,
+		Subject: &ocispec.Descriptor{
+			MediaType: subject.MediaType,
+			Digest:    subject.Digest,
+			Size:      subject.Size,
+		},
+		Annotations: annotations,
+	})
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintln(dockerCLI.Out(), desc.Digest.String())
+	return nil
+}
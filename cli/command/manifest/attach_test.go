@@ -0,0 +1,91 @@
+package manifest
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/distribution/reference"
+	"github.com/docker/cli/internal/test"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/fs"
+)
+
+func TestManifestAttachErrors(t *testing.T) {
+	testCases := []struct {
+		args          []string
+		expectedError string
+	}{
+		{
+			args:          []string{},
+			expectedError: "requires 1 argument",
+		},
+		{
+			args:          []string{"--file", "sbom.json", "example.com/alpine:3.0"},
+			expectedError: "an artifact type is required",
+		},
+		{
+			args:          []string{"--artifact-type", "application/spdx+json", "example.com/alpine:3.0"},
+			expectedError: "no files to attach",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expectedError, func(t *testing.T) {
+			cmd := newAttachCommand(test.NewFakeCli(nil))
+			cmd.SetArgs(tc.args)
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			assert.ErrorContains(t, cmd.Execute(), tc.expectedError)
+		})
+	}
+}
+
+func TestManifestAttach(t *testing.T) {
+	dir := fs.NewDir(t, t.Name(), fs.WithFile("sbom.json", `{"spdxVersion":"SPDX-2.3"}`))
+	subject := ocispec.Descriptor{MediaType: ocispec.MediaTypeImageIndex, Digest: digest.FromString("subject"), Size: 512}
+
+	blobs := make(map[digest.Digest]string)
+	var pushed ocispec.Manifest
+	cli := test.NewFakeCli(nil)
+	cli.SetRegistryClient(&fakeRegistryClient{
+		getDescriptorFunc: func(_ context.Context, ref reference.Named) (ocispec.Descriptor, error) {
+			return subject, nil
+		},
+		putBlobFunc: func(_ context.Context, ref reference.Named, mediaType string, content []byte) (ocispec.Descriptor, error) {
+			assert.Check(t, is.Equal(ref.String(), "example.com/alpine"))
+			dgst := digest.FromBytes(content)
+			blobs[dgst] = string(content)
+			return ocispec.Descriptor{MediaType: mediaType, Digest: dgst, Size: int64(len(content))}, nil
+		},
+		putReferrerFunc: func(_ context.Context, ref reference.Named, manifest ocispec.Manifest) (ocispec.Descriptor, error) {
+			assert.Check(t, is.Equal(ref.String(), "example.com/alpine"))
+			pushed = manifest
+			return ocispec.Descriptor{Digest: digest.FromString("artifact")}, nil
+		},
+	})
+
+	cmd := newAttachCommand(cli)
+	cmd.SetArgs([]string{
+		"--artifact-type", "application/spdx+json",
+		"--media-type", "application/spdx+json",
+		"--file", dir.Join("sbom.json"),
+		"--annotation", "org.opencontainers.image.created=2024-03-01T10:00:00Z",
+		"example.com/alpine:3.0",
+	})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), digest.FromString("artifact").String()+"\n"))
+
+	assert.Check(t, is.Equal(pushed.ArtifactType, "application/spdx+json"))
+	assert.Check(t, is.DeepEqual(pushed.Subject, &subject))
+	assert.Check(t, is.Equal(pushed.Config.Digest, ocispec.DescriptorEmptyJSON.Digest))
+	assert.Check(t, is.Equal(blobs[pushed.Config.Digest], "{}"))
+	assert.Assert(t, is.Len(pushed.Layers, 1))
+	assert.Check(t, is.Equal(pushed.Layers[0].MediaType, "application/spdx+json"))
+	assert.Check(t, is.Equal(pushed.Layers[0].Annotations[ocispec.AnnotationTitle], "sbom.json"))
+	assert.Check(t, is.Equal(blobs[pushed.Layers[0].Digest], `{"spdxVersion":"SPDX-2.3"}`))
+	assert.Check(t, is.DeepEqual(pushed.Annotations, map[string]string{ocispec.AnnotationCreated: "2024-03-01T10:00:00Z"}))
+}
@@ -2,19 +2,29 @@
 
 import (
 	"context"
+	"io"
 
 	"github.com/distribution/reference"
 	manifesttypes "github.com/docker/cli/cli/manifest/types"
 	"github.com/docker/cli/internal/registryclient"
 	"github.com/docker/distribution"
 	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 )
 
 type fakeRegistryClient struct {
 	getManifestFunc     func(ctx context.Context, ref reference.Named) (manifesttypes.ImageManifest, error)
 	getManifestListFunc func(ctx context.Context, ref reference.Named) ([]manifesttypes.ImageManifest, error)
+	getBlobFunc         func(ctx context.Context, ref reference.Named, dgst digest.Digest) ([]byte, error)
+	openBlobFunc        func(ctx context.Context, ref reference.Named, dgst digest.Digest) (io.ReadCloser, error)
 	mountBlobFunc       func(ctx context.Context, source reference.Canonical, target reference.Named) error
 	putManifestFunc     func(ctx context.Context, source reference.Named, mf distribution.Manifest) (digest.Digest, error)
+	getDescriptorFunc   func(ctx context.Context, ref reference.Named) (ocispec.Descriptor, error)
+	getRawManifestFunc  func(ctx context.Context, ref reference.Named) (ocispec.Descriptor, []byte, error)
+	pushBlobFunc        func(ctx context.Context, ref reference.Named, desc ocispec.Descriptor, content io.Reader) error
+	putBlobFunc         func(ctx context.Context, ref reference.Named, mediaType string, content []byte) (ocispec.Descriptor, error)
+	getReferrersFunc    func(ctx context.Context, subject reference.Canonical, artifactType string) ([]ocispec.Descriptor, error)
+	putReferrerFunc     func(ctx context.Context, ref reference.Named, manifest ocispec.Manifest) (ocispec.Descriptor, error)
 }
 
 func (c *fakeRegistryClient) GetManifest(ctx context.Context, ref reference.Named) (manifesttypes.ImageManifest, error) {
@@ -31,6 +41,20 @@ func (c *fakeRegistryClient) GetManifestList(ctx context.Context, ref reference.
 	return nil, nil
 }
 
+func (c *fakeRegistryClient) GetBlob(ctx context.Context, ref reference.Named, dgst digest.Digest) ([]byte, error) {
+	if c.getBlobFunc != nil {
+		return c.getBlobFunc(ctx, ref, dgst)
+	}
+	return nil, nil
+}
+
+func (c *fakeRegistryClient) OpenBlob(ctx context.Context, ref reference.Named, dgst digest.Digest) (io.ReadCloser, error) {
+	if c.openBlobFunc != nil {
+		return c.openBlobFunc(ctx, ref, dgst)
+	}
+	return nil, nil
+}
+
 func (c *fakeRegistryClient) MountBlob(ctx context.Context, source reference.Canonical, target reference.Named) error {
 	if c.mountBlobFunc != nil {
 		return c.mountBlobFunc(ctx, source, target)
@@ -45,4 +69,50 @@ func (c *fakeRegistryClient) PutManifest(ctx context.Context, ref reference.Name
 	return digest.Digest(""), nil
 }
 
+func (c *fakeRegistryClient) GetDescriptor(ctx context.Context, ref reference.Named) (ocispec.Descriptor, error) {
+	if c.getDescriptorFunc != nil {
+		return c.getDescriptorFunc(ctx, ref)
+	}
+	return ocispec.Descriptor{}, nil
+}
+
+func (c *fakeRegistryClient) GetRawManifest(ctx context.Context, ref reference.Named) (ocispec.Descriptor, []byte, error) {
+	if c.getRawManifestFunc != nil {
+		return c.getRawManifestFunc(ctx, ref)
+	}
+	return ocispec.Descriptor{}, nil, nil
+}
+
+func (c *fakeRegistryClient) PushBlob(ctx context.Context, ref reference.Named, desc ocispec.Descriptor, content io.Reader) error {
+	if c.pushBlobFunc != nil {
+		return c.pushBlobFunc(ctx, ref, desc, content)
+	}
+	return nil
+}
+
+func (c *fakeRegistryClient) PutBlob(ctx context.Context, ref reference.Named, mediaType string, content []byte) (ocispec.Descriptor, error) {
+	if c.putBlobFunc != nil {
+		return c.putBlobFunc(ctx, ref, mediaType, content)
+	}
+	return ocispec.Descriptor{}, nil
+}
+
+func (c *fakeRegistryClient) GetReferrers(ctx context.Context, subject reference.Canonical, artifactType string) ([]ocispec.Descriptor, error) {
+	if c.getReferrersFunc != nil {
+		return c.getReferrersFunc(ctx, subject, artifactType)
+	}
+	return nil, nil
+}
+
+func (c *fakeRegistryClient) PutReferrer(ctx context.Context, ref reference.Named, manifest ocispec.Manifest) (ocispec.Descriptor, error) {
+	if c.putReferrerFunc != nil {
+		return c.putReferrerFunc(ctx, ref, manifest)
+	}
+	return ocispec.Descriptor{}, nil
+}
+
+func (*fakeRegistryClient) GetCatalog(context.Context, string) ([]string, error) {
+	return nil, nil
+}
+
 var _ registryclient.RegistryClient = &fakeRegistryClient{}
@@ -34,6 +34,12 @@ func newManifestCommand(dockerCLI command.Cli) *cobra.Command {
 		newAnnotateCommand(dockerCLI),
 		newPushListCommand(dockerCLI),
 		newRmManifestListCommand(dockerCLI),
+		newListCommand(dockerCLI),
+		newPruneCommand(dockerCLI),
+		newReferrersCommand(dockerCLI),
+		newAttachCommand(dockerCLI),
+		newCopyCommand(dockerCLI),
+		newDiffCommand(dockerCLI),
 	)
 	return cmd
 }
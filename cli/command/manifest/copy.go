@@ -0,0 +1,260 @@
+package manifest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/containerd/platforms"
+	"github.com/distribution/reference"
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/internal/registryclient"
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/spf13/cobra"
+)
+
+type copyOptions struct {
+	source    string
+	target    string
+	platforms []string
+	insecure  bool
+}
+
+func newCopyCommand(dockerCLI command.Cli) *cobra.Command {
+	var opts copyOptions
+
+	cmd := &cobra.Command{
+		Use:   "copy [OPTIONS] SOURCE TARGET",
+		Short: "Copy an image or manifest list from one repository to another",
+		Args:  cli.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.source = args[0]
+			opts.target = args[1]
+			return runCopy(cmd.Context(), dockerCLI, opts)
+		},
+		DisableFlagsInUseLine: true,
+	}
+
+	flags := cmd.Flags()
+	flags.StringSliceVar(&opts.platforms, "platform", nil, "Only copy the images for these platforms from a manifest list")
+	flags.BoolVar(&opts.insecure, "insecure", false, "Allow communication with insecure registries")
+	return cmd
+}
+
+// imageCopier copies manifests, and the blobs they refer to, from one
+// repository to another.
+type imageCopier struct {
+	client registryclient.RegistryClient
+	out    io.Writer
+	source reference.Named
+	target reference.Named
+	// copied are the digests of the blobs that were copied to the target.
+	copied map[digest.Digest]bool
+}
+
+func runCopy(ctx context.Context, dockerCLI command.Cli, opts copyOptions) error {
+	sourceRef, err := normalizeReference(opts.source)
+	if err != nil {
+		return fmt.Errorf("invalid source %s: %w", opts.source, err)
+	}
+	targetRef, err := normalizeReference(opts.target)
+	if err != nil {
+		return fmt.Errorf("invalid target %s: %w", opts.target, err)
+	}
+	if _, isDigested := targetRef.(reference.Canonical); isDigested {
+		return errors.New("the target cannot be a reference by digest")
+	}
+	var matcher platforms.MatchComparer
+	if len(opts.platforms) > 0 {
+		ps, err := platforms.ParseAll(opts.platforms)
+		if err != nil {
+			return err
+		}
+		matcher = platforms.Any(ps...)
+	}
+
+	c := &imageCopier{
+		client: command.NewRegistryClient(dockerCLI, opts.insecure),
+		out:    dockerCLI.Out(),
+		source: reference.TrimNamed(sourceRef),
+		target: reference.TrimNamed(targetRef),
+		copied: make(map[digest.Digest]bool),
+	}
+
+	desc, raw, err := c.client.GetRawManifest(ctx, sourceRef)
+	if err != nil {
+		return err
+	}
+	switch desc.MediaType {
+	case ocispec.MediaTypeImageIndex, manifestlist.MediaTypeManifestList:
+		raw, err = c.copyIndex(ctx, raw, matcher)
+	default:
+		if matcher != nil {
+			return fmt.Errorf("%s is not a manifest list: --platform can only be used to copy a manifest list", opts.source)
+		}
+		err = c.copyBlobs(ctx, raw)
+	}
+	if err != nil {
+		return err
+	}
+
+	dgst, err := c.putManifest(ctx, targetRef, desc.MediaType, raw)
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintln(dockerCLI.Out(), dgst.String())
+	return nil
+}
+
+// copyIndex copies the manifests in an index, or manifest list, that match
+// the platform matcher, and returns the index to push to the target. The
+// index is returned unmodified if all manifests match.
+func (c *imageCopier) copyIndex(ctx context.Context, raw []byte, matcher platforms.MatchComparer) ([]byte, error) {
+	var index map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &index); err != nil {
+		return nil, err
+	}
+	var manifests []json.RawMessage
+	if err := json.Unmarshal(index["manifests"], &manifests); err != nil {
+		return nil, err
+	}
+	descriptors := make([]ocispec.Descriptor, len(manifests))
+	for i, m := range manifests {
+		if err := json.Unmarshal(m, &descriptors[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	keep := make([]bool, len(descriptors))
+	kept := make(map[digest.Digest]bool)
+	for i, d := range descriptors {
+		if matcher == nil || (d.Platform != nil && matcher.Match(*d.Platform)) {
+			keep[i] = true
+			kept[d.Digest] = true
+		}
+	}
+	// Keep attestations of the images that are copied.
+	for i, d := range descriptors {
+		if ref, ok := d.Annotations["vnd.docker.reference.digest"]; ok && kept[digest.Digest(ref)] {
+			keep[i] = true
+		}
+	}
+	if matcher != nil && len(kept) == 0 {
+		return nil, errors.New("no manifests match the specified platforms")
+	}
+
+	filtered := make([]json.RawMessage, 0, len(manifests))
+	for i, d := range descriptors {
+		if !keep[i] {
+			continue
+		}
+		filtered = append(filtered, manifests[i])
+		if err := c.copyManifest(ctx, d); err != nil {
+			return nil, err
+		}
+		p := "unknown"
+		if d.Platform != nil {
+			p = platforms.FormatAll(*d.Platform)
+		}
+		_, _ = fmt.Fprintf(c.out, "Copied %s@%s (%s)\n", reference.FamiliarString(c.target), d.Digest, p)
+	}
+	if len(filtered) == len(manifests) {
+		return raw, nil
+	}
+
+	var err error
+	index["manifests"], err = json.Marshal(filtered)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(index, "", "   ")
+}
+
+// copyManifest copies an image manifest in an index, and its blobs.
+func (c *imageCopier) copyManifest(ctx context.Context, desc ocispec.Descriptor) error {
+	sourceRef, err := reference.WithDigest(c.source, desc.Digest)
+	if err != nil {
+		return err
+	}
+	mdesc, raw, err := c.client.GetRawManifest(ctx, sourceRef)
+	if err != nil {
+		return err
+	}
+	switch mdesc.MediaType {
+	case ocispec.MediaTypeImageIndex, manifestlist.MediaTypeManifestList:
+		return fmt.Errorf("unsupported nested manifest list %s", desc.Digest)
+	}
+	if err := c.copyBlobs(ctx, raw); err != nil {
+		return err
+	}
+	targetRef, err := reference.WithDigest(c.target, desc.Digest)
+	if err != nil {
+		return err
+	}
+	_, err = c.putManifest(ctx, targetRef, mdesc.MediaType, raw)
+	return err
+}
+
+// copyBlobs copies the config and layers of an image manifest.
+func (c *imageCopier) copyBlobs(ctx context.Context, raw []byte) error {
+	var mfst ocispec.Manifest
+	if err := json.Unmarshal(raw, &mfst); err != nil {
+		return err
+	}
+	for _, blob := range append([]ocispec.Descriptor{mfst.Config}, mfst.Layers...) {
+		if c.copied[blob.Digest] || isForeignLayer(blob) {
+			continue
+		}
+		if err := c.copyBlob(ctx, blob); err != nil {
+			return err
+		}
+		c.copied[blob.Digest] = true
+	}
+	return nil
+}
+
+// copyBlob mounts a blob from the source repository if both repositories
+// are in the same registry, and streams the blob from the source otherwise,
+// or if the registry does not support mounting it.
+func (c *imageCopier) copyBlob(ctx context.Context, blob ocispec.Descriptor) error {
+	if c.source.Name() == c.target.Name() {
+		return nil
+	}
+	sourceRef, err := reference.WithDigest(c.source, blob.Digest)
+	if err != nil {
+		return err
+	}
+	if reference.Domain(c.source) == reference.Domain(c.target) {
+		if err := c.client.MountBlob(ctx, sourceRef, c.target); err == nil {
+			return nil
+		}
+	}
+
+	rc, err := c.client.OpenBlob(ctx, sourceRef, blob.Digest)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	return c.client.PushBlob(ctx, c.target, blob, rc)
+}
+
+func (c *imageCopier) putManifest(ctx context.Context, ref reference.Named, mediaType string, raw []byte) (digest.Digest, error) {
+	mfst, _, err := distribution.UnmarshalManifest(mediaType, raw)
+	if err != nil {
+		return "", err
+	}
+	return c.client.PutManifest(ctx, ref, mfst)
+}
+
+// isForeignLayer returns true for layers that must not be pushed to a
+// registry, such as the base layers of Windows images.
+func isForeignLayer(desc ocispec.Descriptor) bool {
+	return strings.Contains(desc.MediaType, "foreign") || strings.Contains(desc.MediaType, "nondistributable")
+}
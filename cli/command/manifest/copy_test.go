@@ -0,0 +1,166 @@
+package manifest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/distribution/reference"
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/distribution"
+	"github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+// fakeRegistry is an in-memory registry for testing copies.
+type fakeRegistry struct {
+	manifests map[string][]byte
+	blobs     map[string]string
+	mounted   []string
+	pushed    []string
+}
+
+func newFakeRegistry() *fakeRegistry {
+	return &fakeRegistry{manifests: make(map[string][]byte), blobs: make(map[string]string)}
+}
+
+func (r *fakeRegistry) addBlob(t *testing.T, repo string, content string) ocispec.Descriptor {
+	t.Helper()
+	dgst := digest.FromString(content)
+	r.blobs[repo+"@"+dgst.String()] = content
+	return ocispec.Descriptor{MediaType: ocispec.MediaTypeImageLayerGzip, Digest: dgst, Size: int64(len(content))}
+}
+
+func (r *fakeRegistry) addManifest(t *testing.T, ref string, v any) ocispec.Descriptor {
+	t.Helper()
+	dt, err := json.Marshal(v)
+	assert.NilError(t, err)
+	dgst := digest.FromBytes(dt)
+	named, err := reference.ParseNormalizedNamed(ref)
+	assert.NilError(t, err)
+	r.manifests[named.Name()+"@"+dgst.String()] = dt
+	r.manifests[named.String()] = dt
+	return ocispec.Descriptor{Digest: dgst, Size: int64(len(dt))}
+}
+
+func (r *fakeRegistry) client() *fakeRegistryClient {
+	return &fakeRegistryClient{
+		getRawManifestFunc: func(_ context.Context, ref reference.Named) (ocispec.Descriptor, []byte, error) {
+			dt, ok := r.manifests[ref.String()]
+			if !ok {
+				return ocispec.Descriptor{}, nil, errors.New("no such manifest: " + ref.String())
+			}
+			var versioned struct {
+				MediaType string `json:"mediaType"`
+			}
+			if err := json.Unmarshal(dt, &versioned); err != nil {
+				return ocispec.Descriptor{}, nil, err
+			}
+			return ocispec.Descriptor{MediaType: versioned.MediaType, Digest: digest.FromBytes(dt), Size: int64(len(dt))}, dt, nil
+		},
+		mountBlobFunc: func(_ context.Context, source reference.Canonical, target reference.Named) error {
+			r.mounted = append(r.mounted, source.Digest().String())
+			r.blobs[target.Name()+"@"+source.Digest().String()] = r.blobs[source.String()]
+			return nil
+		},
+		openBlobFunc: func(_ context.Context, ref reference.Named, dgst digest.Digest) (io.ReadCloser, error) {
+			return io.NopCloser(strings.NewReader(r.blobs[ref.Name()+"@"+dgst.String()])), nil
+		},
+		pushBlobFunc: func(_ context.Context, ref reference.Named, desc ocispec.Descriptor, content io.Reader) error {
+			dt, err := io.ReadAll(content)
+			if err != nil {
+				return err
+			}
+			r.pushed = append(r.pushed, desc.Digest.String())
+			r.blobs[ref.Name()+"@"+desc.Digest.String()] = string(dt)
+			return nil
+		},
+		putManifestFunc: func(_ context.Context, ref reference.Named, mf distribution.Manifest) (digest.Digest, error) {
+			_, dt, err := mf.Payload()
+			if err != nil {
+				return "", err
+			}
+			r.manifests[ref.String()] = dt
+			return digest.FromBytes(dt), nil
+		},
+	}
+}
+
+func TestManifestCopy(t *testing.T) {
+	registry := newFakeRegistry()
+	source := "example.com/source/app"
+
+	var images []ocispec.Descriptor
+	for _, p := range []ocispec.Platform{{OS: "linux", Architecture: "amd64"}, {OS: "linux", Architecture: "arm64"}} {
+		config := registry.addBlob(t, source, "config-"+p.Architecture)
+		config.MediaType = ocispec.MediaTypeImageConfig
+		shared := registry.addBlob(t, source, "shared-layer")
+		layer := registry.addBlob(t, source, "layer-"+p.Architecture)
+		desc := registry.addManifest(t, source+":unused", ocispec.Manifest{
+			Versioned: specs.Versioned{SchemaVersion: 2},
+			MediaType: ocispec.MediaTypeImageManifest,
+			Config:    config,
+			Layers:    []ocispec.Descriptor{shared, layer},
+		})
+		desc.MediaType = ocispec.MediaTypeImageManifest
+		desc.Platform = &p
+		images = append(images, desc)
+	}
+	indexDesc := registry.addManifest(t, source+":v1", ocispec.Index{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageIndex,
+		Manifests: images,
+	})
+
+	t.Run("same registry", func(t *testing.T) {
+		registry.mounted, registry.pushed = nil, nil
+		cli := test.NewFakeCli(nil)
+		cli.SetRegistryClient(registry.client())
+		cmd := newCopyCommand(cli)
+		cmd.SetArgs([]string{source + ":v1", "example.com/target/app:v1"})
+		assert.NilError(t, cmd.Execute())
+
+		assert.Check(t, is.Equal(cli.OutBuffer().String(), "Copied example.com/target/app@"+images[0].Digest.String()+" (linux/amd64)\n"+
+			"Copied example.com/target/app@"+images[1].Digest.String()+" (linux/arm64)\n"+
+			indexDesc.Digest.String()+"\n"))
+		assert.Check(t, is.Len(registry.mounted, 5), "the shared layer must only be copied once")
+		assert.Check(t, is.Len(registry.pushed, 0))
+		assert.Check(t, is.DeepEqual(registry.manifests["example.com/target/app:v1"], registry.manifests[source+":v1"]))
+	})
+
+	t.Run("other registry with platform", func(t *testing.T) {
+		registry.mounted, registry.pushed = nil, nil
+		cli := test.NewFakeCli(nil)
+		cli.SetRegistryClient(registry.client())
+		cmd := newCopyCommand(cli)
+		cmd.SetArgs([]string{"--platform", "linux/arm64", source + ":v1", "registry.example.org/app:v1"})
+		assert.NilError(t, cmd.Execute())
+
+		assert.Check(t, is.Len(registry.mounted, 0))
+		assert.Check(t, is.Len(registry.pushed, 3))
+		assert.Check(t, is.Equal(registry.blobs["registry.example.org/app@"+digest.FromString("layer-arm64").String()], "layer-arm64"))
+
+		var index ocispec.Index
+		assert.NilError(t, json.Unmarshal(registry.manifests["registry.example.org/app:v1"], &index))
+		assert.Assert(t, is.Len(index.Manifests, 1))
+		assert.Check(t, is.Equal(index.Manifests[0].Digest, images[1].Digest))
+		_, ok := registry.manifests["registry.example.org/app@"+images[1].Digest.String()]
+		assert.Check(t, ok)
+	})
+
+	t.Run("no matching platform", func(t *testing.T) {
+		cli := test.NewFakeCli(nil)
+		cli.SetRegistryClient(registry.client())
+		cmd := newCopyCommand(cli)
+		cmd.SetArgs([]string{"--platform", "windows/amd64", source + ":v1", "registry.example.org/app:v1"})
+		cmd.SetOut(io.Discard)
+		cmd.SetErr(io.Discard)
+		assert.Check(t, is.Error(cmd.Execute(), "no manifests match the specified platforms"))
+	})
+}
@@ -8,12 +8,14 @@
 	"github.com/containerd/errdefs"
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/spf13/cobra"
 )
 
 type createOpts struct {
 	amend    bool
 	insecure bool
+	oci      bool
 }
 
 func newCreateListCommand(dockerCLI command.Cli) *cobra.Command {
@@ -32,6 +34,7 @@ func newCreateListCommand(dockerCLI command.Cli) *cobra.Command {
 	flags := cmd.Flags()
 	flags.BoolVar(&opts.insecure, "insecure", false, "Allow communication with an insecure registry")
 	flags.BoolVarP(&opts.amend, "amend", "a", false, "Amend an existing manifest list")
+	flags.BoolVar(&opts.oci, "oci", false, "Create an OCI image index instead of a Docker manifest list")
 	return cmd
 }
 
@@ -71,6 +74,16 @@ func createManifestList(ctx context.Context, dockerCLI command.Cli, args []strin
 			return err
 		}
 	}
+	if opts.oci {
+		listOptions, err := manifestStore.GetListOptions(targetRef)
+		if err != nil {
+			return err
+		}
+		listOptions.MediaType = ocispec.MediaTypeImageIndex
+		if err := manifestStore.SaveListOptions(targetRef, listOptions); err != nil {
+			return err
+		}
+	}
 	_, _ = fmt.Fprintln(dockerCLI.Out(), "Created manifest list", targetRef.String())
 	return nil
 }
@@ -0,0 +1,228 @@
+package manifest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/containerd/platforms"
+	"github.com/distribution/reference"
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/formatter/tabwriter"
+	"github.com/docker/cli/cli/manifest/types"
+	"github.com/docker/go-units"
+	"github.com/opencontainers/go-digest"
+	"github.com/spf13/cobra"
+)
+
+type diffOptions struct {
+	refA     string
+	refB     string
+	format   string
+	insecure bool
+}
+
+// Values of platformDiff.Status.
+const (
+	diffAdded     = "added"
+	diffRemoved   = "removed"
+	diffChanged   = "changed"
+	diffUnchanged = "unchanged"
+)
+
+// manifestDiff is the difference between two images, or manifest lists.
+type manifestDiff struct {
+	A         string
+	B         string
+	Platforms []platformDiff
+}
+
+// platformDiff is the difference between the images for a platform. A is
+// nil if the platform was added, and B is nil if the platform was removed.
+type platformDiff struct {
+	Platform string
+	Status   string
+	A        *platformImage `json:",omitempty"`
+	B        *platformImage `json:",omitempty"`
+}
+
+type platformImage struct {
+	Digest digest.Digest
+	Layers int
+	Size   int64
+}
+
+func newDiffCommand(dockerCLI command.Cli) *cobra.Command {
+	var opts diffOptions
+
+	cmd := &cobra.Command{
+		Use:   "diff [OPTIONS] IMAGE IMAGE",
+		Short: "Compare the platforms of two images, or manifest lists",
+		Args:  cli.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.refA = args[0]
+			opts.refB = args[1]
+			return runDiff(cmd.Context(), dockerCLI, opts)
+		},
+		DisableFlagsInUseLine: true,
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.format, "format", "", "Format the output. Values: [table | json] (default table)")
+	flags.BoolVar(&opts.insecure, "insecure", false, "Allow communication with an insecure registry")
+	return cmd
+}
+
+func runDiff(ctx context.Context, dockerCLI command.Cli, opts diffOptions) error {
+	switch opts.format {
+	case "", "table", "json":
+	default:
+		return fmt.Errorf("invalid format %q: must be one of table, json", opts.format)
+	}
+
+	refA, err := normalizeReference(opts.refA)
+	if err != nil {
+		return err
+	}
+	refB, err := normalizeReference(opts.refB)
+	if err != nil {
+		return err
+	}
+	manifestsA, err := getManifests(ctx, dockerCLI, refA, opts.insecure)
+	if err != nil {
+		return err
+	}
+	manifestsB, err := getManifests(ctx, dockerCLI, refB, opts.insecure)
+	if err != nil {
+		return err
+	}
+
+	diff := manifestDiff{
+		A:         reference.FamiliarString(refA),
+		B:         reference.FamiliarString(refB),
+		Platforms: diffPlatforms(manifestsA, manifestsB),
+	}
+	if opts.format == "json" {
+		enc := json.NewEncoder(dockerCLI.Out())
+		enc.SetIndent("", "\t")
+		return enc.Encode(diff)
+	}
+	return printDiff(dockerCLI.Out(), diff)
+}
+
+// getManifests returns the manifests of a local manifest list, or of an
+// image or manifest list in a registry. An image that is not in a manifest
+// list is returned as a list of one manifest.
+func getManifests(ctx context.Context, dockerCLI command.Cli, namedRef reference.Named, insecure bool) ([]types.ImageManifest, error) {
+	if manifests, err := newManifestStore(dockerCLI).GetList(namedRef); err == nil {
+		return manifests, nil
+	}
+	registryClient := command.NewRegistryClient(dockerCLI, insecure)
+	imageManifest, err := registryClient.GetManifest(ctx, namedRef)
+	if err == nil {
+		return []types.ImageManifest{imageManifest}, nil
+	}
+	return registryClient.GetManifestList(ctx, namedRef)
+}
+
+// diffPlatforms compares the images in two manifest lists by platform, and
+// returns the differences sorted by platform. Attestations are ignored.
+func diffPlatforms(manifestsA, manifestsB []types.ImageManifest) []platformDiff {
+	imagesA := imagesByPlatform(manifestsA)
+	imagesB := imagesByPlatform(manifestsB)
+
+	diffs := make([]platformDiff, 0, len(imagesA)+len(imagesB))
+	for p, a := range imagesA {
+		d := platformDiff{Platform: p, Status: diffRemoved, A: a}
+		if b, ok := imagesB[p]; ok {
+			d.B = b
+			d.Status = diffChanged
+			if a.Digest == b.Digest {
+				d.Status = diffUnchanged
+			}
+		}
+		diffs = append(diffs, d)
+	}
+	for p, b := range imagesB {
+		if _, ok := imagesA[p]; !ok {
+			diffs = append(diffs, platformDiff{Platform: p, Status: diffAdded, B: b})
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].Platform < diffs[j].Platform
+	})
+	return diffs
+}
+
+func imagesByPlatform(manifests []types.ImageManifest) map[string]*platformImage {
+	images := make(map[string]*platformImage, len(manifests))
+	for _, m := range manifests {
+		if m.Descriptor.Annotations["vnd.docker.reference.type"] == "attestation-manifest" {
+			continue
+		}
+		p := "unknown"
+		if m.Descriptor.Platform != nil && m.Descriptor.Platform.OS != "" {
+			p = platforms.FormatAll(*m.Descriptor.Platform)
+		}
+		if _, ok := images[p]; ok {
+			continue
+		}
+		image := &platformImage{Digest: m.Descriptor.Digest}
+		switch {
+		case m.SchemaV2Manifest != nil:
+			for _, l := range m.SchemaV2Manifest.Layers {
+				image.Layers++
+				image.Size += l.Size
+			}
+		case m.OCIManifest != nil:
+			for _, l := range m.OCIManifest.Layers {
+				image.Layers++
+				image.Size += l.Size
+			}
+		}
+		images[p] = image
+	}
+	return images
+}
+
+func printDiff(out io.Writer, diff manifestDiff) error {
+	w := tabwriter.NewWriter(out, 10, 1, 3, ' ', 0)
+	_, _ = fmt.Fprintln(w, "PLATFORM\tSTATUS\tDIGEST\tLAYERS\tSIZE")
+	for _, d := range diff.Platforms {
+		var dgst, layers, size string
+		switch {
+		case d.A == nil:
+			dgst, layers, size = shortDigest(d.B.Digest), fmt.Sprint(d.B.Layers), units.HumanSize(float64(d.B.Size))
+		case d.B == nil:
+			dgst, layers, size = shortDigest(d.A.Digest), fmt.Sprint(d.A.Layers), units.HumanSize(float64(d.A.Size))
+		default:
+			dgst = diffValue(shortDigest(d.A.Digest), shortDigest(d.B.Digest))
+			layers = diffValue(fmt.Sprint(d.A.Layers), fmt.Sprint(d.B.Layers))
+			size = diffValue(units.HumanSize(float64(d.A.Size)), units.HumanSize(float64(d.B.Size)))
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", d.Platform, d.Status, dgst, layers, size)
+	}
+	return w.Flush()
+}
+
+// diffValue formats a value that may differ between the two images.
+func diffValue(a, b string) string {
+	if a == b {
+		return a
+	}
+	return a + " -> " + b
+}
+
+func shortDigest(dgst digest.Digest) string {
+	if dgst == "" {
+		return "-"
+	}
+	encoded := dgst.Encoded()
+	if len(encoded) > 12 {
+		encoded = encoded[:12]
+	}
+	return encoded
+}
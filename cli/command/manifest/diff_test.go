@@ -0,0 +1,96 @@
+package manifest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/containerd/platforms"
+	"github.com/distribution/reference"
+	"github.com/docker/cli/cli/manifest/store"
+	"github.com/docker/cli/cli/manifest/types"
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/manifest/schema2"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/golden"
+)
+
+func platformImageManifest(t *testing.T, ref reference.Named, platform string, layerSizes ...int64) types.ImageManifest {
+	t.Helper()
+	layers := make([]distribution.Descriptor, 0, len(layerSizes))
+	for i, size := range layerSizes {
+		layers = append(layers, distribution.Descriptor{
+			MediaType: schema2.MediaTypeLayer,
+			Size:      size,
+			Digest:    digest.FromString(platform + string(rune('a'+i))),
+		})
+	}
+	man, err := schema2.FromStruct(schema2.Manifest{
+		Versioned: schema2.SchemaVersion,
+		Config: distribution.Descriptor{
+			Digest:    digest.FromString(platform),
+			Size:      1520,
+			MediaType: schema2.MediaTypeImageConfig,
+		},
+		Layers: layers,
+	})
+	assert.NilError(t, err)
+	mt, raw, err := man.Payload()
+	assert.NilError(t, err)
+
+	p, err := platforms.Parse(platform)
+	assert.NilError(t, err)
+	desc := ocispec.Descriptor{
+		Digest:    digest.FromBytes(raw),
+		Size:      int64(len(raw)),
+		MediaType: mt,
+		Platform:  &p,
+	}
+	return types.NewImageManifest(ref, desc, man)
+}
+
+func TestManifestDiff(t *testing.T) {
+	base := ref(t, "alpine:3.0")
+	amd64 := platformImageManifest(t, base, "linux/amd64", 1000, 2000)
+	listA := []types.ImageManifest{
+		amd64,
+		platformImageManifest(t, base, "linux/arm/v6", 1000),
+		platformImageManifest(t, base, "linux/arm64/v8", 1000),
+	}
+	listB := []types.ImageManifest{
+		amd64,
+		platformImageManifest(t, base, "linux/arm64/v8", 1000, 3000),
+		platformImageManifest(t, base, "linux/riscv64", 1500),
+	}
+	attestation := platformImageManifest(t, base, "unknown/unknown", 100)
+	attestation.Descriptor.Annotations = map[string]string{"vnd.docker.reference.type": "attestation-manifest"}
+	listB = append(listB, attestation)
+
+	cli := test.NewFakeCli(nil)
+	cli.SetManifestStore(store.NewStore(t.TempDir()))
+	cli.SetRegistryClient(&fakeRegistryClient{
+		getManifestFunc: func(context.Context, reference.Named) (types.ImageManifest, error) {
+			return types.ImageManifest{}, errors.New("is a manifest list")
+		},
+		getManifestListFunc: func(_ context.Context, ref reference.Named) ([]types.ImageManifest, error) {
+			if ref.String() == "example.com/alpine:3.0" {
+				return listA, nil
+			}
+			return listB, nil
+		},
+	})
+
+	cmd := newDiffCommand(cli)
+	cmd.SetArgs([]string{"example.com/alpine:3.0", "example.com/alpine:3.1"})
+	assert.NilError(t, cmd.Execute())
+	golden.Assert(t, cli.OutBuffer().String(), "diff.golden")
+
+	cli.OutBuffer().Reset()
+	cmd = newDiffCommand(cli)
+	cmd.SetArgs([]string{"--format", "json", "example.com/alpine:3.0", "example.com/alpine:3.1"})
+	assert.NilError(t, cmd.Execute())
+	golden.Assert(t, cli.OutBuffer().String(), "diff.json.golden")
+}
@@ -10,6 +10,7 @@
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/manifest/types"
+	"github.com/docker/cli/internal/registryclient"
 	"github.com/docker/distribution/manifest/manifestlist"
 	"github.com/spf13/cobra"
 )
@@ -71,11 +72,15 @@ func runInspect(ctx context.Context, dockerCli command.Cli, opts inspectOptions)
 	// Try a local manifest list first
 	localManifestList, err := newManifestStore(dockerCli).GetList(namedRef)
 	if err == nil {
-		return printManifestList(dockerCli, namedRef, localManifestList, opts)
+		listOptions, err := newManifestStore(dockerCli).GetListOptions(namedRef)
+		if err != nil {
+			return err
+		}
+		return printManifestList(dockerCli, namedRef, localManifestList, listOptions, opts)
 	}
 
 	// Next try a remote manifest
-	registryClient := newRegistryClient(dockerCli, opts.insecure)
+	registryClient := command.NewRegistryClient(dockerCli, opts.insecure)
 	imageManifest, err := registryClient.GetManifest(ctx, namedRef)
 	if err == nil {
 		return printManifest(dockerCli, imageManifest, opts)
@@ -86,7 +91,27 @@ func runInspect(ctx context.Context, dockerCli command.Cli, opts inspectOptions)
 	if err != nil {
 		return err
 	}
-	return printManifestList(dockerCli, namedRef, manifestList, opts)
+	listOptions, err := remoteListOptions(ctx, registryClient, namedRef)
+	if err != nil {
+		return err
+	}
+	return printManifestList(dockerCli, namedRef, manifestList, listOptions, opts)
+}
+
+// remoteListOptions returns the media type and the annotations of a remote
+// manifest list or image index.
+func remoteListOptions(ctx context.Context, registryClient registryclient.RegistryClient, namedRef reference.Named) (types.ListOptions, error) {
+	desc, raw, err := registryClient.GetRawManifest(ctx, namedRef)
+	if err != nil {
+		return types.ListOptions{}, err
+	}
+	var index struct {
+		Annotations map[string]string `json:"annotations,omitempty"`
+	}
+	if err := json.Unmarshal(raw, &index); err != nil {
+		return types.ListOptions{}, fmt.Errorf("failed to parse manifest list %s: %w", namedRef, err)
+	}
+	return types.ListOptions{MediaType: desc.MediaType, Annotations: index.Annotations}, nil
 }
 
 func printManifest(dockerCli command.Cli, manifest types.ImageManifest, opts inspectOptions) error {
@@ -110,7 +135,7 @@ func printManifest(dockerCli command.Cli, manifest types.ImageManifest, opts ins
 	return nil
 }
 
-func printManifestList(dockerCli command.Cli, namedRef reference.Named, list []types.ImageManifest, opts inspectOptions) error {
+func printManifestList(dockerCli command.Cli, namedRef reference.Named, list []types.ImageManifest, listOptions types.ListOptions, opts inspectOptions) error {
 	if !opts.verbose {
 		targetRepo := reference.TrimNamed(namedRef)
 
@@ -123,7 +148,7 @@ func printManifestList(dockerCli command.Cli, namedRef reference.Named, list []t
 			}
 			manifests = append(manifests, mfd)
 		}
-		deserializedML, err := manifestlist.FromDescriptors(manifests)
+		deserializedML, err := newManifestList(manifests, listOptions)
 		if err != nil {
 			return err
 		}
@@ -135,3 +135,30 @@ func TestInspectcommandRemoteManifest(t *testing.T) {
 	expected := golden.Get(t, "inspect-manifest.golden")
 	assert.Check(t, is.Equal(string(expected), actual.String()))
 }
+
+func TestInspectCommandRemoteOCIIndex(t *testing.T) {
+	refStore := store.NewStore(t.TempDir())
+
+	cli := test.NewFakeCli(nil)
+	cli.SetManifestStore(refStore)
+	cli.SetRegistryClient(&fakeRegistryClient{
+		getManifestFunc: func(_ context.Context, _ reference.Named) (types.ImageManifest, error) {
+			return types.ImageManifest{}, errors.New("not an image manifest")
+		},
+		getManifestListFunc: func(_ context.Context, _ reference.Named) ([]types.ImageManifest, error) {
+			imageManifest := fullImageManifest(t, ref(t, "alpine:3.0"))
+			imageManifest.Descriptor.Annotations = map[string]string{"org.opencontainers.image.title": "alpine"}
+			return []types.ImageManifest{imageManifest}, nil
+		},
+		getRawManifestFunc: func(_ context.Context, _ reference.Named) (ocispec.Descriptor, []byte, error) {
+			return ocispec.Descriptor{MediaType: ocispec.MediaTypeImageIndex},
+				[]byte(`{"schemaVersion":2,"mediaType":"application/vnd.oci.image.index.v1+json","manifests":[],"annotations":{"org.opencontainers.image.version":"3.0"}}`), nil
+		},
+	})
+
+	cmd := newInspectCommand(cli)
+	cmd.SetOut(io.Discard)
+	cmd.SetArgs([]string{"example.com/list:v1"})
+	assert.NilError(t, cmd.Execute())
+	golden.Assert(t, cli.OutBuffer().String(), "inspect-oci-index.golden")
+}
@@ -0,0 +1,149 @@
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/containerd/platforms"
+	"github.com/distribution/reference"
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/formatter/tabwriter"
+	manifeststore "github.com/docker/cli/cli/manifest/store"
+	"github.com/docker/cli/cli/manifest/types"
+	"github.com/docker/go-units"
+	"github.com/opencontainers/go-digest"
+	"github.com/spf13/cobra"
+)
+
+type lsOptions struct {
+	format  string
+	quiet   bool
+	verbose bool
+}
+
+// listEntry is the JSON representation of a local manifest list.
+type listEntry struct {
+	Name      string
+	Manifests []listManifestEntry
+	Updated   time.Time
+	Pushed    *time.Time    `json:",omitempty"`
+	Digest    digest.Digest `json:",omitempty"`
+}
+
+type listManifestEntry struct {
+	Ref      string
+	Digest   digest.Digest
+	Platform string `json:",omitempty"`
+}
+
+func newListCommand(dockerCLI command.Cli) *cobra.Command {
+	var opts lsOptions
+
+	cmd := &cobra.Command{
+		Use:     "ls [OPTIONS]",
+		Aliases: []string{"list"},
+		Short:   "List manifest lists in local storage",
+		Args:    cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runList(dockerCLI.Out(), newManifestStore(dockerCLI), opts)
+		},
+		DisableFlagsInUseLine: true,
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.format, "format", "", "Format the output. Values: [table | json] (default table)")
+	flags.BoolVarP(&opts.quiet, "quiet", "q", false, "Only display the names of the manifest lists")
+	flags.BoolVarP(&opts.verbose, "verbose", "v", false, "Display the manifests in each manifest list")
+	return cmd
+}
+
+func runList(out io.Writer, store manifeststore.Store, opts lsOptions) error {
+	switch opts.format {
+	case "", "table", "json":
+	default:
+		return fmt.Errorf("invalid format %q: must be one of table, json", opts.format)
+	}
+
+	lists, err := store.List()
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case opts.quiet:
+		for _, l := range lists {
+			_, _ = fmt.Fprintln(out, familiarListName(l.Name))
+		}
+		return nil
+	case opts.format == "json":
+		entries := make([]listEntry, 0, len(lists))
+		for _, l := range lists {
+			entries = append(entries, newListEntry(l))
+		}
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "\t")
+		return enc.Encode(entries)
+	}
+
+	w := tabwriter.NewWriter(out, 10, 1, 3, ' ', 0)
+	if opts.verbose {
+		_, _ = fmt.Fprintln(w, "NAME\tMANIFEST\tPLATFORM\tDIGEST")
+		for _, l := range lists {
+			name := familiarListName(l.Name)
+			for _, m := range l.Manifests {
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", name, reference.FamiliarString(m.Ref), manifestPlatform(m), m.Descriptor.Digest)
+			}
+		}
+		return w.Flush()
+	}
+
+	_, _ = fmt.Fprintln(w, "NAME\tMANIFESTS\tUPDATED\tPUSHED")
+	for _, l := range lists {
+		pushed := "-"
+		if !l.Pushed.IsZero() {
+			pushed = units.HumanDuration(time.Since(l.Pushed)) + " ago"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", familiarListName(l.Name), len(l.Manifests), units.HumanDuration(time.Since(l.Updated))+" ago", pushed)
+	}
+	return w.Flush()
+}
+
+func newListEntry(l manifeststore.ListInfo) listEntry {
+	entry := listEntry{
+		Name:      familiarListName(l.Name),
+		Manifests: make([]listManifestEntry, 0, len(l.Manifests)),
+		Updated:   l.Updated,
+		Digest:    l.Digest,
+	}
+	if !l.Pushed.IsZero() {
+		entry.Pushed = &l.Pushed
+	}
+	for _, m := range l.Manifests {
+		entry.Manifests = append(entry.Manifests, listManifestEntry{
+			Ref:      reference.FamiliarString(m.Ref),
+			Digest:   m.Descriptor.Digest,
+			Platform: manifestPlatform(m),
+		})
+	}
+	return entry
+}
+
+// familiarListName returns the shortest form of the name of a manifest list,
+// as it would be passed to the other manifest commands.
+func familiarListName(name string) string {
+	named, err := reference.ParseNamed(name)
+	if err != nil {
+		return name
+	}
+	return reference.FamiliarString(named)
+}
+
+func manifestPlatform(m types.ImageManifest) string {
+	if m.Descriptor.Platform == nil || m.Descriptor.Platform.OS == "" {
+		return ""
+	}
+	return platforms.FormatAll(*m.Descriptor.Platform)
+}
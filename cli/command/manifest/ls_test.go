@@ -0,0 +1,64 @@
+package manifest
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/docker/cli/cli/manifest/store"
+	"github.com/docker/cli/internal/test"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/golden"
+)
+
+func TestManifestList(t *testing.T) {
+	manifestStore := store.NewStore(t.TempDir())
+	cli := test.NewFakeCli(nil)
+	cli.SetManifestStore(manifestStore)
+
+	list1 := ref(t, "first:1")
+	for _, name := range []string{"alpine:3.0", "alpine:3.1"} {
+		namedRef := ref(t, name)
+		assert.NilError(t, manifestStore.Save(list1, namedRef, fullImageManifest(t, namedRef)))
+	}
+	list2 := ref(t, "second:2")
+	namedRef := ref(t, "alpine:3.2")
+	assert.NilError(t, manifestStore.Save(list2, namedRef, fullImageManifest(t, namedRef)))
+	assert.NilError(t, manifestStore.SetPushed(list2, "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"))
+
+	cmd := newListCommand(cli)
+	cmd.SetArgs([]string{"--quiet"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "example.com/first:1\nexample.com/second:2\n"))
+
+	cli.OutBuffer().Reset()
+	cmd = newListCommand(cli)
+	cmd.SetArgs([]string{"--verbose"})
+	assert.NilError(t, cmd.Execute())
+	golden.Assert(t, cli.OutBuffer().String(), "ls-verbose.golden")
+
+	cli.OutBuffer().Reset()
+	cmd = newListCommand(cli)
+	cmd.SetArgs([]string{"--format", "json"})
+	assert.NilError(t, cmd.Execute())
+	var entries []listEntry
+	assert.NilError(t, json.Unmarshal(cli.OutBuffer().Bytes(), &entries))
+	assert.Assert(t, is.Len(entries, 2))
+	assert.Check(t, is.Equal(entries[0].Name, "example.com/first:1"))
+	assert.Check(t, is.Len(entries[0].Manifests, 2))
+	assert.Check(t, is.Nil(entries[0].Pushed))
+	assert.Check(t, is.Equal(entries[1].Name, "example.com/second:2"))
+	assert.Check(t, entries[1].Pushed != nil)
+	assert.Check(t, is.Equal(entries[1].Digest.String(), "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"))
+}
+
+func TestManifestListInvalidFormat(t *testing.T) {
+	cli := test.NewFakeCli(nil)
+	cli.SetManifestStore(store.NewStore(t.TempDir()))
+
+	cmd := newListCommand(cli)
+	cmd.SetArgs([]string{"--format", "yaml"})
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	assert.Error(t, cmd.Execute(), `invalid format "yaml": must be one of table, json`)
+}
@@ -0,0 +1,125 @@
+package manifest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/distribution/reference"
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	manifeststore "github.com/docker/cli/cli/manifest/store"
+	"github.com/docker/cli/internal/prompt"
+	"github.com/spf13/cobra"
+)
+
+type pruneOptions struct {
+	force     bool
+	all       bool
+	olderThan time.Duration
+}
+
+func newPruneCommand(dockerCLI command.Cli) *cobra.Command {
+	var opts pruneOptions
+
+	cmd := &cobra.Command{
+		Use:   "prune [OPTIONS]",
+		Short: "Remove pushed and stale manifest lists from local storage",
+		Args:  cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPrune(cmd.Context(), dockerCLI, opts)
+		},
+		DisableFlagsInUseLine: true,
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVarP(&opts.force, "force", "f", false, "Do not prompt for confirmation")
+	flags.BoolVarP(&opts.all, "all", "a", false, "Remove all manifest lists, including the ones that were not pushed")
+	flags.DurationVar(&opts.olderThan, "older-than", 0, "Also remove manifest lists that were not changed for this duration (e.g. \"168h\")")
+	return cmd
+}
+
+const (
+	pruneWarning = `WARNING! This will remove all local manifest lists that were pushed after their last change.
+Are you sure you want to continue?`
+	pruneOlderThanWarning = `WARNING! This will remove all local manifest lists that were pushed after their last change,
+and all local manifest lists that were not changed for %s.
+Are you sure you want to continue?`
+	pruneAllWarning = `WARNING! This will remove all local manifest lists.
+Are you sure you want to continue?`
+)
+
+func runPrune(ctx context.Context, dockerCLI command.Cli, opts pruneOptions) error {
+	if opts.olderThan < 0 {
+		return errors.New("invalid value for --older-than: duration must be positive")
+	}
+	if !opts.force {
+		warning := pruneWarning
+		switch {
+		case opts.all:
+			warning = pruneAllWarning
+		case opts.olderThan > 0:
+			warning = fmt.Sprintf(pruneOlderThanWarning, opts.olderThan)
+		}
+		r, err := prompt.Confirm(ctx, dockerCLI.In(), dockerCLI.Out(), warning)
+		if err != nil {
+			return err
+		}
+		if !r {
+			return cancelledErr{errors.New("manifest prune has been cancelled")}
+		}
+	}
+
+	store := newManifestStore(dockerCLI)
+	lists, err := store.List()
+	if err != nil {
+		return err
+	}
+
+	var (
+		deleted []string
+		errs    []error
+	)
+	for _, l := range lists {
+		if !shouldPrune(l, opts) {
+			continue
+		}
+		listRef, err := reference.ParseNamed(l.Name)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid manifest list %s: %w", l.Name, err))
+			continue
+		}
+		if err := store.Remove(listRef); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		deleted = append(deleted, familiarListName(l.Name))
+	}
+
+	if len(deleted) > 0 {
+		_, _ = fmt.Fprintln(dockerCLI.Out(), "Deleted Manifest Lists:")
+		for _, name := range deleted {
+			_, _ = fmt.Fprintln(dockerCLI.Out(), name)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// shouldPrune returns whether a manifest list is removed by prune. Manifest
+// lists are removed if they were pushed after their last change, or if they
+// were not changed for longer than --older-than.
+func shouldPrune(l manifeststore.ListInfo, opts pruneOptions) bool {
+	switch {
+	case opts.all, !l.Pushed.IsZero():
+		return true
+	case opts.olderThan > 0:
+		return time.Since(l.Updated) > opts.olderThan
+	default:
+		return false
+	}
+}
+
+type cancelledErr struct{ error }
+
+func (cancelledErr) Cancelled() {}
@@ -0,0 +1,90 @@
+package manifest
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/cli/manifest/store"
+	"github.com/docker/cli/cli/streams"
+	"github.com/docker/cli/internal/test"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestManifestPrune(t *testing.T) {
+	testCases := []struct {
+		name     string
+		args     []string
+		expected []string
+	}{
+		{
+			name:     "pushed",
+			args:     []string{"--force"},
+			expected: []string{"example.com/stale:1", "example.com/unpushed:1"},
+		},
+		{
+			name:     "older-than",
+			args:     []string{"--force", "--older-than", "24h"},
+			expected: []string{"example.com/unpushed:1"},
+		},
+		{
+			name:     "all",
+			args:     []string{"--force", "--all"},
+			expected: []string{},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			storeDir := t.TempDir()
+			manifestStore := store.NewStore(storeDir)
+			cli := test.NewFakeCli(nil)
+			cli.SetManifestStore(manifestStore)
+
+			namedRef := ref(t, "alpine:3.0")
+			for _, name := range []string{"pushed:1", "stale:1", "unpushed:1"} {
+				assert.NilError(t, manifestStore.Save(ref(t, name), namedRef, fullImageManifest(t, namedRef)))
+			}
+			assert.NilError(t, manifestStore.SetPushed(ref(t, "pushed:1"), "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"))
+			old := time.Now().Add(-48 * time.Hour)
+			assert.NilError(t, os.Chtimes(filepath.Join(storeDir, "example.com_stale-1", "example.com_alpine-3.0"), old, old))
+
+			cmd := newPruneCommand(cli)
+			cmd.SetArgs(tc.args)
+			assert.NilError(t, cmd.Execute())
+			assert.Check(t, is.Contains(cli.OutBuffer().String(), "Deleted Manifest Lists:\nexample.com/pushed:1\n"))
+
+			lists, err := manifestStore.List()
+			assert.NilError(t, err)
+			names := []string{}
+			for _, l := range lists {
+				names = append(names, familiarListName(l.Name))
+			}
+			assert.Check(t, is.DeepEqual(names, tc.expected))
+		})
+	}
+}
+
+func TestManifestPruneCancelled(t *testing.T) {
+	manifestStore := store.NewStore(t.TempDir())
+	cli := test.NewFakeCli(nil)
+	cli.SetManifestStore(manifestStore)
+	cli.SetIn(streams.NewIn(io.NopCloser(strings.NewReader("n\n"))))
+
+	namedRef := ref(t, "alpine:3.0")
+	assert.NilError(t, manifestStore.Save(ref(t, "pushed:1"), namedRef, fullImageManifest(t, namedRef)))
+	assert.NilError(t, manifestStore.SetPushed(ref(t, "pushed:1"), "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"))
+
+	cmd := newPruneCommand(cli)
+	cmd.SetArgs([]string{})
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	assert.Error(t, cmd.Execute(), "manifest prune has been cancelled")
+
+	lists, err := manifestStore.List()
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(lists, 1))
+}
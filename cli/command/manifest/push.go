@@ -3,6 +3,7 @@
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 
@@ -15,6 +16,8 @@
 	"github.com/docker/distribution/manifest/manifestlist"
 	"github.com/docker/distribution/manifest/ocischema"
 	"github.com/docker/distribution/manifest/schema2"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/spf13/cobra"
 )
 
@@ -76,25 +79,31 @@ func runPush(ctx context.Context, dockerCli command.Cli, opts pushOpts) error {
 		return fmt.Errorf("%s not found", targetRef)
 	}
 
-	req, err := buildPushRequest(manifests, targetRef, opts.insecure)
+	listOptions, err := newManifestStore(dockerCli).GetListOptions(targetRef)
 	if err != nil {
 		return err
 	}
 
-	if err := pushList(ctx, dockerCli, req); err != nil {
+	req, err := buildPushRequest(manifests, listOptions, targetRef, opts.insecure)
+	if err != nil {
+		return err
+	}
+
+	dgst, err := pushList(ctx, dockerCli, req)
+	if err != nil {
 		return err
 	}
 	if opts.purge {
 		return newManifestStore(dockerCli).Remove(targetRef)
 	}
-	return nil
+	return newManifestStore(dockerCli).SetPushed(targetRef, dgst)
 }
 
-func buildPushRequest(manifests []types.ImageManifest, targetRef reference.Named, insecure bool) (pushRequest, error) {
+func buildPushRequest(manifests []types.ImageManifest, listOptions types.ListOptions, targetRef reference.Named, insecure bool) (pushRequest, error) {
 	req := pushRequest{targetRef: targetRef, insecure: insecure}
 
 	var err error
-	req.list, err = buildManifestList(manifests, targetRef)
+	req.list, err = buildManifestList(manifests, listOptions, targetRef)
 	if err != nil {
 		return req, err
 	}
@@ -121,7 +130,7 @@ func buildPushRequest(manifests []types.ImageManifest, targetRef reference.Named
 	return req, nil
 }
 
-func buildManifestList(manifests []types.ImageManifest, targetRef reference.Named) (*manifestlist.DeserializedManifestList, error) {
+func buildManifestList(manifests []types.ImageManifest, listOptions types.ListOptions, targetRef reference.Named) (*manifestlist.DeserializedManifestList, error) {
 	targetRepo := reference.TrimNamed(targetRef)
 	descriptors := make([]manifestlist.ManifestDescriptor, 0, len(manifests))
 	for _, imageManifest := range manifests {
@@ -137,7 +146,62 @@ func buildManifestList(manifests []types.ImageManifest, targetRef reference.Name
 		descriptors = append(descriptors, descriptor)
 	}
 
-	return manifestlist.FromDescriptors(descriptors)
+	return newManifestList(descriptors, listOptions)
+}
+
+// newManifestList creates a Docker manifest list or OCI image index from the
+// descriptors, with the media type and annotations of listOptions.
+// Annotations are only supported by OCI image indexes.
+func newManifestList(descriptors []manifestlist.ManifestDescriptor, listOptions types.ListOptions) (*manifestlist.DeserializedManifestList, error) {
+	var firstMediaType string
+	if len(descriptors) > 0 {
+		firstMediaType = descriptors[0].MediaType
+	}
+	mediaType := listMediaType(listOptions, firstMediaType)
+	hasAnnotations := len(listOptions.Annotations) > 0
+	for _, d := range descriptors {
+		hasAnnotations = hasAnnotations || len(d.Annotations) > 0
+	}
+	if hasAnnotations && mediaType != ocispec.MediaTypeImageIndex {
+		return nil, errors.New("annotations are only supported by OCI image indexes: use 'docker manifest create --oci' to create an OCI image index")
+	}
+
+	list, err := manifestlist.FromDescriptorsWithMediaType(descriptors, mediaType)
+	if err != nil || len(listOptions.Annotations) == 0 {
+		return list, err
+	}
+
+	// manifestlist.ManifestList has no field for the annotations of an
+	// index, so marshal it with the annotations, and deserialize the result
+	// to preserve them in the canonical representation.
+	dt, err := json.MarshalIndent(struct {
+		manifestlist.ManifestList
+		Annotations map[string]string `json:"annotations,omitempty"`
+	}{
+		ManifestList: list.ManifestList,
+		Annotations:  listOptions.Annotations,
+	}, "", "   ")
+	if err != nil {
+		return nil, err
+	}
+	var index manifestlist.DeserializedManifestList
+	if err := index.UnmarshalJSON(dt); err != nil {
+		return nil, err
+	}
+	return &index, nil
+}
+
+// listMediaType returns the media type of a manifest list with the given
+// options, of which the first manifest has media type firstMediaType.
+func listMediaType(listOptions types.ListOptions, firstMediaType string) string {
+	switch {
+	case listOptions.MediaType != "":
+		return listOptions.MediaType
+	case firstMediaType == ocispec.MediaTypeImageManifest:
+		return ocispec.MediaTypeImageIndex
+	default:
+		return manifestlist.MediaTypeManifestList
+	}
 }
 
 func buildManifestDescriptor(targetRepo reference.Named, imageManifest types.ImageManifest) (manifestlist.ManifestDescriptor, error) {
@@ -149,9 +213,10 @@ func buildManifestDescriptor(targetRepo reference.Named, imageManifest types.Ima
 
 	manifest := manifestlist.ManifestDescriptor{
 		Descriptor: distribution.Descriptor{
-			Digest:    imageManifest.Descriptor.Digest,
-			Size:      imageManifest.Descriptor.Size,
-			MediaType: imageManifest.Descriptor.MediaType,
+			Digest:      imageManifest.Descriptor.Digest,
+			Size:        imageManifest.Descriptor.Size,
+			MediaType:   imageManifest.Descriptor.MediaType,
+			Annotations: imageManifest.Descriptor.Annotations,
 		},
 	}
 
@@ -245,22 +310,22 @@ func buildPutManifestRequest(imageManifest types.ImageManifest, targetRef refere
 	return mountRequest{ref: mountRef, manifest: imageManifest}, err
 }
 
-func pushList(ctx context.Context, dockerCLI command.Cli, req pushRequest) error {
-	registryClient := newRegistryClient(dockerCLI, req.insecure)
+func pushList(ctx context.Context, dockerCLI command.Cli, req pushRequest) (digest.Digest, error) {
+	registryClient := command.NewRegistryClient(dockerCLI, req.insecure)
 
 	if err := mountBlobs(ctx, registryClient, req.targetRef, req.manifestBlobs); err != nil {
-		return err
+		return "", err
 	}
 	if err := pushReferences(ctx, dockerCLI.Out(), registryClient, req.mountRequests); err != nil {
-		return err
+		return "", err
 	}
 	dgst, err := registryClient.PutManifest(ctx, req.targetRef, req.list)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	_, _ = fmt.Fprintln(dockerCLI.Out(), dgst.String())
-	return nil
+	return dgst, nil
 }
 
 func pushReferences(ctx context.Context, out io.Writer, client registryclient.RegistryClient, mounts []mountRequest) error {
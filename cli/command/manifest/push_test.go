@@ -2,6 +2,7 @@
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"io"
 	"testing"
@@ -10,7 +11,11 @@
 	"github.com/docker/cli/cli/manifest/store"
 	manifesttypes "github.com/docker/cli/cli/manifest/types"
 	"github.com/docker/cli/internal/test"
+	"github.com/docker/distribution"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
 )
 
 func newFakeRegistryClient() *fakeRegistryClient {
@@ -68,3 +73,46 @@ func TestManifestPush(t *testing.T) {
 	err = cmd.Execute()
 	assert.NilError(t, err)
 }
+
+func TestManifestPushOCIIndex(t *testing.T) {
+	manifestStore := store.NewStore(t.TempDir())
+
+	var mediaType string
+	var payload []byte
+	registry := newFakeRegistryClient()
+	registry.putManifestFunc = func(_ context.Context, _ reference.Named, mf distribution.Manifest) (digest.Digest, error) {
+		var err error
+		mediaType, payload, err = mf.Payload()
+		return digest.FromBytes(payload), err
+	}
+
+	cli := test.NewFakeCli(nil)
+	cli.SetManifestStore(manifestStore)
+	cli.SetRegistryClient(registry)
+
+	listRef := ref(t, "list:v1")
+	namedRef := ref(t, "alpine:3.0")
+	imageManifest := fullImageManifest(t, namedRef)
+	imageManifest.Descriptor.Annotations = map[string]string{"org.opencontainers.image.title": "alpine"}
+	assert.NilError(t, manifestStore.Save(listRef, namedRef, imageManifest))
+
+	cmd := newPushListCommand(cli)
+	cmd.SetArgs([]string{"example.com/list:v1"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.ErrorContains(t, cmd.Execute(), "annotations are only supported by OCI image indexes")
+
+	assert.NilError(t, manifestStore.SaveListOptions(listRef, manifesttypes.ListOptions{
+		MediaType:   ocispec.MediaTypeImageIndex,
+		Annotations: map[string]string{"org.opencontainers.image.version": "3.0"},
+	}))
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(mediaType, ocispec.MediaTypeImageIndex))
+
+	var index ocispec.Index
+	assert.NilError(t, json.Unmarshal(payload, &index))
+	assert.Check(t, is.Equal(index.MediaType, ocispec.MediaTypeImageIndex))
+	assert.Check(t, is.DeepEqual(index.Annotations, map[string]string{"org.opencontainers.image.version": "3.0"}))
+	assert.Assert(t, is.Len(index.Manifests, 1))
+	assert.Check(t, is.DeepEqual(index.Manifests[0].Annotations, map[string]string{"org.opencontainers.image.title": "alpine"}))
+}
@@ -0,0 +1,99 @@
+package manifest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/distribution/reference"
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/formatter/tabwriter"
+	"github.com/docker/cli/internal/registryclient"
+	"github.com/docker/go-units"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/spf13/cobra"
+)
+
+type referrersOptions struct {
+	ref          string
+	artifactType string
+	format       string
+	insecure     bool
+}
+
+func newReferrersCommand(dockerCLI command.Cli) *cobra.Command {
+	var opts referrersOptions
+
+	cmd := &cobra.Command{
+		Use:   "referrers [OPTIONS] IMAGE",
+		Short: "List the artifacts that refer to an image, such as signatures and SBOMs",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.ref = args[0]
+			return runReferrers(cmd.Context(), dockerCLI, opts)
+		},
+		DisableFlagsInUseLine: true,
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.artifactType, "artifact-type", "", "Only list referrers with this artifact type")
+	flags.StringVar(&opts.format, "format", "", "Format the output. Values: [table | json] (default table)")
+	flags.BoolVar(&opts.insecure, "insecure", false, "Allow communication with an insecure registry")
+	return cmd
+}
+
+func runReferrers(ctx context.Context, dockerCLI command.Cli, opts referrersOptions) error {
+	switch opts.format {
+	case "", "table", "json":
+	default:
+		return fmt.Errorf("invalid format %q: must be one of table, json", opts.format)
+	}
+
+	namedRef, err := normalizeReference(opts.ref)
+	if err != nil {
+		return err
+	}
+	registryClient := command.NewRegistryClient(dockerCLI, opts.insecure)
+	subject, err := resolveDigest(ctx, registryClient, namedRef)
+	if err != nil {
+		return err
+	}
+	referrers, err := registryClient.GetReferrers(ctx, subject, opts.artifactType)
+	if err != nil {
+		return err
+	}
+
+	if opts.format == "json" {
+		if referrers == nil {
+			referrers = []ocispec.Descriptor{}
+		}
+		enc := json.NewEncoder(dockerCLI.Out())
+		enc.SetIndent("", "\t")
+		return enc.Encode(referrers)
+	}
+
+	w := tabwriter.NewWriter(dockerCLI.Out(), 10, 1, 3, ' ', 0)
+	_, _ = fmt.Fprintln(w, "DIGEST\tARTIFACT TYPE\tSIZE\tCREATED")
+	for _, r := range referrers {
+		created := r.Annotations[ocispec.AnnotationCreated]
+		if created == "" {
+			created = "-"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", r.Digest, r.ArtifactType, units.HumanSize(float64(r.Size)), created)
+	}
+	return w.Flush()
+}
+
+// resolveDigest returns a reference to the manifest, or manifest list, that
+// the reference points to by digest.
+func resolveDigest(ctx context.Context, registryClient registryclient.RegistryClient, namedRef reference.Named) (reference.Canonical, error) {
+	if canonical, ok := namedRef.(reference.Canonical); ok {
+		return canonical, nil
+	}
+	desc, err := registryClient.GetDescriptor(ctx, namedRef)
+	if err != nil {
+		return nil, err
+	}
+	return reference.WithDigest(reference.TrimNamed(namedRef), desc.Digest)
+}
@@ -0,0 +1,68 @@
+package manifest
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/distribution/reference"
+	"github.com/docker/cli/internal/test"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/golden"
+)
+
+func TestManifestReferrers(t *testing.T) {
+	subject := digest.FromString("subject")
+	referrers := []ocispec.Descriptor{
+		{
+			MediaType:    ocispec.MediaTypeImageManifest,
+			ArtifactType: "application/spdx+json",
+			Digest:       digest.FromString("sbom"),
+			Size:         713,
+			Annotations:  map[string]string{ocispec.AnnotationCreated: "2024-03-01T10:00:00Z"},
+		},
+		{
+			MediaType:    ocispec.MediaTypeImageManifest,
+			ArtifactType: "application/vnd.dev.cosign.artifact.sig.v1+json",
+			Digest:       digest.FromString("signature"),
+			Size:         1024,
+		},
+	}
+
+	cli := test.NewFakeCli(nil)
+	cli.SetRegistryClient(&fakeRegistryClient{
+		getDescriptorFunc: func(_ context.Context, ref reference.Named) (ocispec.Descriptor, error) {
+			assert.Check(t, is.Equal(ref.String(), "example.com/alpine:3.0"))
+			return ocispec.Descriptor{MediaType: ocispec.MediaTypeImageIndex, Digest: subject, Size: 512}, nil
+		},
+		getReferrersFunc: func(_ context.Context, ref reference.Canonical, artifactType string) ([]ocispec.Descriptor, error) {
+			assert.Check(t, is.Equal(ref.String(), "example.com/alpine@"+subject.String()))
+			if artifactType == "" {
+				return referrers, nil
+			}
+			return referrers[:1], nil
+		},
+	})
+
+	cmd := newReferrersCommand(cli)
+	cmd.SetArgs([]string{"example.com/alpine:3.0"})
+	assert.NilError(t, cmd.Execute())
+	golden.Assert(t, cli.OutBuffer().String(), "referrers.golden")
+
+	cli.OutBuffer().Reset()
+	cmd = newReferrersCommand(cli)
+	cmd.SetArgs([]string{"--artifact-type", "application/spdx+json", "--format", "json", "example.com/alpine:3.0"})
+	assert.NilError(t, cmd.Execute())
+	golden.Assert(t, cli.OutBuffer().String(), "referrers.json.golden")
+}
+
+func TestManifestReferrersInvalidFormat(t *testing.T) {
+	cmd := newReferrersCommand(test.NewFakeCli(nil))
+	cmd.SetArgs([]string{"--format", "yaml", "example.com/alpine:3.0"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.Check(t, is.Error(cmd.Execute(), `invalid format "yaml": must be one of table, json`))
+}
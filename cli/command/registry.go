@@ -15,6 +15,7 @@
 	"github.com/docker/cli/cli/hints"
 	"github.com/docker/cli/cli/streams"
 	"github.com/docker/cli/internal/prompt"
+	"github.com/docker/cli/internal/registryclient"
 	"github.com/docker/cli/internal/tui"
 	"github.com/moby/moby/api/pkg/authconfig"
 	registrytypes "github.com/moby/moby/api/types/registry"
@@ -37,7 +38,8 @@
 
 // ResolveAuthConfig returns auth-config for the given registry from the
 // credential-store. It returns an empty AuthConfig if no credentials were
-// found.
+// found. The access token of a registry that was logged in to with
+// "docker login --oauth" is refreshed if it's about to expire.
 //
 // It is similar to [registry.ResolveAuthConfig], but uses the credentials-
 // store, instead of looking up credentials from a map.
@@ -49,7 +51,7 @@ func ResolveAuthConfig(cfg *configfile.ConfigFile, index *registrytypes.IndexInf
 		configKey = authConfigKey
 	}
 
-	a, _ := cfg.GetAuthConfig(configKey)
+	a, _ := getAuthConfig(cfg, configKey)
 	return registrytypes.AuthConfig{
 		Username:      a.Username,
 		Password:      a.Password,
@@ -62,6 +64,24 @@ func ResolveAuthConfig(cfg *configfile.ConfigFile, index *registrytypes.IndexInf
 	}
 }
 
+// registryClientProvider is used in tests to provide a fake registry client.
+type registryClientProvider interface {
+	RegistryClient(allowInsecure bool) registryclient.RegistryClient
+}
+
+// NewRegistryClient returns a client for communicating with a registry, using
+// the credentials stored in the CLI's config file.
+func NewRegistryClient(dockerCLI Cli, allowInsecure bool) registryclient.RegistryClient {
+	if rcp, ok := dockerCLI.(registryClientProvider); ok {
+		return rcp.RegistryClient(allowInsecure)
+	}
+	resolver := func(ctx context.Context, index *registrytypes.IndexInfo) registrytypes.AuthConfig {
+		return ResolveAuthConfig(dockerCLI.ConfigFile(), index)
+	}
+	// FIXME(thaJeztah): this should use the userAgent as configured on the dockerCLI.
+	return registryclient.NewRegistryClient(resolver, UserAgent(), allowInsecure)
+}
+
 // GetDefaultAuthConfig gets the default auth config given a serverAddress
 // If credentials for given serverAddress exists in the credential store, the configuration will be populated with values in it
 func GetDefaultAuthConfig(cfg *configfile.ConfigFile, checkCredStore bool, serverAddress string, isDefaultRegistry bool) (registrytypes.AuthConfig, error) {
@@ -189,7 +209,9 @@ func PromptUserForCredentials(ctx context.Context, cli Cli, argUser, argPassword
 // RetrieveAuthTokenFromImage retrieves an encoded auth token given a
 // complete image reference. The auth configuration is serialized as a
 // base64url encoded ([RFC 4648, Section 5]) JSON string for sending through
-// the "X-Registry-Auth" header.
+// the "X-Registry-Auth" header. The access token of a registry that was
+// logged in to with "docker login --oauth" is refreshed if it's about to
+// expire.
 //
 // [RFC 4648, Section 5]: https://tools.ietf.org/html/rfc4648#section-5
 func RetrieveAuthTokenFromImage(cfg *configfile.ConfigFile, image string) (string, error) {
@@ -198,7 +220,7 @@ func RetrieveAuthTokenFromImage(cfg *configfile.ConfigFile, image string) (strin
 		return "", err
 	}
 	configKey := getAuthConfigKey(reference.Domain(registryRef))
-	authConfig, err := cfg.GetAuthConfig(configKey)
+	authConfig, err := getAuthConfig(cfg, configKey)
 	if err != nil {
 		return "", err
 	}
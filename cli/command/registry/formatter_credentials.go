@@ -0,0 +1,70 @@
+package registry
+
+import (
+	"github.com/docker/cli/cli/command/formatter"
+)
+
+const (
+	defaultCredentialsTableFormat = "table {{.Registry}}\t{{.Username}}\t{{.Store}}\t{{.Type}}"
+
+	registryHeader = "REGISTRY"
+	usernameHeader = "USERNAME"
+	storeHeader    = "STORE"
+	typeHeader     = "TYPE"
+)
+
+// newCredentialsFormat returns a Format for rendering using a credentialsContext.
+func newCredentialsFormat(source string) formatter.Format {
+	switch source {
+	case "", formatter.TableFormatKey:
+		return defaultCredentialsTableFormat
+	}
+	return formatter.Format(source)
+}
+
+// credentialsWrite writes the stored credentials using the given format.
+func credentialsWrite(fmtCtx formatter.Context, entries []credentialEntry) error {
+	credsCtx := &credentialsContext{
+		HeaderContext: formatter.HeaderContext{
+			Header: formatter.SubHeaderContext{
+				"Registry": registryHeader,
+				"Username": usernameHeader,
+				"Store":    storeHeader,
+				"Type":     typeHeader,
+			},
+		},
+	}
+	return fmtCtx.Write(credsCtx, func(format func(subContext formatter.SubContext) error) error {
+		for _, entry := range entries {
+			if err := format(&credentialsContext{e: entry}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+type credentialsContext struct {
+	formatter.HeaderContext
+	e credentialEntry
+}
+
+func (c *credentialsContext) MarshalJSON() ([]byte, error) {
+	return formatter.MarshalJSON(c)
+}
+
+func (c *credentialsContext) Registry() string {
+	return c.e.registry
+}
+
+func (c *credentialsContext) Username() string {
+	return c.e.username
+}
+
+func (c *credentialsContext) Store() string {
+	return c.e.store
+}
+
+func (c *credentialsContext) Type() string {
+	return c.e.credentialType
+}
@@ -3,17 +3,24 @@
 import (
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/go-units"
 	registrytypes "github.com/moby/moby/api/types/registry"
 )
 
 const (
 	defaultSearchTableFormat = "table {{.Name}}\t{{.Description}}\t{{.StarCount}}\t{{.IsOfficial}}"
+	// defaultSearchDetailsTableFormat is used if the number of pulls of
+	// the repositories was looked up.
+	defaultSearchDetailsTableFormat = "table {{.Name}}\t{{.Description}}\t{{.StarCount}}\t{{.Pulls}}\t{{.LastUpdated}}\t{{.IsOfficial}}"
 
-	starsHeader     = "STARS"
-	officialHeader  = "OFFICIAL"
-	automatedHeader = "AUTOMATED"
+	starsHeader       = "STARS"
+	pullsHeader       = "PULLS"
+	lastUpdatedHeader = "UPDATED"
+	officialHeader    = "OFFICIAL"
+	automatedHeader   = "AUTOMATED"
 )
 
 // newFormat returns a Format for rendering using a searchContext.
@@ -26,13 +33,15 @@ func newFormat(source string) formatter.Format {
 }
 
 // formatWrite writes the context.
-func formatWrite(fmtCtx formatter.Context, results []registrytypes.SearchResult) error {
+func formatWrite(fmtCtx formatter.Context, results []searchResult) error {
 	searchCtx := &searchContext{
 		HeaderContext: formatter.HeaderContext{
 			Header: formatter.SubHeaderContext{
 				"Name":        formatter.NameHeader,
 				"Description": formatter.DescriptionHeader,
 				"StarCount":   starsHeader,
+				"Pulls":       pullsHeader,
+				"LastUpdated": lastUpdatedHeader,
 				"IsOfficial":  officialHeader,
 			},
 		},
@@ -40,8 +49,9 @@ func formatWrite(fmtCtx formatter.Context, results []registrytypes.SearchResult)
 	return fmtCtx.Write(searchCtx, func(format func(subContext formatter.SubContext) error) error {
 		for _, result := range results {
 			if err := format(&searchContext{
-				trunc: fmtCtx.Trunc,
-				s:     result,
+				trunc:   fmtCtx.Trunc,
+				s:       result.SearchResult,
+				details: result.details,
 			}); err != nil {
 				return err
 			}
@@ -52,9 +62,10 @@ func formatWrite(fmtCtx formatter.Context, results []registrytypes.SearchResult)
 
 type searchContext struct {
 	formatter.HeaderContext
-	trunc bool
-	json  bool
-	s     registrytypes.SearchResult
+	trunc   bool
+	json    bool
+	s       registrytypes.SearchResult
+	details *hubRepository
 }
 
 func (c *searchContext) MarshalJSON() ([]byte, error) {
@@ -79,6 +90,28 @@ func (c *searchContext) StarCount() string {
 	return strconv.Itoa(c.s.StarCount)
 }
 
+// Pulls returns the number of pulls of the repository, or an empty string
+// if it is unknown.
+func (c *searchContext) Pulls() string {
+	if c.details == nil {
+		return ""
+	}
+	return strconv.FormatInt(c.details.PullCount, 10)
+}
+
+// LastUpdated returns when the latest tag of the repository was pushed, or
+// an empty string if it is unknown.
+func (c *searchContext) LastUpdated() string {
+	switch {
+	case c.details == nil || c.details.LastUpdated.IsZero():
+		return ""
+	case c.json:
+		return c.details.LastUpdated.UTC().Format(time.RFC3339)
+	default:
+		return units.HumanDuration(time.Now().UTC().Sub(c.details.LastUpdated)) + " ago"
+	}
+}
+
 func (c *searchContext) formatBool(value bool) string {
 	switch {
 	case value && c.json:
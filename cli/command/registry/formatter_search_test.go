@@ -3,6 +3,7 @@
 import (
 	"bytes"
 	"testing"
+	"time"
 
 	"github.com/docker/cli/cli/command/formatter"
 	registrytypes "github.com/moby/moby/api/types/registry"
@@ -45,6 +46,25 @@ func TestSearchContext(t *testing.T) {
 			},
 			call: ctx.IsOfficial,
 		},
+		{
+			searchCtx: searchContext{
+				details: &hubRepository{PullCount: 1000000},
+			},
+			expValue: "1000000",
+			call:     ctx.Pulls,
+		},
+		{
+			searchCtx: searchContext{},
+			call:      ctx.Pulls,
+		},
+		{
+			searchCtx: searchContext{
+				json:    true,
+				details: &hubRepository{LastUpdated: time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)},
+			},
+			expValue: "2024-03-01T10:00:00Z",
+			call:     ctx.LastUpdated,
+		},
 	}
 
 	for _, c := range cases {
@@ -144,8 +164,8 @@ func TestSearchContextWrite(t *testing.T) {
 		{
 			doc:    "JSON format",
 			format: "{{json .}}",
-			expected: `{"Description":"Official build","IsOfficial":"true","Name":"result1","StarCount":"5000"}
-{"Description":"Not official","IsOfficial":"false","Name":"result2","StarCount":"5"}
+			expected: `{"Description":"Official build","IsOfficial":"true","LastUpdated":"","Name":"result1","Pulls":"","StarCount":"5000"}
+{"Description":"Not official","IsOfficial":"false","LastUpdated":"","Name":"result2","Pulls":"","StarCount":"5"}
 `,
 		},
 		{
@@ -184,9 +204,9 @@ func TestSearchContextWrite(t *testing.T) {
 		},
 	}
 
-	results := []registrytypes.SearchResult{
-		{Name: "result1", Description: "Official build", StarCount: 5000, IsOfficial: true},
-		{Name: "result2", Description: "Not official", StarCount: 5},
+	results := []searchResult{
+		{SearchResult: registrytypes.SearchResult{Name: "result1", Description: "Official build", StarCount: 5000, IsOfficial: true}},
+		{SearchResult: registrytypes.SearchResult{Name: "result2", Description: "Not official", StarCount: 5}},
 	}
 
 	for _, tc := range cases {
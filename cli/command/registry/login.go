@@ -11,6 +11,7 @@
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/cli/config/credentials"
 	configtypes "github.com/docker/cli/cli/config/types"
 	"github.com/docker/cli/internal/commands"
 	"github.com/docker/cli/internal/oauth/manager"
@@ -31,6 +32,7 @@ type loginOptions struct {
 	user          string
 	password      string
 	passwordStdin bool
+	oauth         bool
 }
 
 // newLoginCommand creates a new `docker login` command
@@ -63,7 +65,14 @@ func newLoginCommand(dockerCLI command.Cli) *cobra.Command {
 	flags.StringVarP(&opts.user, "username", "u", "", "Username")
 	flags.StringVarP(&opts.password, "password", "p", "", "Password or Personal Access Token (PAT)")
 	flags.BoolVar(&opts.passwordStdin, "password-stdin", false, "Take the Password or Personal Access Token (PAT) from stdin")
+	flags.BoolVar(&opts.oauth, "oauth", false, "Log in with the OAuth device authorization flow in a web browser")
 
+	// The subcommands take precedence over a registry with the same name;
+	// "docker login -- ls" logs in to a registry named "ls".
+	cmd.AddCommand(
+		newLoginListCommand(dockerCLI),
+		newLoginMigrateCommand(dockerCLI),
+	)
 	return cmd
 }
 
@@ -71,6 +80,9 @@ func newLoginCommand(dockerCLI command.Cli) *cobra.Command {
 //
 // TODO(thaJeztah); combine with verifyLoginOptions, but this requires rewrites of many tests.
 func verifyLoginFlags(flags *pflag.FlagSet, opts loginOptions) error {
+	if opts.oauth && (flags.Changed("username") || flags.Changed("password") || flags.Changed("password-stdin")) {
+		return errors.New("conflicting options: cannot specify --oauth with --username, --password, or --password-stdin")
+	}
 	if flags.Changed("password-stdin") {
 		if flags.Changed("password") {
 			return errors.New("conflicting options: cannot specify both --password and --password-stdin")
@@ -127,6 +139,20 @@ func runLogin(ctx context.Context, dockerCLI command.Cli, opts loginOptions) err
 	}
 	isDefaultRegistry := serverAddress == registry.IndexServer
 
+	if opts.oauth {
+		if !isDefaultRegistry {
+			serverAddress = credentials.ConvertToHostname(serverAddress)
+		}
+		msg, err := loginWithOAuth(ctx, dockerCLI, serverAddress)
+		if err != nil {
+			return err
+		}
+		if msg != "" {
+			_, _ = fmt.Fprintln(dockerCLI.Out(), msg)
+		}
+		return nil
+	}
+
 	// attempt login with current (stored) credentials
 	authConfig, err := command.GetDefaultAuthConfig(dockerCLI.ConfigFile(), opts.user == "" && opts.password == "", serverAddress, isDefaultRegistry)
 	if err == nil && authConfig.Username != "" && authConfig.Password != "" {
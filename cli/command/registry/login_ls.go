@@ -0,0 +1,155 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.23
+
+package registry
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/cli/config/credentials"
+	configtypes "github.com/docker/cli/cli/config/types"
+	flagsHelper "github.com/docker/cli/cli/flags"
+	"github.com/spf13/cobra"
+)
+
+// fileStoreName is the name that's shown for credentials that are stored
+// in the CLI configuration file.
+const fileStoreName = "file"
+
+// newNativeStore returns a credentials store that uses the given credentials
+// helper. It's a variable so that it can be replaced in tests.
+var newNativeStore = func(configFile *configfile.ConfigFile, helperSuffix string) credentials.Store {
+	return credentials.NewNativeStore(configFile, helperSuffix)
+}
+
+type loginListOptions struct {
+	format string
+}
+
+// credentialEntry describes credentials that are stored for a registry.
+type credentialEntry struct {
+	registry       string
+	username       string
+	store          string
+	credentialType string
+}
+
+func newLoginListCommand(dockerCLI command.Cli) *cobra.Command {
+	var opts loginListOptions
+
+	cmd := &cobra.Command{
+		Use:     "ls [OPTIONS]",
+		Aliases: []string{"list"},
+		Short:   "List stored registry credentials",
+		Args:    cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runLoginList(cmd.Context(), dockerCLI, opts)
+		},
+		ValidArgsFunction:     cobra.NoFileCompletions,
+		DisableFlagsInUseLine: true,
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.format, "format", "", flagsHelper.FormatHelp)
+	return cmd
+}
+
+func runLoginList(_ context.Context, dockerCLI command.Cli, opts loginListOptions) error {
+	maybePrintEnvAuthWarning(dockerCLI)
+
+	entries, err := listCredentials(dockerCLI, dockerCLI.ConfigFile())
+	if err != nil {
+		return err
+	}
+	if err := credentialsWrite(formatter.Context{
+		Output: dockerCLI.Out(),
+		Format: newCredentialsFormat(opts.format),
+	}, entries); err != nil {
+		return err
+	}
+	maybePrintPlaintextWarning(dockerCLI, dockerCLI.ConfigFile())
+	return nil
+}
+
+// listCredentials returns the credentials that are stored in the CLI
+// configuration file, the default credentials store, and the credential
+// helpers for specific registries, sorted by registry.
+func listCredentials(streams command.Streams, configFile *configfile.ConfigFile) ([]credentialEntry, error) {
+	var entries []credentialEntry
+	for _, registry := range plaintextRegistries(configFile) {
+		entries = append(entries, newCredentialEntry(registry, fileStoreName, configFile.GetAuthConfigs()[registry]))
+	}
+
+	if configFile.CredentialsStore != "" {
+		auths, err := newNativeStore(configFile, configFile.CredentialsStore).GetAll()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list credentials in credentials store %s: %w", configFile.CredentialsStore, err)
+		}
+		for registry, ac := range auths {
+			if hasCredentials(ac) {
+				entries = append(entries, newCredentialEntry(registry, configFile.CredentialsStore, ac))
+			}
+		}
+	}
+
+	for _, registry := range slices.Sorted(maps.Keys(configFile.CredentialHelpers)) {
+		helper := configFile.CredentialHelpers[registry]
+		if helper == "" || helper == configFile.CredentialsStore {
+			// Already listed as part of the default credentials store.
+			continue
+		}
+		ac, err := newNativeStore(configFile, helper).Get(registry)
+		if err != nil {
+			_, _ = fmt.Fprintf(streams.Err(), "WARNING: failed to get credentials for %s from credential helper %s: %v\n", registry, helper, err)
+			continue
+		}
+		if hasCredentials(ac) {
+			entries = append(entries, newCredentialEntry(registry, helper, ac))
+		}
+	}
+
+	slices.SortFunc(entries, func(a, b credentialEntry) int {
+		return cmp.Or(cmp.Compare(a.registry, b.registry), cmp.Compare(a.store, b.store))
+	})
+	return entries, nil
+}
+
+func newCredentialEntry(registry, store string, ac configtypes.AuthConfig) credentialEntry {
+	credentialType := "password"
+	if ac.IdentityToken != "" {
+		credentialType = "token"
+	}
+	return credentialEntry{
+		registry:       registry,
+		username:       ac.Username,
+		store:          store,
+		credentialType: credentialType,
+	}
+}
+
+// plaintextRegistries returns the registries for which credentials are
+// stored unencrypted in the CLI configuration file, sorted by name.
+func plaintextRegistries(configFile *configfile.ConfigFile) []string {
+	var registries []string
+	for registry, ac := range configFile.GetAuthConfigs() {
+		if hasCredentials(ac) {
+			registries = append(registries, registry)
+		}
+	}
+	slices.Sort(registries)
+	return registries
+}
+
+// hasCredentials returns whether ac contains a password or token, and not
+// only the address of the registry.
+func hasCredentials(ac configtypes.AuthConfig) bool {
+	return ac.Password != "" || ac.IdentityToken != ""
+}
@@ -0,0 +1,106 @@
+package registry
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/cli/config/credentials"
+	configtypes "github.com/docker/cli/cli/config/types"
+	"github.com/docker/cli/internal/test"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+// fakeHelperStore is a credentials store for a credential helper that, like
+// the native store, only keeps the address of the registry in the CLI
+// configuration file.
+type fakeHelperStore struct {
+	*test.FakeStore
+	configFile *configfile.ConfigFile
+}
+
+func (s *fakeHelperStore) Store(authConfig configtypes.AuthConfig) error {
+	if err := s.FakeStore.Store(authConfig); err != nil {
+		return err
+	}
+	s.configFile.GetAuthConfigs()[authConfig.ServerAddress] = configtypes.AuthConfig{ServerAddress: authConfig.ServerAddress}
+	return s.configFile.Save()
+}
+
+// setFakeHelpers replaces the credential helpers with in-memory stores, and
+// returns the stores by the name of the credential helper.
+func setFakeHelpers(t *testing.T, helpers map[string]map[string]configtypes.AuthConfig) {
+	t.Helper()
+	orig := newNativeStore
+	newNativeStore = func(configFile *configfile.ConfigFile, helperSuffix string) credentials.Store {
+		store, ok := helpers[helperSuffix]
+		if !ok {
+			store = map[string]configtypes.AuthConfig{}
+			helpers[helperSuffix] = store
+		}
+		fakeStore := test.NewFakeStore().(*test.FakeStore)
+		fakeStore.SetStore(store)
+		return &fakeHelperStore{FakeStore: fakeStore, configFile: configFile}
+	}
+	t.Cleanup(func() { newNativeStore = orig })
+}
+
+func newLoginTestCli(t *testing.T) *test.FakeCli {
+	t.Helper()
+	cli := test.NewFakeCli(&fakeClient{})
+	cli.ConfigFile().Filename = filepath.Join(t.TempDir(), "config.json")
+	return cli
+}
+
+func TestLoginList(t *testing.T) {
+	cli := newLoginTestCli(t)
+	cfg := cli.ConfigFile()
+	cfg.AuthConfigs = map[string]configtypes.AuthConfig{
+		"https://index.docker.io/v1/": {Username: "hubuser", Password: "hub-password", ServerAddress: "https://index.docker.io/v1/"},
+		"registry.example.com":        {ServerAddress: "registry.example.com"},
+	}
+	cfg.CredentialsStore = "pass"
+	cfg.CredentialHelpers = map[string]string{
+		"ghcr.io":    "osxkeychain",
+		"quay.io":    "osxkeychain",
+		"gcr.io":     "pass",
+		"unknown.io": "",
+	}
+	setFakeHelpers(t, map[string]map[string]configtypes.AuthConfig{
+		"pass": {
+			"registry.example.com": {Username: "me", Password: "secret", ServerAddress: "registry.example.com"},
+			"gcr.io":               {IdentityToken: "a-token", ServerAddress: "gcr.io"},
+		},
+		"osxkeychain": {
+			"ghcr.io": {Username: "octocat", Password: "secret", ServerAddress: "ghcr.io"},
+		},
+	})
+
+	assert.NilError(t, runLoginList(context.Background(), cli, loginListOptions{}))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), `REGISTRY                      USERNAME   STORE         TYPE
+gcr.io                                   pass          token
+ghcr.io                       octocat    osxkeychain   password
+https://index.docker.io/v1/   hubuser    file          password
+registry.example.com          me         pass          password
+`))
+	assert.Check(t, is.Contains(cli.ErrBuffer().String(), "Credentials for the following registries are stored unencrypted in '"+cfg.Filename+"'"))
+	assert.Check(t, is.Contains(cli.ErrBuffer().String(), "  https://index.docker.io/v1/\n"))
+	assert.Check(t, !strings.Contains(cli.ErrBuffer().String(), "registry.example.com"))
+}
+
+func TestLoginListFormat(t *testing.T) {
+	cli := newLoginTestCli(t)
+	cli.ConfigFile().CredentialHelpers = map[string]string{"ghcr.io": "osxkeychain"}
+	setFakeHelpers(t, map[string]map[string]configtypes.AuthConfig{
+		"osxkeychain": {
+			"ghcr.io": {Username: "octocat", Password: "secret", ServerAddress: "ghcr.io"},
+		},
+	})
+
+	assert.NilError(t, runLoginList(context.Background(), cli, loginListOptions{format: "json"}))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), `{"Registry":"ghcr.io","Store":"osxkeychain","Type":"password","Username":"octocat"}`+"\n"))
+	assert.Check(t, is.Equal(cli.ErrBuffer().String(), ""))
+}
@@ -0,0 +1,117 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/config/configfile"
+	configtypes "github.com/docker/cli/cli/config/types"
+	"github.com/spf13/cobra"
+)
+
+type loginMigrateOptions struct {
+	to string
+}
+
+func newLoginMigrateCommand(dockerCLI command.Cli) *cobra.Command {
+	var opts loginMigrateOptions
+
+	cmd := &cobra.Command{
+		Use:   "migrate [OPTIONS]",
+		Short: "Move unencrypted registry credentials to a credential helper",
+		Long: `Move the registry credentials that are stored unencrypted in the CLI
+configuration file to a credential helper, and set the credential helper
+as the default credentials store ("credsStore").`,
+		Args: cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runLoginMigrate(cmd.Context(), dockerCLI, opts)
+		},
+		ValidArgsFunction:     cobra.NoFileCompletions,
+		DisableFlagsInUseLine: true,
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.to, "to", "", `Name of the credential helper to move credentials to (for example, "osxkeychain" for "docker-credential-osxkeychain")`)
+	_ = cmd.MarkFlagRequired("to")
+	return cmd
+}
+
+func runLoginMigrate(_ context.Context, dockerCLI command.Cli, opts loginMigrateOptions) error {
+	if opts.to == "" {
+		return errors.New("no credential helper specified")
+	}
+	configFile := dockerCLI.ConfigFile()
+	if configFile.CredentialsStore != "" && configFile.CredentialsStore != opts.to {
+		return fmt.Errorf("the credentials store is already set to %q in %s: use --to %[1]q to move credentials to it", configFile.CredentialsStore, configFile.GetFilename())
+	}
+
+	registries := plaintextRegistries(configFile)
+	if len(registries) == 0 {
+		_, _ = fmt.Fprintf(dockerCLI.Out(), "No credentials are stored unencrypted in %s\n", configFile.GetFilename())
+		return nil
+	}
+
+	// The credentials store is only set once all credentials are moved, so
+	// that a failure doesn't hide the credentials that are still stored in
+	// the configuration file. Until then, credentials that are moved are
+	// looked up through a per-registry credential helper.
+	var moved []string
+	for i, registry := range registries {
+		helper := configFile.CredentialHelpers[registry]
+		useDefault := helper == ""
+		if useDefault {
+			helper = opts.to
+			if configFile.CredentialHelpers == nil {
+				configFile.CredentialHelpers = make(map[string]string)
+			}
+			configFile.CredentialHelpers[registry] = helper
+		}
+		msg, err := migrateCredentials(configFile, registry, helper)
+		if err != nil {
+			err = fmt.Errorf("failed to move credentials for %s to credential helper %s: %w", registry, helper, err)
+			if useDefault {
+				delete(configFile.CredentialHelpers, registry)
+			}
+			if i == 0 {
+				return err
+			}
+			if saveErr := configFile.Save(); saveErr != nil {
+				return fmt.Errorf("%w; failed to save %s: %v", err, configFile.GetFilename(), saveErr)
+			}
+			return err
+		}
+		if useDefault {
+			moved = append(moved, registry)
+		}
+		_, _ = fmt.Fprintln(dockerCLI.Out(), msg)
+	}
+	for _, registry := range moved {
+		delete(configFile.CredentialHelpers, registry)
+	}
+	configFile.CredentialsStore = opts.to
+	return configFile.Save()
+}
+
+// migrateCredentials moves the unencrypted credentials for registry to the
+// given credential helper. If the credential helper already holds credentials
+// for the registry, those take precedence, and only the unencrypted copy is
+// removed.
+func migrateCredentials(configFile *configfile.ConfigFile, registry, helper string) (msg string, _ error) {
+	ac := configFile.GetAuthConfigs()[registry]
+	target := newNativeStore(configFile, helper)
+	existing, err := target.Get(registry)
+	if err != nil {
+		return "", err
+	}
+	if hasCredentials(existing) {
+		configFile.GetAuthConfigs()[registry] = configtypes.AuthConfig{ServerAddress: ac.ServerAddress}
+		return fmt.Sprintf("Removed unencrypted credentials for %s: credential helper %s already has credentials for it", registry, helper), nil
+	}
+	if err := target.Store(ac); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Moved credentials for %s to credential helper %s", registry, helper), nil
+}
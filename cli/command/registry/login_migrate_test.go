@@ -0,0 +1,130 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/cli/cli/config"
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/cli/config/credentials"
+	configtypes "github.com/docker/cli/cli/config/types"
+	"github.com/docker/cli/internal/test"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestLoginMigrate(t *testing.T) {
+	cli := newLoginTestCli(t)
+	cfg := cli.ConfigFile()
+	cfg.AuthConfigs = map[string]configtypes.AuthConfig{
+		"https://index.docker.io/v1/": {Username: "hubuser", Password: "hub-password", ServerAddress: "https://index.docker.io/v1/"},
+		"gcr.io":                      {IdentityToken: "a-token", ServerAddress: "gcr.io"},
+		"ghcr.io":                     {Username: "octocat", Password: "old-secret", ServerAddress: "ghcr.io"},
+		"registry.example.com":        {ServerAddress: "registry.example.com"},
+	}
+	cfg.CredentialHelpers = map[string]string{"ghcr.io": "osxkeychain"}
+	helpers := map[string]map[string]configtypes.AuthConfig{
+		"osxkeychain": {
+			"ghcr.io": {Username: "octocat", Password: "secret", ServerAddress: "ghcr.io"},
+		},
+	}
+	setFakeHelpers(t, helpers)
+
+	assert.NilError(t, runLoginMigrate(context.Background(), cli, loginMigrateOptions{to: "pass"}))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), `Moved credentials for gcr.io to credential helper pass
+Removed unencrypted credentials for ghcr.io: credential helper osxkeychain already has credentials for it
+Moved credentials for https://index.docker.io/v1/ to credential helper pass
+`))
+	assert.Check(t, is.DeepEqual(helpers, map[string]map[string]configtypes.AuthConfig{
+		"pass": {
+			"https://index.docker.io/v1/": {Username: "hubuser", Password: "hub-password", ServerAddress: "https://index.docker.io/v1/"},
+			"gcr.io":                      {IdentityToken: "a-token", ServerAddress: "gcr.io"},
+		},
+		"osxkeychain": {
+			"ghcr.io": {Username: "octocat", Password: "secret", ServerAddress: "ghcr.io"},
+		},
+	}))
+
+	saved, err := config.Load(filepath.Dir(cfg.Filename))
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(saved.CredentialsStore, "pass"))
+	assert.Check(t, is.Len(plaintextRegistries(saved), 0))
+	assert.Check(t, is.Len(saved.AuthConfigs, 4))
+}
+
+func TestLoginMigrateNothingToDo(t *testing.T) {
+	cli := newLoginTestCli(t)
+	setFakeHelpers(t, map[string]map[string]configtypes.AuthConfig{})
+
+	assert.NilError(t, runLoginMigrate(context.Background(), cli, loginMigrateOptions{to: "pass"}))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "No credentials are stored unencrypted in "+cli.ConfigFile().Filename+"\n"))
+	assert.Check(t, is.Equal(cli.ConfigFile().CredentialsStore, ""))
+}
+
+func TestLoginMigrateErrors(t *testing.T) {
+	t.Run("other credentials store", func(t *testing.T) {
+		cli := newLoginTestCli(t)
+		cli.ConfigFile().CredentialsStore = "desktop"
+		err := runLoginMigrate(context.Background(), cli, loginMigrateOptions{to: "pass"})
+		assert.Check(t, is.ErrorContains(err, `the credentials store is already set to "desktop"`))
+	})
+
+	t.Run("helper not available", func(t *testing.T) {
+		cli := newLoginTestCli(t)
+		cfg := cli.ConfigFile()
+		cfg.AuthConfigs = map[string]configtypes.AuthConfig{
+			"gcr.io": {IdentityToken: "a-token", ServerAddress: "gcr.io"},
+		}
+		orig := newNativeStore
+		newNativeStore = func(*configfile.ConfigFile, string) credentials.Store {
+			s := test.NewFakeStore().(*test.FakeStore)
+			s.SetGetFunc(func(string) (configtypes.AuthConfig, error) {
+				return configtypes.AuthConfig{}, errors.New(`exec: "docker-credential-pass": executable file not found in $PATH`)
+			})
+			return s
+		}
+		t.Cleanup(func() { newNativeStore = orig })
+
+		err := runLoginMigrate(context.Background(), cli, loginMigrateOptions{to: "pass"})
+		assert.Check(t, is.ErrorContains(err, "failed to move credentials for gcr.io to credential helper pass"))
+		assert.Check(t, is.Equal(cfg.CredentialsStore, ""))
+		assert.Check(t, is.Equal(cfg.AuthConfigs["gcr.io"].IdentityToken, "a-token"))
+	})
+	t.Run("partial failure", func(t *testing.T) {
+		cli := newLoginTestCli(t)
+		cfg := cli.ConfigFile()
+		cfg.AuthConfigs = map[string]configtypes.AuthConfig{
+			"gcr.io":  {IdentityToken: "a-token", ServerAddress: "gcr.io"},
+			"quay.io": {Username: "me", Password: "secret", ServerAddress: "quay.io"},
+		}
+		helpers := map[string]map[string]configtypes.AuthConfig{}
+		setFakeHelpers(t, helpers)
+		fakeHelper := newNativeStore
+		newNativeStore = func(configFile *configfile.ConfigFile, helperSuffix string) credentials.Store {
+			s := fakeHelper(configFile, helperSuffix).(*fakeHelperStore)
+			s.SetStoreFunc(func(ac configtypes.AuthConfig) error {
+				if ac.ServerAddress == "quay.io" {
+					return errors.New("the keyring is locked")
+				}
+				helpers[helperSuffix][ac.ServerAddress] = ac
+				return nil
+			})
+			return s
+		}
+
+		err := runLoginMigrate(context.Background(), cli, loginMigrateOptions{to: "pass"})
+		assert.Check(t, is.Error(err, "failed to move credentials for quay.io to credential helper pass: the keyring is locked"))
+		assert.Check(t, is.Equal(cli.OutBuffer().String(), "Moved credentials for gcr.io to credential helper pass\n"))
+
+		// the credentials store is not set, so that the credentials for
+		// quay.io are still used, and gcr.io uses the credential helper.
+		saved, err := config.Load(filepath.Dir(cfg.Filename))
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(saved.CredentialsStore, ""))
+		assert.Check(t, is.DeepEqual(saved.CredentialHelpers, map[string]string{"gcr.io": "pass"}))
+		assert.Check(t, is.DeepEqual(plaintextRegistries(saved), []string{"quay.io"}))
+		assert.Check(t, is.Equal(helpers["pass"]["gcr.io"].IdentityToken, "a-token"))
+	})
+}
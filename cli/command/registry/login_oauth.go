@@ -0,0 +1,131 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/internal/oauth"
+	"github.com/docker/cli/internal/oauth/api"
+	"github.com/docker/cli/internal/oauth/manager"
+	"github.com/docker/cli/internal/registry"
+	registrytypes "github.com/moby/moby/api/types/registry"
+)
+
+// defaultOAuthClientID is the client ID that's used to log in to registries
+// other than Docker Hub if no client ID is configured.
+const defaultOAuthClientID = "docker-cli"
+
+// newOAuthManager returns an OAuthManager for the given options. It's a
+// variable so that it can be replaced in tests.
+var newOAuthManager = manager.New
+
+// loginWithOAuth logs in to the registry with the OAuth device authorization
+// flow. For registries other than Docker Hub, the authorization server is
+// discovered from the "oauth" configuration for the registry in the CLI
+// configuration file, or from the metadata that's published by the registry.
+func loginWithOAuth(ctx context.Context, dockerCLI command.Cli, serverAddress string) (msg string, _ error) {
+	if serverAddress == registry.IndexServer {
+		return loginWithDeviceCodeFlow(ctx, dockerCLI)
+	}
+
+	configFile := dockerCLI.ConfigFile()
+	oauthConfig, configured := configFile.OAuth[serverAddress]
+	m, oauthConfig, err := newRegistryOAuthManager(ctx, configFile, serverAddress, oauthConfig)
+	if err != nil {
+		return "", err
+	}
+	authConfig, err := m.LoginDevice(ctx, dockerCLI.Err())
+	if err != nil {
+		return "", err
+	}
+
+	regAuthConfig := registrytypes.AuthConfig{
+		Username:      authConfig.Username,
+		Password:      authConfig.Password,
+		ServerAddress: authConfig.ServerAddress,
+	}
+	response, err := loginWithRegistry(ctx, dockerCLI.Client(), regAuthConfig)
+	if err != nil {
+		return "", err
+	}
+
+	// Store the access token as password, and not an identity token that's
+	// returned by the registry, so that it can be refreshed when it expires.
+	if !configured {
+		if configFile.OAuth == nil {
+			configFile.OAuth = make(map[string]configfile.OAuthConfig)
+		}
+		configFile.OAuth[serverAddress] = oauthConfig
+	}
+	if err := storeCredentials(configFile, regAuthConfig); err != nil {
+		return "", err
+	}
+	if !configured {
+		if err := configFile.Save(); err != nil {
+			return "", fmt.Errorf("error saving OAuth configuration: %w", err)
+		}
+	}
+	return response.Status, nil
+}
+
+// newRegistryOAuthManager returns an OAuthManager for a registry other than
+// Docker Hub, and the configuration that's used for it, with the issuer and
+// client ID filled in. The endpoints that are not configured are discovered
+// from the issuer, which defaults to the registry itself.
+func newRegistryOAuthManager(ctx context.Context, configFile *configfile.ConfigFile, serverAddress string, oauthConfig configfile.OAuthConfig) (*manager.OAuthManager, configfile.OAuthConfig, error) {
+	issuer := oauthConfig.Issuer
+	if issuer == "" {
+		issuer = "https://" + serverAddress
+	}
+	httpClient, err := registry.NewHTTPClient(ctx, issuer)
+	if err != nil {
+		return nil, oauthConfig, fmt.Errorf("cannot log in to %s with OAuth: %w", serverAddress, err)
+	}
+	metadata, err := api.ResolveMetadata(ctx, httpClient, api.ServerMetadata{
+		Issuer:                      issuer,
+		DeviceAuthorizationEndpoint: oauthConfig.DeviceAuthorizationEndpoint,
+		TokenEndpoint:               oauthConfig.TokenEndpoint,
+		RevocationEndpoint:          oauthConfig.RevocationEndpoint,
+	})
+	if err != nil {
+		return nil, oauthConfig, fmt.Errorf("cannot log in to %s with OAuth: %w", serverAddress, err)
+	}
+	if oauthConfig.Issuer == "" {
+		oauthConfig.Issuer = metadata.Issuer
+	}
+	if oauthConfig.ClientID == "" {
+		oauthConfig.ClientID = defaultOAuthClientID
+	}
+
+	return newOAuthManager(manager.OAuthManagerOptions{
+		Store:         configFile.GetCredentialsStore(serverAddress),
+		Audience:      oauthConfig.Audience,
+		ClientID:      oauthConfig.ClientID,
+		Scopes:        oauthConfig.Scopes,
+		ServerAddress: serverAddress,
+		Metadata:      metadata,
+		HTTPClient:    httpClient,
+	}), oauthConfig, nil
+}
+
+// logoutOAuth revokes the refresh token for a registry that's configured for
+// OAuth, and erases it from the credentials store.
+func logoutOAuth(ctx context.Context, dockerCLI command.Cli, serverAddress string) error {
+	configFile := dockerCLI.ConfigFile()
+	oauthConfig, ok := configFile.OAuth[serverAddress]
+	if !ok {
+		return nil
+	}
+	m, _, err := newRegistryOAuthManager(ctx, configFile, serverAddress, oauthConfig)
+	if err != nil {
+		// Erase the refresh token, even if it can't be revoked.
+		if eraseErr := configFile.GetCredentialsStore(serverAddress).Erase(oauth.RefreshTokenKey(serverAddress)); eraseErr != nil {
+			return errors.Join(err, eraseErr)
+		}
+		return err
+	}
+	return m.Logout(ctx)
+}
@@ -0,0 +1,163 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.23
+
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/docker/cli/cli/config/configfile"
+	configtypes "github.com/docker/cli/cli/config/types"
+	"github.com/docker/cli/internal/oauth/manager"
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+// newFakeAuthServer returns an authorization server that supports the device
+// authorization grant, and immediately returns tokens for the device code.
+// Revoked tokens are recorded in revoked.
+func newFakeAuthServer(t *testing.T, accessToken string, revoked *[]string) *httptest.Server {
+	t.Helper()
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var resp any
+		switch r.URL.Path {
+		case "/.well-known/openid-configuration":
+			resp = map[string]string{
+				"issuer":                        ts.URL,
+				"device_authorization_endpoint": ts.URL + "/device",
+				"token_endpoint":                ts.URL + "/token",
+				"revocation_endpoint":           ts.URL + "/revoke",
+			}
+		case "/device":
+			assert.Check(t, is.Equal(r.FormValue("client_id"), defaultOAuthClientID))
+			assert.Check(t, is.Equal(r.FormValue("audience"), "https://registry.example.com"))
+			resp = map[string]any{
+				"device_code":      "a-device-code",
+				"user_code":        "0123-4567",
+				"verification_uri": ts.URL + "/activate",
+				"expires_in":       30,
+				"interval":         1,
+			}
+		case "/token":
+			assert.Check(t, is.Equal(r.FormValue("device_code"), "a-device-code"))
+			resp = map[string]any{
+				"access_token":  accessToken,
+				"refresh_token": "a-refresh-token",
+				"expires_in":    300,
+			}
+		case "/revoke":
+			*revoked = append(*revoked, r.FormValue("token"))
+			return
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		assert.Check(t, json.NewEncoder(w).Encode(resp))
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func setNoopBrowser(t *testing.T) {
+	t.Helper()
+	orig := newOAuthManager
+	newOAuthManager = func(options manager.OAuthManagerOptions) *manager.OAuthManager {
+		options.OpenBrowser = func(string) error { return nil }
+		return orig(options)
+	}
+	t.Cleanup(func() { newOAuthManager = orig })
+}
+
+func TestLoginOAuth(t *testing.T) {
+	setNoopBrowser(t)
+	t.Setenv("DOCKER_AUTH_CONFIG", "")
+	t.Setenv("DOCKER_AUTH_CONFIG_FILE", "")
+
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.HS256, Key: []byte("not-a-secret-key-for-testing-only")}, nil)
+	assert.NilError(t, err)
+	accessToken, err := jwt.Signed(signer).Claims(map[string]any{"sub": "0123", "preferred_username": "bork"}).Serialize()
+	assert.NilError(t, err)
+
+	var revoked []string
+	ts := newFakeAuthServer(t, accessToken, &revoked)
+
+	cli := newLoginTestCli(t)
+	cli.ConfigFile().OAuth = map[string]configfile.OAuthConfig{
+		"registry.example.com": {
+			Issuer:   ts.URL,
+			Audience: "https://registry.example.com",
+		},
+	}
+
+	cmd := newLoginCommand(cli)
+	cmd.SetArgs([]string{"--oauth", "https://registry.example.com"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Contains(cli.ErrBuffer().String(), "0123-4567"))
+
+	cfg := cli.ConfigFile()
+	assert.Check(t, is.DeepEqual(cfg.AuthConfigs["registry.example.com"], configtypes.AuthConfig{
+		Username:      "bork",
+		Password:      accessToken,
+		ServerAddress: "registry.example.com",
+	}))
+	assert.Check(t, is.Equal(cfg.AuthConfigs["registry.example.com/refresh-token"].Password, "a-refresh-token.."+defaultOAuthClientID))
+
+	cmd = newLogoutCommand(cli)
+	cmd.SetArgs([]string{"registry.example.com"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.DeepEqual(revoked, []string{"a-refresh-token"}))
+	assert.Check(t, is.Len(cfg.AuthConfigs, 0))
+}
+
+func TestNewRegistryOAuthManager(t *testing.T) {
+	setNoopBrowser(t)
+	t.Setenv("DOCKER_AUTH_CONFIG", "")
+	t.Setenv("DOCKER_AUTH_CONFIG_FILE", "")
+
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.HS256, Key: []byte("not-a-secret-key-for-testing-only")}, nil)
+	assert.NilError(t, err)
+	accessToken, err := jwt.Signed(signer).Claims(map[string]any{"sub": "0123", "email": "bork@example.com"}).Serialize()
+	assert.NilError(t, err)
+
+	var revoked []string
+	ts := newFakeAuthServer(t, accessToken, &revoked)
+
+	cli := newLoginTestCli(t)
+	m, oauthConfig, err := newRegistryOAuthManager(context.Background(), cli.ConfigFile(), "registry.example.com", configfile.OAuthConfig{
+		Issuer:   ts.URL,
+		Audience: "https://registry.example.com",
+	})
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(oauthConfig, configfile.OAuthConfig{
+		Issuer:   ts.URL,
+		ClientID: defaultOAuthClientID,
+		Audience: "https://registry.example.com",
+	}))
+
+	authConfig, err := m.LoginDevice(context.Background(), cli.Err())
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(authConfig.Username, "bork@example.com"))
+}
+
+func TestLoginOAuthDiscoveryError(t *testing.T) {
+	ts := httptest.NewServer(http.NotFoundHandler())
+	defer ts.Close()
+
+	cli := newLoginTestCli(t)
+	cli.ConfigFile().OAuth = map[string]configfile.OAuthConfig{
+		"registry.example.com": {Issuer: ts.URL},
+	}
+	cmd := newLoginCommand(cli)
+	cmd.SetArgs([]string{"--oauth", "registry.example.com"})
+	cmd.SetOut(cli.OutBuffer())
+	cmd.SetErr(cli.ErrBuffer())
+	err := cmd.Execute()
+	assert.Check(t, is.ErrorContains(err, "cannot log in to registry.example.com with OAuth: failed to discover authorization server metadata"))
+}
@@ -538,6 +538,11 @@ func TestLoginValidateFlags(t *testing.T) {
 			args:        []string{"--password"},
 			expectedErr: `flag needs an argument: --password`,
 		},
+		{
+			name:        "conflicting options --oauth and --username",
+			args:        []string{"--oauth", "--username", "bork"},
+			expectedErr: `conflicting options: cannot specify --oauth with --username, --password, or --password-stdin`,
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			cmd := newLoginCommand(test.NewFakeCli(&fakeClient{}))
@@ -67,6 +67,8 @@ func runLogout(ctx context.Context, dockerCLI command.Cli, serverAddress string)
 		if err := manager.NewManager(store).Logout(ctx); err != nil {
 			_, _ = fmt.Fprintln(dockerCLI.Err(), "WARNING:", err)
 		}
+	} else if err := logoutOAuth(ctx, dockerCLI, hostnameAddress); err != nil {
+		_, _ = fmt.Fprintln(dockerCLI.Err(), "WARNING:", err)
 	}
 
 	_, _ = fmt.Fprintln(dockerCLI.Out(), "Removing login credentials for", hostnameAddress)
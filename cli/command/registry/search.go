@@ -1,8 +1,14 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.23
+
 package registry
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/docker/cli/cli"
@@ -13,6 +19,7 @@
 	"github.com/moby/moby/api/pkg/authconfig"
 	registrytypes "github.com/moby/moby/api/types/registry"
 	"github.com/moby/moby/client"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
@@ -25,9 +32,30 @@ type searchOptions struct {
 	term    string
 	noTrunc bool
 	limit   int
+	page    int
+	all     bool
+	sort    string
 	filter  opts.FilterOpt
 }
 
+const (
+	// defaultSearchLimit is the number of search results that is returned
+	// by the search API if no limit is set.
+	defaultSearchLimit = 25
+	// maxSearchLimit is the maximum number of search results that is returned
+	// by the search API.
+	maxSearchLimit = 100
+)
+
+// searchResult is a search result, with the properties of the repository that
+// are not included in the results of the search API.
+type searchResult struct {
+	registrytypes.SearchResult
+	// details are the properties of a repository on Docker Hub, or nil if
+	// they were not looked up.
+	details *hubRepository
+}
+
 // newSearchCommand creates a new `docker search` command
 func newSearchCommand(dockerCLI command.Cli) *cobra.Command {
 	options := searchOptions{filter: opts.NewFilterOpt()}
@@ -51,38 +79,211 @@ func newSearchCommand(dockerCLI command.Cli) *cobra.Command {
 	flags.BoolVar(&options.noTrunc, "no-trunc", false, "Don't truncate output")
 	flags.VarP(&options.filter, "filter", "f", "Filter output based on conditions provided")
 	flags.IntVar(&options.limit, "limit", 0, "Max number of search results")
+	flags.IntVar(&options.page, "page", 1, "Page of search results to show, with --limit results per page")
+	flags.BoolVar(&options.all, "all", false, fmt.Sprintf("Show all search results, up to %d", maxSearchLimit))
+	flags.StringVar(&options.sort, "sort", "", `Sort the search results by "stars" or "pulls"`)
 	flags.StringVar(&options.format, "format", "", "Pretty-print search using a Go template")
 
 	return cmd
 }
 
 func runSearch(ctx context.Context, dockerCli command.Cli, options searchOptions) error {
+	switch options.sort {
+	case "", "stars", "pulls":
+	default:
+		return fmt.Errorf(`invalid sort order %q: must be "stars" or "pulls"`, options.sort)
+	}
+	if options.page < 1 {
+		return errors.New("invalid page: page must be 1 or higher")
+	}
+	if options.all && options.page > 1 {
+		return errors.New("conflicting options: --all and --page cannot be used together")
+	}
 	if _, ok := options.filter.Value()["is-automated"]; ok {
 		_, _ = fmt.Fprintln(dockerCli.Err(), `WARNING: the "is-automated" filter is deprecated, and searching for "is-automated=true" will not yield any results in future.`)
 	}
-	encodedAuth, err := getAuth(dockerCli, options.term)
+	minPulls, err := getPullsFilter(options.filter.Value())
 	if err != nil {
 		return err
 	}
+	withDetails := options.sort == "pulls" || minPulls > 0 ||
+		strings.Contains(options.format, ".Pulls") || strings.Contains(options.format, ".LastUpdated")
 
-	results, err := dockerCli.Client().ImageSearch(ctx, options.term, client.ImageSearchOptions{
-		RegistryAuth:  encodedAuth,
-		PrivilegeFunc: nil,
-		Filters:       options.filter.Value(),
-		Limit:         options.limit,
-	})
+	pageSize := options.limit
+	if pageSize == 0 {
+		pageSize = defaultSearchLimit
+	}
+	limit := options.limit
+	switch {
+	case options.all, options.sort != "", minPulls > 0:
+		// Sort and filter all results, before selecting the page.
+		limit = maxSearchLimit
+	case options.page > 1:
+		limit = options.page * pageSize
+		if limit > maxSearchLimit {
+			return fmt.Errorf("page %d is not available: search results are limited to %d results", options.page, maxSearchLimit)
+		}
+	}
+
+	results, err := search(ctx, dockerCli, options.term, options.filter.Value(), limit)
 	if err != nil {
 		return err
 	}
+	if withDetails && isDockerHub(options.term) {
+		if err := addHubDetails(ctx, results); err != nil {
+			return err
+		}
+	}
+	results = filterPulls(results, minPulls)
+	sortResults(results, options.sort)
+	if !options.all {
+		results = resultsPage(results, options.page, pageSize)
+	}
 
+	format := options.format
+	if withDetails && (format == "" || format == formatter.TableFormatKey) {
+		format = defaultSearchDetailsTableFormat
+	}
 	searchCtx := formatter.Context{
 		Output: dockerCli.Out(),
-		Format: newFormat(options.format),
+		Format: newFormat(format),
 		Trunc:  !options.noTrunc,
 	}
 	return formatWrite(searchCtx, results)
 }
 
+// search searches the registry of the search term through the daemon. For
+// registries other than Docker Hub, it falls back to searching the catalog
+// of the registry if the search fails, because most registries do not
+// implement the search API.
+func search(ctx context.Context, dockerCLI command.Cli, term string, filters client.Filters, limit int) ([]searchResult, error) {
+	encodedAuth, err := getAuth(dockerCLI, term)
+	if err != nil {
+		return nil, err
+	}
+
+	searchFilters := filters.Clone()
+	delete(searchFilters, "pulls")
+	found, err := dockerCLI.Client().ImageSearch(ctx, term, client.ImageSearchOptions{
+		RegistryAuth:  encodedAuth,
+		PrivilegeFunc: nil,
+		Filters:       searchFilters,
+		Limit:         limit,
+	})
+	if err != nil {
+		if isDockerHub(term) {
+			return nil, err
+		}
+		results, catalogErr := searchCatalog(ctx, dockerCLI, term)
+		if catalogErr != nil {
+			logrus.WithError(catalogErr).Debug("failed to search the catalog of the registry")
+			return nil, err
+		}
+		return filterResults(results, filters)
+	}
+
+	results := make([]searchResult, 0, len(found))
+	for _, r := range found {
+		results = append(results, searchResult{SearchResult: r})
+	}
+	return results, nil
+}
+
+// getPullsFilter returns the minimum number of pulls of the "pulls" filter,
+// which is applied by the CLI instead of the search API.
+func getPullsFilter(filters client.Filters) (int64, error) {
+	var minPulls int64
+	for value := range filters["pulls"] {
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid filter 'pulls=%s'", value)
+		}
+		minPulls = max(minPulls, n)
+	}
+	return minPulls, nil
+}
+
+// filterResults applies the filters of the search API to search results that
+// were not returned by the search API.
+func filterResults(results []searchResult, filters client.Filters) ([]searchResult, error) {
+	var minStars int
+	for value := range filters["stars"] {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid filter 'stars=%s'", value)
+		}
+		minStars = max(minStars, n)
+	}
+	for value := range filters["is-official"] {
+		if _, err := strconv.ParseBool(value); err != nil {
+			return nil, fmt.Errorf("invalid filter 'is-official=%s'", value)
+		}
+	}
+	filtered := results[:0]
+	for _, r := range results {
+		if r.StarCount < minStars {
+			continue
+		}
+		if len(filters["is-official"]) > 0 && !filters["is-official"][strconv.FormatBool(r.IsOfficial)] {
+			continue
+		}
+		filtered = append(filtered, r)
+	}
+	return filtered, nil
+}
+
+// filterPulls removes the search results with less than minPulls pulls, or
+// for which the number of pulls is unknown.
+func filterPulls(results []searchResult, minPulls int64) []searchResult {
+	if minPulls == 0 {
+		return results
+	}
+	filtered := results[:0]
+	for _, r := range results {
+		if r.details != nil && r.details.PullCount >= minPulls {
+			filtered = append(filtered, r)
+		}
+	}
+	return filtered
+}
+
+// sortResults sorts search results by the number of stars or pulls, from
+// most to least. Results for which the number of pulls is unknown are sorted
+// last.
+func sortResults(results []searchResult, order string) {
+	switch order {
+	case "stars":
+		sort.SliceStable(results, func(i, j int) bool {
+			return results[i].StarCount > results[j].StarCount
+		})
+	case "pulls":
+		pulls := func(r searchResult) int64 {
+			if r.details == nil {
+				return -1
+			}
+			return r.details.PullCount
+		}
+		sort.SliceStable(results, func(i, j int) bool {
+			return pulls(results[i]) > pulls(results[j])
+		})
+	}
+}
+
+// resultsPage returns a page of search results.
+func resultsPage(results []searchResult, page, pageSize int) []searchResult {
+	start := (page - 1) * pageSize
+	if start >= len(results) {
+		return nil
+	}
+	return results[start:min(start+pageSize, len(results))]
+}
+
+// isDockerHub returns whether the search term searches Docker Hub.
+func isDockerHub(term string) bool {
+	indexName := splitReposSearchTerm(term)
+	return indexName == "docker.io" || indexName == "index.docker.io"
+}
+
 // authConfigKey is the key used to store credentials for Docker Hub. It is
 // a copy of [registry.IndexServer].
 //
@@ -0,0 +1,32 @@
+package registry
+
+import (
+	"context"
+	"strings"
+
+	"github.com/docker/cli/cli/command"
+	registrytypes "github.com/moby/moby/api/types/registry"
+)
+
+// searchCatalog searches the catalog of a registry that does not implement
+// the search API. It returns the repositories that contain the search term
+// in their name. Registries may only include the repositories that the user
+// has access to in the catalog.
+func searchCatalog(ctx context.Context, dockerCLI command.Cli, term string) ([]searchResult, error) {
+	domain := splitReposSearchTerm(term)
+	remoteName := strings.ToLower(strings.TrimPrefix(term, domain+"/"))
+
+	repositories, err := command.NewRegistryClient(dockerCLI, false).GetCatalog(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+	var results []searchResult
+	for _, repo := range repositories {
+		if strings.Contains(strings.ToLower(repo), remoteName) {
+			results = append(results, searchResult{
+				SearchResult: registrytypes.SearchResult{Name: domain + "/" + repo},
+			})
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,89 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/sirupsen/logrus"
+)
+
+// hubURL is the URL of the Docker Hub API, which provides the properties of
+// repositories on Docker Hub that are not included in search results.
+var hubURL = "https://hub.docker.com"
+
+// maxConcurrentHubRequests is the maximum number of concurrent requests to
+// the Docker Hub API when looking up the properties of repositories.
+const maxConcurrentHubRequests = 8
+
+// hubRepository is the subset of a repository in the Docker Hub API that is
+// used for search results.
+type hubRepository struct {
+	PullCount   int64     `json:"pull_count"`
+	LastUpdated time.Time `json:"last_updated"`
+}
+
+// addHubDetails looks up the number of pulls, and the time the latest tag was
+// pushed, for search results on Docker Hub. Search results for which the
+// lookup fails are left as-is.
+func addHubDetails(ctx context.Context, results []searchResult) error {
+	httpClient := &http.Client{Timeout: 30 * time.Second}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxConcurrentHubRequests)
+	for i := range results {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return ctx.Err()
+		}
+		wg.Add(1)
+		go func(r *searchResult) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			repo, err := getHubRepository(ctx, httpClient, r.Name)
+			if err != nil {
+				logrus.WithError(err).Debugf("failed to get details of repository %s", r.Name)
+				return
+			}
+			r.details = repo
+		}(&results[i])
+	}
+	wg.Wait()
+	return ctx.Err()
+}
+
+func getHubRepository(ctx context.Context, httpClient *http.Client, name string) (*hubRepository, error) {
+	namespace, repoName, ok := strings.Cut(name, "/")
+	if !ok {
+		namespace, repoName = "library", name
+	}
+	u := hubURL + "/v2/repositories/" + url.PathEscape(namespace) + "/" + url.PathEscape(repoName) + "/"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", command.UserAgent())
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+	var repo hubRepository
+	if err := json.NewDecoder(resp.Body).Decode(&repo); err != nil {
+		return nil, err
+	}
+	return &repo, nil
+}
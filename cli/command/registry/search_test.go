@@ -0,0 +1,204 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/docker/cli/internal/registryclient"
+	"github.com/docker/cli/internal/test"
+	registrytypes "github.com/moby/moby/api/types/registry"
+	"github.com/moby/moby/client"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+type fakeSearchClient struct {
+	client.Client
+	imageSearchFunc func(term string, options client.ImageSearchOptions) ([]registrytypes.SearchResult, error)
+}
+
+func (c *fakeSearchClient) ImageSearch(_ context.Context, term string, options client.ImageSearchOptions) ([]registrytypes.SearchResult, error) {
+	return c.imageSearchFunc(term, options)
+}
+
+type fakeRegistryClient struct {
+	registryclient.RegistryClient
+	getCatalogFunc func(domain string) ([]string, error)
+}
+
+func (c *fakeRegistryClient) GetCatalog(_ context.Context, domain string) ([]string, error) {
+	return c.getCatalogFunc(domain)
+}
+
+func hubSearchResults(term string, options client.ImageSearchOptions) ([]registrytypes.SearchResult, error) {
+	results := []registrytypes.SearchResult{
+		{Name: term, StarCount: 20000, IsOfficial: true},
+		{Name: "bitnami/" + term, StarCount: 200},
+		{Name: "user1/" + term, StarCount: 50},
+		{Name: "user2/" + term, StarCount: 5},
+	}
+	if options.Limit > 0 && options.Limit < len(results) {
+		results = results[:options.Limit]
+	}
+	return results, nil
+}
+
+func TestSearchPagination(t *testing.T) {
+	testCases := []struct {
+		doc           string
+		args          []string
+		expectedLimit int
+		expected      string
+		expectedErr   string
+	}{
+		{
+			doc:           "default",
+			args:          []string{"--format", "{{.Name}}", "nginx"},
+			expectedLimit: 0,
+			expected:      "nginx\nbitnami/nginx\nuser1/nginx\nuser2/nginx\n",
+		},
+		{
+			doc:           "second page",
+			args:          []string{"--format", "{{.Name}}", "--limit", "2", "--page", "2", "nginx"},
+			expectedLimit: 4,
+			expected:      "user1/nginx\nuser2/nginx\n",
+		},
+		{
+			doc:           "all",
+			args:          []string{"--format", "{{.Name}}", "--all", "nginx"},
+			expectedLimit: maxSearchLimit,
+			expected:      "nginx\nbitnami/nginx\nuser1/nginx\nuser2/nginx\n",
+		},
+		{
+			doc:           "sort by stars",
+			args:          []string{"--format", "{{.Name}}", "--limit", "2", "--sort", "stars", "nginx"},
+			expectedLimit: maxSearchLimit,
+			expected:      "nginx\nbitnami/nginx\n",
+		},
+		{
+			doc:         "page out of range",
+			args:        []string{"--limit", "50", "--page", "3", "nginx"},
+			expectedErr: "page 3 is not available: search results are limited to 100 results",
+		},
+		{
+			doc:         "all and page",
+			args:        []string{"--all", "--page", "2", "nginx"},
+			expectedErr: "conflicting options: --all and --page cannot be used together",
+		},
+		{
+			doc:         "invalid sort",
+			args:        []string{"--sort", "name", "nginx"},
+			expectedErr: `invalid sort order "name": must be "stars" or "pulls"`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.doc, func(t *testing.T) {
+			cli := test.NewFakeCli(&fakeSearchClient{
+				imageSearchFunc: func(term string, options client.ImageSearchOptions) ([]registrytypes.SearchResult, error) {
+					assert.Check(t, is.Equal(options.Limit, tc.expectedLimit))
+					return hubSearchResults(term, options)
+				},
+			})
+			cmd := newSearchCommand(cli)
+			cmd.SetArgs(tc.args)
+			cmd.SilenceUsage = true
+			cmd.SilenceErrors = true
+			err := cmd.Execute()
+			if tc.expectedErr != "" {
+				assert.Check(t, is.Error(err, tc.expectedErr))
+				return
+			}
+			assert.NilError(t, err)
+			assert.Check(t, is.Equal(cli.OutBuffer().String(), tc.expected))
+		})
+	}
+}
+
+func TestSearchPulls(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/repositories/library/nginx/":
+			_, _ = w.Write([]byte(`{"pull_count": 1000000000, "last_updated": "2024-03-01T10:00:00.000000Z"}`))
+		case "/v2/repositories/bitnami/nginx/":
+			_, _ = w.Write([]byte(`{"pull_count": 5000000, "last_updated": "2024-03-02T10:00:00.000000Z"}`))
+		case "/v2/repositories/user1/nginx/":
+			_, _ = w.Write([]byte(`{"pull_count": 10000000, "last_updated": "2024-03-03T10:00:00.000000Z"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	defer func(original string) { hubURL = original }(hubURL)
+	hubURL = server.URL
+
+	cli := test.NewFakeCli(&fakeSearchClient{
+		imageSearchFunc: func(term string, options client.ImageSearchOptions) ([]registrytypes.SearchResult, error) {
+			_, ok := options.Filters["pulls"]
+			assert.Check(t, !ok, "pulls filter must not be sent to the daemon")
+			return hubSearchResults(term, options)
+		},
+	})
+	cmd := newSearchCommand(cli)
+	cmd.SetArgs([]string{"--sort", "pulls", "--filter", "pulls=6000000", "--format", "{{.Name}} {{.Pulls}}", "nginx"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "nginx 1000000000\nuser1/nginx 10000000\n"))
+
+	cli.OutBuffer().Reset()
+	cmd = newSearchCommand(cli)
+	cmd.SetArgs([]string{"--format", "{{json .}}", "--limit", "1", "nginx"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), `{"Description":"","IsOfficial":"true","LastUpdated":"","Name":"nginx","Pulls":"","StarCount":"20000"}`+"\n"))
+
+	cli.OutBuffer().Reset()
+	cmd = newSearchCommand(cli)
+	cmd.SetArgs([]string{"--format", "{{.Name}}\t{{.LastUpdated}}", "--limit", "1", "nginx"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Contains(cli.OutBuffer().String(), "nginx\t"))
+	assert.Check(t, is.Contains(cli.OutBuffer().String(), " ago\n"))
+}
+
+func TestSearchCatalog(t *testing.T) {
+	cli := test.NewFakeCli(&fakeSearchClient{
+		imageSearchFunc: func(string, client.ImageSearchOptions) ([]registrytypes.SearchResult, error) {
+			return nil, errors.New("Unexpected status code 404")
+		},
+	})
+	cli.SetRegistryClient(&fakeRegistryClient{
+		getCatalogFunc: func(domain string) ([]string, error) {
+			assert.Check(t, is.Equal(domain, "registry.example.com"))
+			return []string{"team/Nginx-proxy", "team/redis", "nginx"}, nil
+		},
+	})
+	cmd := newSearchCommand(cli)
+	cmd.SetArgs([]string{"--format", "{{.Name}}", "registry.example.com/nginx"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "registry.example.com/team/Nginx-proxy\nregistry.example.com/nginx\n"))
+
+	cli.OutBuffer().Reset()
+	cmd = newSearchCommand(cli)
+	cmd.SetArgs([]string{"--filter", "is-official=true", "registry.example.com/nginx"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(strings.TrimSpace(cli.OutBuffer().String()), "NAME      DESCRIPTION   STARS     OFFICIAL"))
+}
+
+func TestSearchCatalogNotSupported(t *testing.T) {
+	cli := test.NewFakeCli(&fakeSearchClient{
+		imageSearchFunc: func(string, client.ImageSearchOptions) ([]registrytypes.SearchResult, error) {
+			return nil, errors.New("Unexpected status code 404")
+		},
+	})
+	cli.SetRegistryClient(&fakeRegistryClient{
+		getCatalogFunc: func(string) ([]string, error) {
+			return nil, errors.New("unauthorized")
+		},
+	})
+	cmd := newSearchCommand(cli)
+	cmd.SetArgs([]string{"registry.example.com/nginx"})
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	assert.Check(t, is.Error(cmd.Execute(), "Unexpected status code 404"))
+}
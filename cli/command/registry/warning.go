@@ -2,17 +2,34 @@
 
 import (
 	"os"
+	"strings"
 
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/config/configfile"
 	"github.com/docker/cli/internal/tui"
 )
 
-// maybePrintEnvAuthWarning if the `DOCKER_AUTH_CONFIG` environment variable is
-// set this function will output a warning to stdErr
+// maybePrintEnvAuthWarning if the `DOCKER_AUTH_CONFIG` or `DOCKER_AUTH_CONFIG_FILE`
+// environment variable is set this function will output a warning to stdErr
 func maybePrintEnvAuthWarning(out command.Streams) {
-	if os.Getenv(configfile.DockerEnvConfigKey) != "" {
-		tui.NewOutput(out.Err()).
-			PrintWarning("%[1]s is set and takes precedence.\nUnset %[1]s to restore the CLI auth behaviour.\n", configfile.DockerEnvConfigKey)
+	for _, envKey := range []string{configfile.DockerEnvConfigKey, configfile.DockerEnvConfigFileKey} {
+		if os.Getenv(envKey) != "" {
+			tui.NewOutput(out.Err()).
+				PrintWarning("%[1]s is set and takes precedence.\nUnset %[1]s to restore the CLI auth behaviour.\n", envKey)
+			return
+		}
 	}
 }
+
+// maybePrintPlaintextWarning outputs a warning to stdErr listing the
+// registries for which credentials are stored unencrypted in the CLI
+// configuration file, if any.
+func maybePrintPlaintextWarning(out command.Streams, configFile *configfile.ConfigFile) {
+	registries := plaintextRegistries(configFile)
+	if len(registries) == 0 {
+		return
+	}
+	tui.NewOutput(out.Err()).
+		PrintWarning("Credentials for the following registries are stored unencrypted in '%s':\n  %s\nRun 'docker login migrate --to <helper>' to move them to a credential helper.\n",
+			configFile.GetFilename(), strings.Join(registries, "\n  "))
+}
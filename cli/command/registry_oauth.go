@@ -0,0 +1,187 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/cli/config/credentials"
+	configtypes "github.com/docker/cli/cli/config/types"
+	"github.com/docker/cli/internal/oauth"
+	"github.com/docker/cli/internal/oauth/api"
+	"github.com/docker/cli/internal/registry"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// oauthRefreshTimeout is the maximum time refreshing an access token can take.
+	oauthRefreshTimeout = 30 * time.Second
+
+	// oauthExpiryWindow is how long before they expire access tokens are
+	// refreshed, so that they don't expire during a pull or push.
+	oauthExpiryWindow = time.Minute
+)
+
+var (
+	// credentialsMu serializes access to the credentials store when access
+	// tokens may be refreshed, because the store is not safe for concurrent
+	// use, and "docker pull" and "docker push" resolve the credentials of
+	// multiple images concurrently.
+	credentialsMu sync.Mutex
+
+	// oauthRefreshMu holds a mutex for each registry, so that an access
+	// token is only refreshed once at a time. Refresh tokens may only be
+	// used once.
+	oauthRefreshMu   sync.Mutex
+	oauthRefreshLock = map[string]*sync.Mutex{}
+)
+
+// GetAllCredentials returns the credentials of all registries from the
+// credential-store. The access tokens of registries that were logged in to
+// with "docker login --oauth" are refreshed if they are about to expire.
+func GetAllCredentials(cfg *configfile.ConfigFile) (map[string]configtypes.AuthConfig, error) {
+	credentialsMu.Lock()
+	auths, err := cfg.GetAllCredentials()
+	credentialsMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	for serverAddress, ac := range auths {
+		auths[serverAddress] = refreshOAuthToken(cfg, serverAddress, ac)
+	}
+	return auths, nil
+}
+
+// getAuthConfig returns the credentials for serverAddress from the
+// credential-store, and refreshes the access token if it's about to expire.
+func getAuthConfig(cfg *configfile.ConfigFile, serverAddress string) (configtypes.AuthConfig, error) {
+	credentialsMu.Lock()
+	ac, err := cfg.GetAuthConfig(serverAddress)
+	credentialsMu.Unlock()
+	if err != nil {
+		return ac, err
+	}
+	return refreshOAuthToken(cfg, serverAddress, ac), nil
+}
+
+// refreshOAuthToken returns the credentials with a new access token if the
+// registry was logged in to with "docker login --oauth", and the access token
+// is about to expire. If refreshing fails, a warning is logged, and the
+// credentials are returned as-is, so that the registry can reject them.
+func refreshOAuthToken(cfg *configfile.ConfigFile, serverAddress string, ac configtypes.AuthConfig) configtypes.AuthConfig {
+	oauthConfig, ok := cfg.OAuth[serverAddress]
+	if !ok || !oauthTokenExpires(ac.Password, time.Now()) {
+		return ac
+	}
+
+	mu := oauthRefreshMutex(serverAddress)
+	mu.Lock()
+	defer mu.Unlock()
+
+	// The token may have been refreshed while waiting for the lock.
+	store := cfg.GetCredentialsStore(serverAddress)
+	credentialsMu.Lock()
+	current, err := store.Get(serverAddress)
+	credentialsMu.Unlock()
+	if err == nil && current.Password != "" {
+		if !oauthTokenExpires(current.Password, time.Now()) {
+			return current
+		}
+		ac = current
+	}
+
+	refreshed, err := refreshOAuthAccessToken(store, serverAddress, oauthConfig, ac)
+	if err != nil {
+		logrus.Warnf("Failed to refresh the access token for %s: %v. Run 'docker login --oauth %s' to log in again.", serverAddress, err, serverAddress)
+		return ac
+	}
+	return refreshed
+}
+
+// oauthRefreshMutex returns the mutex that's held while refreshing the access
+// token for serverAddress.
+func oauthRefreshMutex(serverAddress string) *sync.Mutex {
+	oauthRefreshMu.Lock()
+	defer oauthRefreshMu.Unlock()
+	mu, ok := oauthRefreshLock[serverAddress]
+	if !ok {
+		mu = &sync.Mutex{}
+		oauthRefreshLock[serverAddress] = mu
+	}
+	return mu
+}
+
+func refreshOAuthAccessToken(store credentials.Store, serverAddress string, oauthConfig configfile.OAuthConfig, ac configtypes.AuthConfig) (configtypes.AuthConfig, error) {
+	credentialsMu.Lock()
+	stored, err := store.Get(oauth.RefreshTokenKey(serverAddress))
+	credentialsMu.Unlock()
+	if err != nil {
+		return configtypes.AuthConfig{}, err
+	}
+	refreshToken, clientID, ok := oauth.SplitRefreshToken(stored.Password)
+	if !ok {
+		return configtypes.AuthConfig{}, errors.New("no refresh token is stored")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), oauthRefreshTimeout)
+	defer cancel()
+	authServer := oauthConfig.Issuer
+	if authServer == "" {
+		authServer = oauthConfig.TokenEndpoint
+	}
+	if authServer == "" {
+		return configtypes.AuthConfig{}, errors.New("no issuer or token endpoint is configured")
+	}
+	httpClient, err := registry.NewHTTPClient(ctx, authServer)
+	if err != nil {
+		return configtypes.AuthConfig{}, err
+	}
+	metadata, err := api.ResolveMetadata(ctx, httpClient, api.ServerMetadata{
+		Issuer:                      oauthConfig.Issuer,
+		DeviceAuthorizationEndpoint: oauthConfig.DeviceAuthorizationEndpoint,
+		TokenEndpoint:               oauthConfig.TokenEndpoint,
+		RevocationEndpoint:          oauthConfig.RevocationEndpoint,
+	})
+	if err != nil {
+		return configtypes.AuthConfig{}, err
+	}
+	res, err := api.API{Metadata: metadata, ClientID: clientID, HTTPClient: httpClient}.Refresh(ctx, refreshToken)
+	if err != nil {
+		return configtypes.AuthConfig{}, err
+	}
+	if res.AccessToken == "" {
+		return configtypes.AuthConfig{}, errors.New("no access token in response")
+	}
+
+	credentialsMu.Lock()
+	defer credentialsMu.Unlock()
+	ac.Password = res.AccessToken
+	if err := store.Store(ac); err != nil {
+		return configtypes.AuthConfig{}, err
+	}
+	if res.RefreshToken != "" && res.RefreshToken != refreshToken {
+		// the authorization server rotated the refresh token.
+		stored.Password = oauth.JoinRefreshToken(res.RefreshToken, clientID)
+		stored.ServerAddress = oauth.RefreshTokenKey(serverAddress)
+		if err := store.Store(stored); err != nil {
+			return configtypes.AuthConfig{}, err
+		}
+	}
+	return ac, nil
+}
+
+// oauthTokenExpires returns whether token is a JWT that expires within the
+// oauthExpiryWindow. Tokens that are not a JWT, or don't expire are never
+// refreshed.
+func oauthTokenExpires(token string, now time.Time) bool {
+	if token == "" {
+		return false
+	}
+	claims, err := oauth.GetClaims(token)
+	if err != nil || claims.Expiry == nil {
+		return false
+	}
+	return !now.Add(oauthExpiryWindow).Before(claims.Expiry.Time())
+}
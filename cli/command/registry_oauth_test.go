@@ -0,0 +1,184 @@
+package command_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/config/configfile"
+	configtypes "github.com/docker/cli/cli/config/types"
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	"github.com/moby/moby/api/pkg/authconfig"
+	"github.com/moby/moby/api/types/registry"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+// newTestToken returns an (insecure) signed JWT that expires at the given time.
+func newTestToken(t *testing.T, expiresAt time.Time) string {
+	t.Helper()
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.HS256, Key: []byte("not-a-secret-key-for-testing-only")}, nil)
+	assert.NilError(t, err)
+	token, err := jwt.Signed(signer).Claims(jwt.Claims{
+		Subject: "bork",
+		Expiry:  jwt.NewNumericDate(expiresAt),
+	}).Serialize()
+	assert.NilError(t, err)
+	return token
+}
+
+// newTokenServer returns a token endpoint that returns the given access and
+// refresh token, and a pointer to the number of requests it received.
+func newTokenServer(t *testing.T, accessToken, refreshToken string) (*httptest.Server, *int) {
+	t.Helper()
+	var calls int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		assert.Check(t, is.Equal(r.FormValue("grant_type"), "refresh_token"))
+		assert.Check(t, is.Equal(r.FormValue("refresh_token"), "a-refresh-token"))
+		assert.Check(t, is.Equal(r.FormValue("client_id"), "docker-cli"))
+		if accessToken == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"invalid_grant","error_description":"refresh token expired"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"access_token":"` + accessToken + `","refresh_token":"` + refreshToken + `","expires_in":300}`))
+	}))
+	t.Cleanup(ts.Close)
+	return ts, &calls
+}
+
+func newOAuthConfigFile(t *testing.T, tokenEndpoint, accessToken string) *configfile.ConfigFile {
+	t.Helper()
+	t.Setenv(configfile.DockerEnvConfigKey, "")
+	t.Setenv(configfile.DockerEnvConfigFileKey, "")
+
+	configFile := configfile.New(filepath.Join(t.TempDir(), "config.json"))
+	configFile.OAuth = map[string]configfile.OAuthConfig{
+		"registry.example.com": {
+			DeviceAuthorizationEndpoint: "https://auth.example.com/device",
+			TokenEndpoint:               tokenEndpoint,
+		},
+	}
+	configFile.AuthConfigs = map[string]configtypes.AuthConfig{
+		"registry.example.com": {
+			Username:      "bork",
+			Password:      accessToken,
+			ServerAddress: "registry.example.com",
+		},
+		"registry.example.com/refresh-token": {
+			Username:      "bork",
+			Password:      "a-refresh-token..docker-cli",
+			ServerAddress: "registry.example.com/refresh-token",
+		},
+	}
+	return configFile
+}
+
+func TestResolveAuthConfigOAuthRefresh(t *testing.T) {
+	newToken := newTestToken(t, time.Now().Add(time.Hour))
+	ts, calls := newTokenServer(t, newToken, "a-new-refresh-token")
+	configFile := newOAuthConfigFile(t, ts.URL, newTestToken(t, time.Now().Add(-time.Minute)))
+
+	ac := command.ResolveAuthConfig(configFile, &registry.IndexInfo{Name: "registry.example.com"})
+	assert.Check(t, is.Equal(ac.Username, "bork"))
+	assert.Check(t, is.Equal(ac.Password, newToken))
+	assert.Check(t, is.Equal(*calls, 1))
+
+	// the new tokens are stored, and not refreshed again.
+	assert.Check(t, is.Equal(configFile.AuthConfigs["registry.example.com"].Password, newToken))
+	assert.Check(t, is.Equal(configFile.AuthConfigs["registry.example.com/refresh-token"].Password, "a-new-refresh-token..docker-cli"))
+
+	auths, err := command.GetAllCredentials(configFile)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(auths["registry.example.com"].Password, newToken))
+	assert.Check(t, is.Equal(*calls, 1))
+}
+
+func TestResolveAuthConfigOAuthRefreshConcurrent(t *testing.T) {
+	newToken := newTestToken(t, time.Now().Add(time.Hour))
+	ts, calls := newTokenServer(t, newToken, "a-new-refresh-token")
+	configFile := newOAuthConfigFile(t, ts.URL, newTestToken(t, time.Now().Add(-time.Minute)))
+	configFile.OAuth["other.example.com"] = configFile.OAuth["registry.example.com"]
+	configFile.AuthConfigs["other.example.com"] = configtypes.AuthConfig{
+		Username:      "bork",
+		Password:      configFile.AuthConfigs["registry.example.com"].Password,
+		ServerAddress: "other.example.com",
+	}
+	configFile.AuthConfigs["other.example.com/refresh-token"] = configtypes.AuthConfig{
+		Username:      "bork",
+		Password:      "a-refresh-token..docker-cli",
+		ServerAddress: "other.example.com/refresh-token",
+	}
+
+	// "docker pull" and "docker push" resolve the credentials of multiple
+	// images concurrently, and the refresh token may only be used once.
+	const n = 8
+	passwords := make([]string, 2*n)
+	var wg sync.WaitGroup
+	for i := range passwords {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			name := "registry.example.com"
+			if i%2 == 1 {
+				name = "other.example.com"
+			}
+			passwords[i] = command.ResolveAuthConfig(configFile, &registry.IndexInfo{Name: name}).Password
+		}(i)
+	}
+	wg.Wait()
+
+	for _, password := range passwords {
+		assert.Check(t, is.Equal(password, newToken))
+	}
+	assert.Check(t, is.Equal(*calls, 2))
+}
+
+func TestRetrieveAuthTokenFromImageOAuthRefresh(t *testing.T) {
+	newToken := newTestToken(t, time.Now().Add(time.Hour))
+	ts, calls := newTokenServer(t, newToken, "")
+	configFile := newOAuthConfigFile(t, ts.URL, newTestToken(t, time.Now().Add(-time.Minute)))
+
+	encoded, err := command.RetrieveAuthTokenFromImage(configFile, "registry.example.com/bork/image:latest")
+	assert.NilError(t, err)
+	ac, err := authconfig.Decode(encoded)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(ac.Password, newToken))
+	assert.Check(t, is.Equal(*calls, 1))
+
+	// the refresh token was not rotated.
+	assert.Check(t, is.Equal(configFile.AuthConfigs["registry.example.com/refresh-token"].Password, "a-refresh-token..docker-cli"))
+}
+
+func TestGetAllCredentialsOAuthNotExpired(t *testing.T) {
+	ts, calls := newTokenServer(t, "unused", "")
+	for _, token := range []string{
+		newTestToken(t, time.Now().Add(time.Hour)),
+		"an-opaque-token",
+	} {
+		configFile := newOAuthConfigFile(t, ts.URL, token)
+		auths, err := command.GetAllCredentials(configFile)
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(auths["registry.example.com"].Password, token))
+	}
+	assert.Check(t, is.Equal(*calls, 0))
+}
+
+func TestResolveAuthConfigOAuthRefreshError(t *testing.T) {
+	ts, calls := newTokenServer(t, "", "")
+	expired := newTestToken(t, time.Now().Add(-time.Minute))
+	configFile := newOAuthConfigFile(t, ts.URL, expired)
+
+	// the expired credentials are returned, so that the registry can
+	// reject them.
+	ac := command.ResolveAuthConfig(configFile, &registry.IndexInfo{Name: "registry.example.com"})
+	assert.Check(t, is.Equal(ac.Password, expired))
+	assert.Check(t, is.Equal(*calls, 1))
+	assert.Check(t, is.Equal(configFile.AuthConfigs["registry.example.com/refresh-token"].Password, "a-refresh-token..docker-cli"))
+}
@@ -4,10 +4,14 @@
 	"context"
 	"errors"
 	"io"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/docker/cli/cli/config/configfile"
 	"github.com/docker/cli/internal/test"
+	"github.com/moby/moby/api/types/container"
 	"github.com/moby/moby/client"
 	"gotest.tools/v3/assert"
 	is "gotest.tools/v3/assert/cmp"
@@ -68,3 +72,71 @@ func TestSystemPrunePromptTermination(t *testing.T) {
 	cmd.SetErr(io.Discard)
 	test.TerminatePrompt(ctx, t, cmd, cli)
 }
+
+func TestPrunePromptImagePolicy(t *testing.T) {
+	policyFile := filepath.Join(t.TempDir(), "policy.json")
+	assert.NilError(t, os.WriteFile(policyFile, []byte(`{"keepLast": 3}`), 0o644))
+
+	cli := test.NewFakeCli(&fakeClient{version: "1.51"})
+	cmd := newPruneCommand(cli)
+	cmd.SetArgs([]string{"--image-policy", policyFile})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+
+	assert.ErrorContains(t, cmd.Execute(), "system prune has been cancelled")
+	assert.Check(t, is.Contains(cli.OutBuffer().String(), "  - all image tags not retained by the retention policy in "+policyFile+"\n"))
+	assert.Check(t, !strings.Contains(cli.OutBuffer().String(), "all dangling images"))
+}
+
+func TestPruneImagePolicyConflicts(t *testing.T) {
+	policyFile := filepath.Join(t.TempDir(), "policy.json")
+	assert.NilError(t, os.WriteFile(policyFile, []byte(`{"keepLast": 3}`), 0o644))
+
+	for _, tc := range []struct {
+		args        []string
+		expectedErr string
+	}{
+		{
+			args:        []string{"--all"},
+			expectedErr: "conflicting options: --all cannot be used with --image-policy",
+		},
+		{
+			args:        []string{"--filter", "until=24h"},
+			expectedErr: "conflicting options: --filter cannot be used with --image-policy",
+		},
+	} {
+		t.Run(tc.args[0], func(t *testing.T) {
+			cli := test.NewFakeCli(&fakeClient{version: "1.51"})
+			cmd := newPruneCommand(cli)
+			cmd.SetArgs(append([]string{"--force", "--image-policy", policyFile}, tc.args...))
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+
+			assert.ErrorContains(t, cmd.Execute(), tc.expectedErr)
+		})
+	}
+}
+
+func TestSystemPrunePartialFailure(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		version: "1.51",
+		containerPruneFunc: func(context.Context, client.ContainerPruneOptions) (client.ContainerPruneResult, error) {
+			return client.ContainerPruneResult{Report: container.PruneReport{
+				ContainersDeleted: []string{"abc123"},
+				SpaceReclaimed:    2048,
+			}}, nil
+		},
+		networkPruneFunc: func(context.Context, client.NetworkPruneOptions) (client.NetworkPruneResult, error) {
+			return client.NetworkPruneResult{}, errors.New("network prune failed")
+		},
+	})
+	cmd := newPruneCommand(cli)
+	cmd.SetArgs([]string{"--force"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+
+	// the containers that were removed are printed before the error is returned.
+	assert.Check(t, is.ErrorContains(cmd.Execute(), "network prune failed"))
+	assert.Check(t, is.Contains(cli.OutBuffer().String(), "abc123"))
+	assert.Check(t, is.Contains(cli.OutBuffer().String(), "Total reclaimed space: 2.048kB"))
+}
@@ -0,0 +1,208 @@
+package configfile
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/cli/cli/config/credentials"
+	"github.com/docker/cli/cli/config/types"
+)
+
+const (
+	// expiryWindow is how long before they expire credentials are refreshed,
+	// so that they don't expire during a pull or push.
+	expiryWindow = time.Minute
+
+	// refreshCommandTimeout is the maximum time a refresh command can take.
+	refreshCommandTimeout = 2 * time.Minute
+
+	// envRefreshRegistry is the environment variable that's set to the address
+	// of the registry when running a refresh command.
+	envRefreshRegistry = "DOCKER_AUTH_REGISTRY"
+)
+
+// envCredential holds the credentials for a registry from DOCKER_AUTH_CONFIG
+// that expire, or that are provided by a refresh command.
+type envCredential struct {
+	authConfig     types.AuthConfig
+	expiresAt      time.Time
+	refreshCommand []string
+}
+
+func newEnvCredential(serverAddress string, envAuth configEnvAuth) (envCredential, error) {
+	username, password, err := decodeAuth(envAuth.Auth)
+	if err != nil {
+		return envCredential{}, err
+	}
+	return envCredential{
+		authConfig: types.AuthConfig{
+			Username:      username,
+			Password:      password,
+			ServerAddress: serverAddress,
+		},
+		expiresAt:      envAuth.ExpiresAt,
+		refreshCommand: envAuth.RefreshCommand,
+	}, nil
+}
+
+// isStale returns whether the credentials are missing, or expire within the
+// expiryWindow.
+func (c envCredential) isStale(now time.Time) bool {
+	if c.authConfig.Username == "" && c.authConfig.Password == "" {
+		return true
+	}
+	return !c.expiresAt.IsZero() && !now.Add(expiryWindow).Before(c.expiresAt)
+}
+
+// refreshedCredentials holds the credentials that were obtained from refresh
+// commands, so that refresh commands run at most once per registry for each
+// time the credentials expire.
+var refreshedCredentials = struct {
+	sync.Mutex
+	creds map[string]envCredential
+}{creds: make(map[string]envCredential)}
+
+// runRefreshCommand runs the refresh command for the given registry and
+// returns its output. It's a variable so that it can be replaced in tests.
+var runRefreshCommand = func(ctx context.Context, serverAddress string, command []string) ([]byte, error) {
+	var stdout bytes.Buffer
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...) //nolint:gosec // ignore G204: the command is set by the user
+	cmd.Env = append(os.Environ(), envRefreshRegistry+"="+serverAddress)
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return nil, err
+	}
+	return stdout.Bytes(), nil
+}
+
+// envStore is a credentials store for credentials from DOCKER_AUTH_CONFIG
+// that expire, or that are provided by a refresh command. Other credentials
+// are looked up in the fallback store.
+//
+// When credentials are looked up, and they are missing or about to expire,
+// the refresh command is run, which prints the new credentials as a JSON
+// object, for example:
+//
+//	{"auth": "dXNlcjp0b2tlbg==", "expiresAt": "2025-06-01T12:00:00Z"}
+type envStore struct {
+	fallback credentials.Store
+	expiring map[string]envCredential
+	// source is the name of the environment variable the credentials are
+	// provided through, for use in messages.
+	source string
+}
+
+// Erase removes credentials from the store for a given server.
+func (s *envStore) Erase(serverAddress string) error {
+	delete(s.expiring, serverAddress)
+	return s.fallback.Erase(serverAddress)
+}
+
+// Get retrieves credentials from the store for a given server, and refreshes
+// them if they are missing or about to expire.
+func (s *envStore) Get(serverAddress string) (types.AuthConfig, error) {
+	cred, ok := s.expiring[serverAddress]
+	if !ok {
+		return s.fallback.Get(serverAddress)
+	}
+	cred, err := s.refresh(serverAddress, cred)
+	if err != nil {
+		return types.AuthConfig{}, err
+	}
+	return cred.authConfig, nil
+}
+
+// GetAll retrieves all the credentials from the store, and refreshes the
+// credentials that are missing or about to expire.
+func (s *envStore) GetAll() (map[string]types.AuthConfig, error) {
+	auths, err := s.fallback.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	for serverAddress, cred := range s.expiring {
+		cred, err := s.refresh(serverAddress, cred)
+		if err != nil {
+			// refresh already printed the error.
+			continue
+		}
+		auths[serverAddress] = cred.authConfig
+	}
+	return auths, nil
+}
+
+// Store saves credentials in the store.
+func (s *envStore) Store(authConfig types.AuthConfig) error {
+	delete(s.expiring, authConfig.ServerAddress)
+	return s.fallback.Store(authConfig)
+}
+
+// refresh runs the refresh command for the given credentials if they are
+// stale. Credentials that are stale, but have no refresh command are
+// returned as-is, with a warning.
+func (s *envStore) refresh(serverAddress string, cred envCredential) (envCredential, error) {
+	now := time.Now()
+	if !cred.isStale(now) {
+		return cred, nil
+	}
+	if len(cred.refreshCommand) == 0 {
+		_, _ = fmt.Fprintf(os.Stderr, "WARNING: credentials for %s in %s expired at %s, and no refreshCommand is set\n", serverAddress, s.source, cred.expiresAt.Format(time.RFC3339))
+		return cred, nil
+	}
+
+	refreshedCredentials.Lock()
+	defer refreshedCredentials.Unlock()
+	if c, ok := refreshedCredentials.creds[serverAddress]; ok && !c.isStale(now) {
+		return c, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), refreshCommandTimeout)
+	defer cancel()
+	out, err := runRefreshCommand(ctx, serverAddress, cred.refreshCommand)
+	if err != nil {
+		err = fmt.Errorf("failed to refresh credentials for %s from %s: %w", serverAddress, s.source, err)
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		return envCredential{}, err
+	}
+	refreshed, err := parseRefreshOutput(serverAddress, out)
+	if err != nil {
+		err = fmt.Errorf("failed to refresh credentials for %s from %s: invalid output from refreshCommand: %w", serverAddress, s.source, err)
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		return envCredential{}, err
+	}
+	refreshed.refreshCommand = cred.refreshCommand
+	refreshedCredentials.creds[serverAddress] = refreshed
+	return refreshed, nil
+}
+
+// parseRefreshOutput parses the JSON object printed by a refresh command.
+func parseRefreshOutput(serverAddress string, out []byte) (envCredential, error) {
+	var output struct {
+		Auth      string    `json:"auth"`
+		ExpiresAt time.Time `json:"expiresAt,omitempty"`
+	}
+	decoder := json.NewDecoder(bytes.NewReader(out))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&output); err != nil {
+		if errors.Is(err, io.EOF) {
+			return envCredential{}, errors.New("no output")
+		}
+		return envCredential{}, err
+	}
+	if strings.TrimSpace(output.Auth) == "" {
+		return envCredential{}, errors.New("missing key `auth`")
+	}
+	return newEnvCredential(serverAddress, configEnvAuth{
+		Auth:      output.Auth,
+		ExpiresAt: output.ExpiresAt,
+	})
+}
@@ -0,0 +1,138 @@
+package configfile
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/cli/config/types"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+// setRefreshCommand replaces the refresh command with a function that returns
+// the given output, and returns a pointer to the number of times it ran.
+func setRefreshCommand(t *testing.T, output string, err error) *int {
+	t.Helper()
+	var calls int
+	orig := runRefreshCommand
+	runRefreshCommand = func(_ context.Context, serverAddress string, command []string) ([]byte, error) {
+		calls++
+		assert.Check(t, is.Equal(serverAddress, "ci.example.test"))
+		assert.Check(t, is.DeepEqual(command, []string{"get-token", "--audience", "registry"}))
+		return []byte(output), err
+	}
+	t.Cleanup(func() {
+		runRefreshCommand = orig
+		resetRefreshedCredentials()
+	})
+	return &calls
+}
+
+func resetRefreshedCredentials() {
+	refreshedCredentials.Lock()
+	refreshedCredentials.creds = make(map[string]envCredential)
+	refreshedCredentials.Unlock()
+}
+
+func TestEnvStoreRefresh(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	calls := setRefreshCommand(t, `{"auth":"Y2k6bmV3LXRva2Vu","expiresAt":"`+expiresAt.Format(time.RFC3339)+`"}`, nil)
+
+	tests := []struct {
+		doc           string
+		envConfig     string
+		expectedPass  string
+		expectedCalls int
+	}{
+		{
+			doc:           "no auth",
+			envConfig:     `{"auths":{"ci.example.test":{"refreshCommand":["get-token","--audience","registry"]}}}`,
+			expectedPass:  "new-token",
+			expectedCalls: 1,
+		},
+		{
+			doc:           "expired",
+			envConfig:     `{"auths":{"ci.example.test":{"auth":"Y2k6b2xkLXRva2Vu","expiresAt":"2020-01-01T00:00:00Z","refreshCommand":["get-token","--audience","registry"]}}}`,
+			expectedPass:  "new-token",
+			expectedCalls: 1,
+		},
+		{
+			doc:           "not expired",
+			envConfig:     `{"auths":{"ci.example.test":{"auth":"Y2k6b2xkLXRva2Vu","expiresAt":"2999-01-01T00:00:00Z","refreshCommand":["get-token","--audience","registry"]}}}`,
+			expectedPass:  "old-token",
+			expectedCalls: 0,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.doc, func(t *testing.T) {
+			*calls = 0
+			resetRefreshedCredentials()
+			t.Setenv(DockerEnvConfigKey, tc.envConfig)
+			configFile := New("filename")
+
+			// Credentials are only refreshed once.
+			for i := 0; i < 2; i++ {
+				authConfig, err := configFile.GetAuthConfig("ci.example.test")
+				assert.NilError(t, err)
+				assert.Check(t, is.DeepEqual(authConfig, types.AuthConfig{
+					Username:      "ci",
+					Password:      tc.expectedPass,
+					ServerAddress: "ci.example.test",
+				}))
+			}
+			assert.Check(t, is.Equal(*calls, tc.expectedCalls))
+
+			all, err := configFile.GetAllCredentials()
+			assert.NilError(t, err)
+			assert.Check(t, is.Equal(all["ci.example.test"].Password, tc.expectedPass))
+			assert.Check(t, is.Equal(*calls, tc.expectedCalls))
+		})
+	}
+}
+
+func TestEnvStoreRefreshError(t *testing.T) {
+	setRefreshCommand(t, "", errors.New("exit status 1"))
+	t.Setenv(DockerEnvConfigKey, `{"auths":{"ci.example.test":{"refreshCommand":["get-token","--audience","registry"]}}}`)
+
+	_, err := New("filename").GetCredentialsStore("ci.example.test").Get("ci.example.test")
+	assert.Check(t, is.Error(err, "failed to refresh credentials for ci.example.test from DOCKER_AUTH_CONFIG: exit status 1"))
+}
+
+func TestParseRefreshOutput(t *testing.T) {
+	_, err := parseRefreshOutput("ci.example.test", nil)
+	assert.Check(t, is.Error(err, "no output"))
+	_, err = parseRefreshOutput("ci.example.test", []byte(`{"expiresAt":"2999-01-01T00:00:00Z"}`))
+	assert.Check(t, is.Error(err, "missing key `auth`"))
+	_, err = parseRefreshOutput("ci.example.test", []byte(`{"auth":"Y2k6bmV3LXRva2Vu","token":"foo"}`))
+	assert.Check(t, is.ErrorContains(err, `unknown field "token"`))
+}
+
+func TestEnvConfigFile(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "auth.json")
+	t.Setenv(DockerEnvConfigFileKey, fileName)
+	t.Setenv(DockerEnvConfigKey, "")
+	configFile := New("filename")
+
+	assert.NilError(t, os.WriteFile(fileName, []byte(`{"auths":{"ci.example.test":{"auth":"Y2k6b2xkLXRva2Vu","expiresAt":"2999-01-01T00:00:00Z"}}}`), 0o600))
+	authConfig, err := configFile.GetAuthConfig("ci.example.test")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(authConfig.Password, "old-token"))
+
+	// The file is read again when credentials are looked up.
+	assert.NilError(t, os.WriteFile(fileName, []byte(`{"auths":{"ci.example.test":{"auth":"Y2k6bmV3LXRva2Vu"}}}`), 0o600))
+	authConfig, err = configFile.GetAuthConfig("ci.example.test")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(authConfig.Password, "new-token"))
+
+	// DOCKER_AUTH_CONFIG takes precedence.
+	t.Setenv(DockerEnvConfigKey, envTestAuthConfig)
+	_, err = configFile.GetAuthConfig("env.example.test")
+	assert.NilError(t, err)
+	authConfig, err = configFile.GetAuthConfig("ci.example.test")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(authConfig.Password, ""))
+}
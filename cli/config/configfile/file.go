@@ -9,6 +9,7 @@
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/docker/cli/cli/config/credentials"
 	"github.com/docker/cli/cli/config/memorystore"
@@ -43,10 +44,15 @@ type ConfigFile struct {
 	Plugins              map[string]map[string]string `json:"plugins,omitempty"`
 	Aliases              map[string]string            `json:"aliases,omitempty"`
 	Features             map[string]string            `json:"features,omitempty"`
+	ConnectionHelpers    map[string]string            `json:"connectionHelpers,omitempty"`
+	ContextCredsStore    string                       `json:"contextCredsStore,omitempty"`
+	OAuth                map[string]OAuthConfig       `json:"oauth,omitempty"`
 }
 
 type configEnvAuth struct {
-	Auth string `json:"auth"`
+	Auth           string    `json:"auth"`
+	ExpiresAt      time.Time `json:"expiresAt,omitempty"`
+	RefreshCommand []string  `json:"refreshCommand,omitempty"`
 }
 
 type configEnv struct {
@@ -57,6 +63,12 @@ type configEnv struct {
 // credential config. It only supports storing the credentials as a base64
 // encoded string in the format base64("username:pat").
 //
+// Short-lived credentials can set an "expiresAt" time, and a "refreshCommand"
+// to run to get new credentials when they expire. The "auth" field can be
+// omitted if a "refreshCommand" is set. The refresh command gets the address
+// of the registry in the DOCKER_AUTH_REGISTRY environment variable, and must
+// print a JSON object with the "auth" field, and optionally "expiresAt".
+//
 // Adding additional fields will produce a parsing error.
 //
 // Example:
@@ -65,11 +77,22 @@ type configEnv struct {
 //		"auths": {
 //			"example.test": {
 //				"auth": base64-encoded-username-pat
+//			},
+//			"ci.example.test": {
+//				"auth": base64-encoded-username-token,
+//				"expiresAt": "2025-06-01T12:00:00Z",
+//				"refreshCommand": ["/usr/local/bin/get-registry-token"]
 //			}
 //		}
 //	}
 const DockerEnvConfigKey = "DOCKER_AUTH_CONFIG"
 
+// DockerEnvConfigFileKey is an environment variable that contains the path of
+// a file with a credential config in the same format as [DockerEnvConfigKey].
+// It's ignored if [DockerEnvConfigKey] is set. The file is read each time
+// credentials are looked up, so that it can be updated with new credentials.
+const DockerEnvConfigFileKey = "DOCKER_AUTH_CONFIG_FILE"
+
 // ProxyConfig contains proxy configuration settings
 type ProxyConfig struct {
 	HTTPProxy  string `json:"httpProxy,omitempty"`
@@ -79,6 +102,20 @@ type ProxyConfig struct {
 	AllProxy   string `json:"allProxy,omitempty"`
 }
 
+// OAuthConfig contains the settings to log in to a registry with the OAuth
+// device authorization flow ("docker login --oauth"), and to refresh the
+// access token when it expires. Endpoints that are not set are discovered
+// from the metadata that's published by the Issuer.
+type OAuthConfig struct {
+	Issuer                      string   `json:"issuer,omitempty"`
+	DeviceAuthorizationEndpoint string   `json:"deviceAuthorizationEndpoint,omitempty"`
+	TokenEndpoint               string   `json:"tokenEndpoint,omitempty"`
+	RevocationEndpoint          string   `json:"revocationEndpoint,omitempty"`
+	ClientID                    string   `json:"clientId,omitempty"`
+	Audience                    string   `json:"audience,omitempty"`
+	Scopes                      []string `json:"scopes,omitempty"`
+}
+
 // New initializes an empty configuration file for the given filename 'fn'
 func New(fn string) *ConfigFile {
 	return &ConfigFile{
@@ -293,58 +330,87 @@ func (configFile *ConfigFile) GetCredentialsStore(registryHostname string) crede
 		store = newNativeStore(configFile, helper)
 	}
 
-	envConfig := os.Getenv(DockerEnvConfigKey)
+	envConfig, source, err := getEnvConfig()
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "Failed to create credential store from %s: %v\n", source, err)
+		return store
+	}
 	if envConfig == "" {
 		return store
 	}
 
-	authConfig, err := parseEnvConfig(envConfig)
+	authConfig, expiring, err := parseEnvConfig(envConfig, source)
 	if err != nil {
-		_, _ = fmt.Fprintln(os.Stderr, "Failed to create credential store from DOCKER_AUTH_CONFIG: ", err)
+		_, _ = fmt.Fprintf(os.Stderr, "Failed to create credential store from %s: %v\n", source, err)
 		return store
 	}
 
 	// use DOCKER_AUTH_CONFIG if set
 	// it uses the native or file store as a fallback to fetch and store credentials
-	envStore, err := memorystore.New(
+	memStore, err := memorystore.New(
 		memorystore.WithAuthConfig(authConfig),
 		memorystore.WithFallbackStore(store),
 	)
 	if err != nil {
-		_, _ = fmt.Fprintln(os.Stderr, "Failed to create credential store from DOCKER_AUTH_CONFIG: ", err)
+		_, _ = fmt.Fprintf(os.Stderr, "Failed to create credential store from %s: %v\n", source, err)
 		return store
 	}
+	if len(expiring) == 0 {
+		return memStore
+	}
+	return &envStore{fallback: memStore, expiring: expiring, source: source}
+}
 
-	return envStore
+// getEnvConfig returns the credential config from the DOCKER_AUTH_CONFIG
+// environment variable, or the file in DOCKER_AUTH_CONFIG_FILE, and the
+// name of the environment variable it was read from.
+func getEnvConfig() (envConfig, source string, _ error) {
+	if v := os.Getenv(DockerEnvConfigKey); v != "" {
+		return v, DockerEnvConfigKey, nil
+	}
+	fileName := os.Getenv(DockerEnvConfigFileKey)
+	if fileName == "" {
+		return "", "", nil
+	}
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return "", DockerEnvConfigFileKey, err
+	}
+	return string(data), DockerEnvConfigFileKey, nil
 }
 
-func parseEnvConfig(v string) (map[string]types.AuthConfig, error) {
+// parseEnvConfig parses the credential config from DOCKER_AUTH_CONFIG, or the
+// file in DOCKER_AUTH_CONFIG_FILE, as named by source. It returns the
+// credentials that don't expire, and the credentials that expire or are
+// provided by a refresh command separately.
+func parseEnvConfig(v, source string) (_ map[string]types.AuthConfig, expiring map[string]envCredential, _ error) {
 	envConfig := &configEnv{}
 	decoder := json.NewDecoder(strings.NewReader(v))
 	decoder.DisallowUnknownFields()
 	if err := decoder.Decode(envConfig); err != nil && !errors.Is(err, io.EOF) {
-		return nil, err
+		return nil, nil, err
 	}
 	if decoder.More() {
-		return nil, errors.New("DOCKER_AUTH_CONFIG does not support more than one JSON object")
+		return nil, nil, fmt.Errorf("%s does not support more than one JSON object", source)
 	}
 
 	authConfigs := make(map[string]types.AuthConfig)
+	expiring = make(map[string]envCredential)
 	for addr, envAuth := range envConfig.AuthConfigs {
-		if envAuth.Auth == "" {
-			return nil, fmt.Errorf("DOCKER_AUTH_CONFIG environment variable is missing key `auth` for %s", addr)
+		if envAuth.Auth == "" && len(envAuth.RefreshCommand) == 0 {
+			return nil, nil, fmt.Errorf("%s is missing key `auth` for %s", source, addr)
 		}
-		username, password, err := decodeAuth(envAuth.Auth)
+		cred, err := newEnvCredential(addr, envAuth)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
-		authConfigs[addr] = types.AuthConfig{
-			Username:      username,
-			Password:      password,
-			ServerAddress: addr,
+		if cred.expiresAt.IsZero() && len(cred.refreshCommand) == 0 {
+			authConfigs[addr] = cred.authConfig
+			continue
 		}
+		expiring[addr] = cred
 	}
-	return authConfigs, nil
+	return authConfigs, expiring, nil
 }
 
 // var for unit testing.
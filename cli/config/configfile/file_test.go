@@ -586,11 +586,11 @@ func TestGetAllCredentialsFromEnvironment(t *testing.T) {
 
 func TestParseEnvConfig(t *testing.T) {
 	t.Run("should error on unexpected fields", func(t *testing.T) {
-		_, err := parseEnvConfig(envTestUserPassConfig)
+		_, _, err := parseEnvConfig(envTestUserPassConfig, DockerEnvConfigKey)
 		assert.ErrorContains(t, err, "json: unknown field \"username\"")
 	})
 	t.Run("should be able to load env credentials", func(t *testing.T) {
-		got, err := parseEnvConfig(envTestAuthConfig)
+		got, _, err := parseEnvConfig(envTestAuthConfig, DockerEnvConfigKey)
 		assert.NilError(t, err)
 		expected := map[string]types.AuthConfig{
 			"env.example.test": {
@@ -603,8 +603,12 @@ func TestParseEnvConfig(t *testing.T) {
 		assert.Check(t, is.DeepEqual(got, expected))
 	})
 	t.Run("should not support multiple JSON objects", func(t *testing.T) {
-		_, err := parseEnvConfig(`{"auths":{"env.example.test":{"auth":"something"}}}{}`)
-		assert.ErrorContains(t, err, "does not support more than one JSON object")
+		_, _, err := parseEnvConfig(`{"auths":{"env.example.test":{"auth":"something"}}}{}`, DockerEnvConfigKey)
+		assert.Check(t, is.Error(err, "DOCKER_AUTH_CONFIG does not support more than one JSON object"))
+	})
+	t.Run("should name the source of the config in errors", func(t *testing.T) {
+		_, _, err := parseEnvConfig(`{"auths":{"env.example.test":{}}}`, DockerEnvConfigFileKey)
+		assert.Check(t, is.Error(err, "DOCKER_AUTH_CONFIG_FILE is missing key `auth` for env.example.test"))
 	})
 }
 
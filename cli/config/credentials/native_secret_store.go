@@ -0,0 +1,71 @@
+package credentials
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/docker/docker-credential-helpers/client"
+	"github.com/docker/docker-credential-helpers/credentials"
+)
+
+const (
+	// contextSecretPrefix is the prefix of the server URLs under which
+	// secrets of contexts are stored in a credentials helper.
+	contextSecretPrefix = "docker-context://"
+
+	// contextSecretUsername is the username under which secrets of contexts
+	// are stored in a credentials helper.
+	contextSecretUsername = "<docker-context>"
+)
+
+// NativeSecretStore stores secrets of contexts, such as TLS keys, using a
+// remote helper program, such as the native keychain. Secrets are stored
+// base64-encoded, under a server URL with the "docker-context://" prefix.
+type NativeSecretStore struct {
+	programFunc client.ProgramFunc
+}
+
+// NewNativeSecretStore creates a new secret store that uses a remote helper
+// program to manage secrets of contexts.
+func NewNativeSecretStore(helperSuffix string) *NativeSecretStore {
+	return &NativeSecretStore{
+		programFunc: client.NewShellProgramFunc(remoteCredentialsPrefix + helperSuffix),
+	}
+}
+
+// Get retrieves the secret for the given key from the native store.
+func (s *NativeSecretStore) Get(key string) ([]byte, error) {
+	creds, err := client.Get(s.programFunc, contextSecretPrefix+key)
+	if err != nil {
+		if credentials.IsErrCredentialsNotFound(err) {
+			return nil, fmt.Errorf("secret %s not found in credentials store", key)
+		}
+		return nil, err
+	}
+	if creds.Username != contextSecretUsername {
+		return nil, errors.New("invalid secret in credentials store: " + key)
+	}
+	return base64.StdEncoding.DecodeString(creds.Secret)
+}
+
+// Store saves the secret for the given key in the native store.
+func (s *NativeSecretStore) Store(key string, data []byte) error {
+	return client.Store(s.programFunc, &credentials.Credentials{
+		ServerURL: contextSecretPrefix + key,
+		Username:  contextSecretUsername,
+		Secret:    base64.StdEncoding.EncodeToString(data),
+	})
+}
+
+// Erase removes the secret for the given key from the native store.
+func (s *NativeSecretStore) Erase(key string) error {
+	return client.Erase(s.programFunc, contextSecretPrefix+key)
+}
+
+// isContextSecret returns whether the given server URL holds a secret of a
+// context, which is not a registry credential.
+func isContextSecret(serverURL string) bool {
+	return strings.HasPrefix(serverURL, contextSecretPrefix)
+}
@@ -0,0 +1,97 @@
+package credentials
+
+import (
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/docker/cli/cli/config/types"
+	"github.com/docker/docker-credential-helpers/client"
+	"github.com/docker/docker-credential-helpers/credentials"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+// memoryHelper simulates a credentials helper that keeps credentials in memory.
+type memoryHelper struct {
+	creds map[string]credentials.Credentials
+}
+
+type memoryHelperCommand struct {
+	helper *memoryHelper
+	arg    string
+	input  io.Reader
+}
+
+func (m *memoryHelperCommand) Output() ([]byte, error) {
+	in, err := io.ReadAll(m.input)
+	if err != nil {
+		return nil, err
+	}
+	switch m.arg {
+	case "store":
+		var c credentials.Credentials
+		if err := json.Unmarshal(in, &c); err != nil {
+			return []byte(err.Error()), errCommandExited
+		}
+		m.helper.creds[c.ServerURL] = c
+		return nil, nil
+	case "get":
+		c, ok := m.helper.creds[string(in)]
+		if !ok {
+			return []byte(credentials.NewErrCredentialsNotFound().Error()), errCommandExited
+		}
+		return json.Marshal(c)
+	case "erase":
+		delete(m.helper.creds, string(in))
+		return nil, nil
+	case "list":
+		list := make(map[string]string, len(m.helper.creds))
+		for serverURL, c := range m.helper.creds {
+			list[serverURL] = c.Username
+		}
+		return json.Marshal(list)
+	}
+	return []byte("unknown argument " + m.arg), errCommandExited
+}
+
+func (m *memoryHelperCommand) Input(in io.Reader) {
+	m.input = in
+}
+
+func (h *memoryHelper) programFunc(args ...string) client.Program {
+	return &memoryHelperCommand{helper: h, arg: args[0]}
+}
+
+func TestNativeSecretStore(t *testing.T) {
+	h := &memoryHelper{creds: map[string]credentials.Credentials{}}
+	s := &NativeSecretStore{programFunc: h.programFunc}
+
+	const key = "my-context/docker/key.pem"
+	assert.NilError(t, s.Store(key, []byte("private-key-data")))
+	assert.Check(t, is.Equal(h.creds["docker-context://"+key].Username, "<docker-context>"))
+
+	data, err := s.Get(key)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(data), "private-key-data"))
+
+	assert.NilError(t, s.Erase(key))
+	_, err = s.Get(key)
+	assert.Check(t, is.Error(err, "secret my-context/docker/key.pem not found in credentials store"))
+}
+
+func TestNativeStoreGetAllSkipsContextSecrets(t *testing.T) {
+	h := &memoryHelper{creds: map[string]credentials.Credentials{
+		validServerAddress: {ServerURL: validServerAddress, Username: "foo", Secret: "bar"},
+	}}
+	assert.NilError(t, (&NativeSecretStore{programFunc: h.programFunc}).Store("my-context/docker/key.pem", []byte("private-key-data")))
+
+	s := &nativeStore{
+		programFunc: h.programFunc,
+		fileStore:   NewFileStore(&fakeStore{configs: map[string]types.AuthConfig{}}),
+	}
+	all, err := s.GetAll()
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(all, 1))
+	assert.Check(t, is.Equal(all[validServerAddress].Username, "foo"))
+}
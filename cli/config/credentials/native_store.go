@@ -69,6 +69,11 @@ func (c *nativeStore) GetAll() (map[string]types.AuthConfig, error) {
 
 	authConfigs := make(map[string]types.AuthConfig)
 	for registry := range auths {
+		if isContextSecret(registry) {
+			// Secrets of contexts (see [NativeSecretStore]) are not
+			// registry credentials.
+			continue
+		}
 		creds, err := c.getCredentialsFromStore(registry)
 		if err != nil {
 			return nil, err
@@ -12,6 +12,16 @@
 	"github.com/docker/cli/cli/connhelper/ssh"
 )
 
+// SSH transports that can be used to connect to ssh:// hosts.
+const (
+	// SSHTransportSystem executes the "ssh" binary to connect to the remote
+	// host. It's the default.
+	SSHTransportSystem = "system"
+	// SSHTransportNative connects to the remote host with an in-process SSH
+	// client, which does not require the "ssh" binary to be installed.
+	SSHTransportNative = "native"
+)
+
 // ConnectionHelper allows to connect to a remote host with custom stream provider binary.
 type ConnectionHelper struct {
 	Dialer func(ctx context.Context, network, addr string) (net.Conn, error)
@@ -48,12 +58,7 @@ func getConnectionHelper(daemonURL string, sshFlags []string) (*ConnectionHelper
 		sshFlags = addSSHTimeout(sshFlags)
 		sshFlags = disablePseudoTerminalAllocation(sshFlags)
 
-		remoteCommand := []string{"docker", "system", "dial-stdio"}
-		socketPath := sp.Path
-		if strings.Trim(sp.Path, "/") != "" {
-			remoteCommand = []string{"docker", "--host=unix://" + socketPath, "system", "dial-stdio"}
-		}
-		sshArgs, err := sp.Command(sshFlags, remoteCommand...)
+		sshArgs, err := sp.Command(sshFlags, dialStdioCommand(sp)...)
 		if err != nil {
 			return nil, err
 		}
@@ -64,11 +69,42 @@ func getConnectionHelper(daemonURL string, sshFlags []string) (*ConnectionHelper
 			Host: "http://docker.example.com",
 		}, nil
 	}
-	// Future version may support plugins via ~/.docker/config.json. e.g. "dind"
-	// See docker/cli#889 for the previous discussion.
+	// Other schemes may be handled by commands configured in the
+	// "connectionHelpers" in ~/.docker/config.json, or by CLI plugins.
+	// See GetCommandConnectionHelper.
 	return nil, err
 }
 
+// GetNativeSSHConnectionHelper returns Docker-specific connection helper for
+// the given ssh:// URL, which connects to the remote host with an in-process
+// SSH client instead of executing the "ssh" binary. Refer to [ssh.NativeDialer]
+// for the ssh options that are supported.
+//
+// Requires Docker 18.09 or later on the remote host.
+func GetNativeSSHConnectionHelper(daemonURL string) (*ConnectionHelper, error) {
+	sp, err := ssh.ParseURL(daemonURL)
+	if err != nil {
+		return nil, fmt.Errorf("ssh host connection is not valid: %w", err)
+	}
+	dialer, err := ssh.NewNativeDialer(sp, dialStdioCommand(sp)...)
+	if err != nil {
+		return nil, err
+	}
+	return &ConnectionHelper{
+		Dialer: dialer.DialContext,
+		Host:   "http://docker.example.com",
+	}, nil
+}
+
+// dialStdioCommand returns the command to execute on the remote host to
+// connect to the daemon.
+func dialStdioCommand(sp *ssh.Spec) []string {
+	if strings.Trim(sp.Path, "/") != "" {
+		return []string{"docker", "--host=unix://" + sp.Path, "system", "dial-stdio"}
+	}
+	return []string{"docker", "system", "dial-stdio"}
+}
+
 // GetCommandConnectionHelper returns Docker-specific connection helper constructed from an arbitrary command.
 func GetCommandConnectionHelper(cmd string, flags ...string) (*ConnectionHelper, error) {
 	return &ConnectionHelper{
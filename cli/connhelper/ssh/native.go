@@ -0,0 +1,523 @@
+package ssh
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kevinburke/ssh_config"
+	"github.com/sirupsen/logrus"
+	gossh "golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// nativeConnectTimeout is the timeout for connecting to the remote host and
+// completing the SSH handshake. It's the equivalent of the "ConnectTimeout"
+// option that is set when executing the ssh binary.
+const nativeConnectTimeout = 30 * time.Second
+
+// systemConfigFile is the system-wide ssh configuration file, which is used
+// for options that are not set in the user's configuration file.
+var systemConfigFile = filepath.Join("/", "etc", "ssh", "ssh_config")
+
+// NativeDialer connects to the remote host of a [Spec] with an in-process SSH
+// client, as an alternative to executing the "ssh" binary. It uses the user's
+// ssh configuration ("~/.ssh/config" and "/etc/ssh/ssh_config"), verifies the
+// host key against the known_hosts files, and authenticates using ssh-agent
+// and the identity files of the user.
+//
+// The SSH connection is shared by the connections returned by DialContext,
+// each of which executes the remote command in a new session.
+type NativeDialer struct {
+	spec          Spec
+	remoteCommand string
+
+	mu     sync.Mutex
+	client *gossh.Client
+	// agentConn is the connection to ssh-agent that is used to authenticate
+	// client; it's closed when client is closed.
+	agentConn net.Conn
+}
+
+// NewNativeDialer returns a [NativeDialer] which executes the given remote
+// command on the host of the given [Spec] for each connection. Where needed,
+// it quotes values passed in remoteCommandAndArgs to account for the remote
+// command being executed in a shell.
+func NewNativeDialer(sp *Spec, remoteCommandAndArgs ...string) (*NativeDialer, error) {
+	if sp == nil || sp.Host == "" {
+		return nil, errors.New("no host specified")
+	}
+	if len(remoteCommandAndArgs) == 0 {
+		return nil, errors.New("no remote command specified")
+	}
+	remoteCommand, err := quoteCommand(remoteCommandAndArgs...)
+	if err != nil {
+		return nil, err
+	}
+	return &NativeDialer{spec: *sp, remoteCommand: remoteCommand}, nil
+}
+
+// DialContext executes the remote command in a new session, and returns a
+// connection to its stdin and stdout. The network and address are ignored.
+func (d *NativeDialer) DialContext(ctx context.Context, _, _ string) (net.Conn, error) {
+	client, err := d.getClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	session, err := client.NewSession()
+	if err != nil {
+		// The connection may have been closed by the remote host after
+		// it was idle; reconnect once before giving up.
+		d.resetClient(client)
+		if client, err = d.getClient(ctx); err != nil {
+			return nil, err
+		}
+		if session, err = client.NewSession(); err != nil {
+			return nil, err
+		}
+	}
+	return newSessionConn(session, d.remoteCommand)
+}
+
+// Close closes the SSH connection. Connections returned by DialContext
+// are closed as well.
+func (d *NativeDialer) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.closeAgent()
+	if d.client == nil {
+		return nil
+	}
+	err := d.client.Close()
+	d.client = nil
+	return err
+}
+
+func (d *NativeDialer) getClient(ctx context.Context) (*gossh.Client, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.client != nil {
+		return d.client, nil
+	}
+
+	addr, cfg, err := d.clientConfig()
+	if err != nil {
+		return nil, err
+	}
+	logrus.Debugf("ssh: connecting to %s as %s", addr, cfg.User)
+
+	ctx, cancel := context.WithTimeout(ctx, nativeConnectTimeout)
+	defer cancel()
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	if err != nil {
+		d.closeAgent()
+		return nil, fmt.Errorf("ssh: failed to connect to %s: %w", addr, err)
+	}
+	// NewClientConn does not accept a context; use a deadline instead.
+	deadline, _ := ctx.Deadline()
+	_ = conn.SetDeadline(deadline)
+	c, chans, reqs, err := gossh.NewClientConn(conn, addr, cfg)
+	if err != nil {
+		_ = conn.Close()
+		d.closeAgent()
+		return nil, fmt.Errorf("ssh: failed to connect to %s: %w", addr, err)
+	}
+	_ = conn.SetDeadline(time.Time{})
+
+	client := gossh.NewClient(c, chans, reqs)
+	go func() {
+		_ = client.Wait()
+		d.resetClient(client)
+	}()
+	d.client = client
+	return client, nil
+}
+
+// resetClient discards the given client if it's the current client, so that
+// a new connection is made on the next call to getClient.
+func (d *NativeDialer) resetClient(client *gossh.Client) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.client == client {
+		_ = d.client.Close()
+		d.client = nil
+		d.closeAgent()
+	}
+}
+
+// closeAgent closes the connection to ssh-agent, if any. It must be called
+// with d.mu held.
+func (d *NativeDialer) closeAgent() {
+	if d.agentConn != nil {
+		_ = d.agentConn.Close()
+		d.agentConn = nil
+	}
+}
+
+// clientConfig returns the address of the remote host and the client config,
+// using the options of the user's ssh configuration for the host.
+func (d *NativeDialer) clientConfig() (string, *gossh.ClientConfig, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", nil, fmt.Errorf("ssh: failed to get home directory: %w", err)
+	}
+	sshCfg, err := loadSSHConfig(home)
+	if err != nil {
+		return "", nil, err
+	}
+	alias := d.spec.Host
+
+	for _, opt := range []string{"ProxyCommand", "ProxyJump"} {
+		if v := sshCfg.get(alias, opt); v != "" && !strings.EqualFold(v, "none") {
+			return "", nil, fmt.Errorf("ssh: %s is not supported by the native SSH transport", opt)
+		}
+	}
+
+	hostname := alias
+	if v := sshCfg.get(alias, "HostName"); v != "" {
+		hostname = strings.ReplaceAll(v, "%h", alias)
+	}
+	port := d.spec.Port
+	if port == "" {
+		port = sshCfg.get(alias, "Port")
+	}
+	addr := net.JoinHostPort(hostname, port)
+
+	username := d.spec.User
+	if username == "" {
+		username = sshCfg.get(alias, "User")
+	}
+	if username == "" {
+		u, err := user.Current()
+		if err != nil {
+			return "", nil, fmt.Errorf("ssh: failed to get current user: %w", err)
+		}
+		// On Windows, the username includes the domain.
+		_, username, _ = strings.Cut(u.Username, `\`)
+		if username == "" {
+			username = u.Username
+		}
+	}
+
+	var knownHostsFiles []string
+	for _, v := range sshCfg.getAll(alias, "UserKnownHostsFile") {
+		knownHostsFiles = append(knownHostsFiles, strings.Fields(v)...)
+	}
+	if len(knownHostsFiles) == 0 {
+		knownHostsFiles = []string{"~/.ssh/known_hosts", "~/.ssh/known_hosts2"}
+	}
+	knownHostsFiles = append(knownHostsFiles, strings.Fields(sshCfg.get(alias, "GlobalKnownHostsFile"))...)
+	hostKeyCallback, err := newHostKeyCallback(expandPaths(home, knownHostsFiles))
+	if err != nil {
+		return "", nil, err
+	}
+
+	identityFiles := sshCfg.getAll(alias, "IdentityFile")
+	if len(identityFiles) == 0 {
+		identityFiles = []string{"~/.ssh/id_ed25519", "~/.ssh/id_ecdsa", "~/.ssh/id_rsa"}
+	}
+
+	return addr, &gossh.ClientConfig{
+		User:              username,
+		Auth:              d.authMethods(expandPaths(home, identityFiles)),
+		HostKeyCallback:   hostKeyCallback,
+		HostKeyAlgorithms: knownHostKeyAlgorithms(hostKeyCallback, addr),
+		Timeout:           nativeConnectTimeout,
+	}, nil
+}
+
+// sshConfig is the user's ssh configuration, followed by the system-wide
+// ssh configuration. Options are taken from the first configuration that
+// sets them, in the same way as the ssh binary.
+type sshConfig []*ssh_config.Config
+
+func loadSSHConfig(home string) (sshConfig, error) {
+	var cfg sshConfig
+	for _, fileName := range []string{filepath.Join(home, ".ssh", "config"), systemConfigFile} {
+		f, err := os.Open(fileName)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return nil, fmt.Errorf("ssh: failed to read config: %w", err)
+		}
+		c, err := ssh_config.Decode(f)
+		_ = f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("ssh: failed to parse %s: %w", fileName, err)
+		}
+		cfg = append(cfg, c)
+	}
+	return cfg, nil
+}
+
+func (cfg sshConfig) get(alias, key string) string {
+	for _, c := range cfg {
+		if v, _ := c.Get(alias, key); v != "" {
+			return v
+		}
+	}
+	return ssh_config.Default(key)
+}
+
+func (cfg sshConfig) getAll(alias, key string) []string {
+	for _, c := range cfg {
+		if v, _ := c.GetAll(alias, key); len(v) > 0 {
+			return v
+		}
+	}
+	return nil
+}
+
+// expandPaths expands "~" at the start of the given paths to the home
+// directory.
+func expandPaths(home string, paths []string) []string {
+	expanded := make([]string, 0, len(paths))
+	for _, p := range paths {
+		if p == "~" || strings.HasPrefix(p, "~/") {
+			p = filepath.Join(home, p[1:])
+		}
+		expanded = append(expanded, p)
+	}
+	return expanded
+}
+
+// newHostKeyCallback returns a callback which verifies host keys against the
+// given known_hosts files. Files that do not exist are ignored.
+func newHostKeyCallback(knownHostsFiles []string) (gossh.HostKeyCallback, error) {
+	var files []string
+	for _, f := range knownHostsFiles {
+		if _, err := os.Stat(f); err == nil {
+			files = append(files, f)
+		}
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("ssh: no known_hosts file found (looked for %s)", strings.Join(knownHostsFiles, ", "))
+	}
+	callback, err := knownhosts.New(files...)
+	if err != nil {
+		return nil, fmt.Errorf("ssh: failed to read known_hosts: %w", err)
+	}
+	return func(hostname string, remote net.Addr, key gossh.PublicKey) error {
+		err := callback(hostname, remote, key)
+		var keyErr *knownhosts.KeyError
+		if errors.As(err, &keyErr) && len(keyErr.Want) == 0 {
+			return fmt.Errorf("ssh: host %s is not in the known_hosts files; connect to the host with ssh once to add its host key: %w", hostname, err)
+		}
+		return err
+	}, nil
+}
+
+// knownHostKeyAlgorithms returns the host key algorithms of the keys in the
+// known_hosts files for the given address, so that the server doesn't pick
+// another type of key than the one that is known. It returns nil if there
+// are no keys for the address.
+func knownHostKeyAlgorithms(hostKeyCallback gossh.HostKeyCallback, addr string) []string {
+	// Verify a key that is never known for the host, to get the known keys.
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil
+	}
+	signer, err := gossh.NewSignerFromKey(priv)
+	if err != nil {
+		return nil
+	}
+	err = hostKeyCallback(knownhosts.Normalize(addr), &net.TCPAddr{IP: net.IPv4zero, Port: 22}, signer.PublicKey())
+	var keyErr *knownhosts.KeyError
+	if !errors.As(err, &keyErr) {
+		return nil
+	}
+	var algos []string
+	for _, k := range keyErr.Want {
+		switch keyType := k.Key.Type(); keyType {
+		case gossh.KeyAlgoRSA:
+			algos = append(algos, gossh.KeyAlgoRSASHA512, gossh.KeyAlgoRSASHA256, gossh.KeyAlgoRSA)
+		default:
+			algos = append(algos, keyType)
+		}
+	}
+	return algos
+}
+
+// authMethods returns the public key authentication methods for the keys in
+// ssh-agent, if available, and the given identity files. Identity files that
+// do not exist, or that are protected by a passphrase, are ignored. The
+// connection to ssh-agent is kept until the SSH connection is closed. It must
+// be called with d.mu held.
+func (d *NativeDialer) authMethods(identityFiles []string) []gossh.AuthMethod {
+	var methods []gossh.AuthMethod
+	if sock := os.Getenv("SSH_AUTH_SOCK"); sock != "" {
+		d.closeAgent()
+		if conn, err := net.Dial("unix", sock); err != nil {
+			logrus.WithError(err).Debug("ssh: failed to connect to ssh-agent")
+		} else {
+			d.agentConn = conn
+			methods = append(methods, gossh.PublicKeysCallback(agent.NewClient(conn).Signers))
+		}
+	}
+
+	var signers []gossh.Signer
+	for _, f := range identityFiles {
+		key, err := os.ReadFile(f)
+		if err != nil {
+			continue
+		}
+		signer, err := gossh.ParsePrivateKey(key)
+		if err != nil {
+			logrus.WithError(err).Debugf("ssh: ignoring identity file %s", f)
+			continue
+		}
+		signers = append(signers, signer)
+	}
+	if len(signers) > 0 {
+		methods = append(methods, gossh.PublicKeys(signers...))
+	}
+	return methods
+}
+
+// sessionConn implements net.Conn for the stdin and stdout of a command
+// executed in an SSH session.
+type sessionConn struct {
+	session *gossh.Session
+	stdin   io.WriteCloser
+	stdout  io.Reader
+	stderr  limitedBuffer
+	command string
+
+	waitOnce sync.Once
+	waitErr  error
+}
+
+func newSessionConn(session *gossh.Session, remoteCommand string) (net.Conn, error) {
+	c := &sessionConn{session: session, command: remoteCommand}
+	var err error
+	if c.stdin, err = session.StdinPipe(); err != nil {
+		_ = session.Close()
+		return nil, err
+	}
+	if c.stdout, err = session.StdoutPipe(); err != nil {
+		_ = session.Close()
+		return nil, err
+	}
+	session.Stderr = &c.stderr
+	logrus.Debugf("ssh: executing %s", remoteCommand)
+	if err := session.Start(remoteCommand); err != nil {
+		_ = session.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// wait waits for the remote command to exit after its stdout was closed,
+// and returns an error if it didn't exit successfully.
+func (c *sessionConn) wait() error {
+	c.waitOnce.Do(func() {
+		werrCh := make(chan error, 1)
+		go func() { werrCh <- c.session.Wait() }()
+		select {
+		case c.waitErr = <-werrCh:
+		case <-time.After(10 * time.Second):
+			c.waitErr = errors.New("did not exit")
+		}
+	})
+	return c.waitErr
+}
+
+func (c *sessionConn) Read(p []byte) (int, error) {
+	n, err := c.stdout.Read(p)
+	if errors.Is(err, io.EOF) {
+		if werr := c.wait(); werr != nil {
+			return n, fmt.Errorf("remote command %q has exited with %v, make sure Docker 18.09 or later is installed on the remote host: stderr=%s", c.command, werr, c.stderr.String())
+		}
+	}
+	return n, err
+}
+
+func (c *sessionConn) Write(p []byte) (int, error) {
+	return c.stdin.Write(p)
+}
+
+// CloseRead allows sessionConn to implement halfCloser.
+func (*sessionConn) CloseRead() error {
+	return nil
+}
+
+// CloseWrite allows sessionConn to implement halfCloser.
+func (c *sessionConn) CloseWrite() error {
+	return c.stdin.Close()
+}
+
+func (c *sessionConn) Close() error {
+	if err := c.session.Close(); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
+
+func (*sessionConn) LocalAddr() net.Addr {
+	return sessionAddr("local")
+}
+
+func (*sessionConn) RemoteAddr() net.Addr {
+	return sessionAddr("remote")
+}
+
+func (*sessionConn) SetDeadline(t time.Time) error {
+	logrus.Debugf("unimplemented call: SetDeadline(%v)", t)
+	return nil
+}
+
+func (*sessionConn) SetReadDeadline(t time.Time) error {
+	logrus.Debugf("unimplemented call: SetReadDeadline(%v)", t)
+	return nil
+}
+
+func (*sessionConn) SetWriteDeadline(t time.Time) error {
+	logrus.Debugf("unimplemented call: SetWriteDeadline(%v)", t)
+	return nil
+}
+
+type sessionAddr string
+
+func (sessionAddr) Network() string {
+	return "ssh"
+}
+
+func (a sessionAddr) String() string {
+	return string(a)
+}
+
+// limitedBuffer keeps the last output of the remote command's stderr, to be
+// included in errors.
+type limitedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	logrus.Debugf("ssh (stderr): %s", string(p))
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.buf.Len() > 4096 {
+		b.buf.Reset()
+	}
+	return b.buf.Write(p)
+}
+
+func (b *limitedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
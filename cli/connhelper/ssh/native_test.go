@@ -0,0 +1,303 @@
+package ssh
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/pem"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	gossh "golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/poll"
+)
+
+// testServer is an in-process SSH server, which executes "docker system
+// dial-stdio" by echoing stdin to stdout.
+type testServer struct {
+	addr     string
+	hostKey  gossh.Signer
+	conns    atomic.Int32
+	mu       sync.Mutex
+	commands []string
+}
+
+func newTestServer(t *testing.T, authorizedKey gossh.PublicKey) *testServer {
+	t.Helper()
+	s := &testServer{hostKey: newTestSigner(t)}
+	cfg := &gossh.ServerConfig{
+		PublicKeyCallback: func(conn gossh.ConnMetadata, key gossh.PublicKey) (*gossh.Permissions, error) {
+			if conn.User() == "docker-user" && string(key.Marshal()) == string(authorizedKey.Marshal()) {
+				return nil, nil
+			}
+			return nil, io.ErrUnexpectedEOF
+		},
+	}
+	cfg.AddHostKey(s.hostKey)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NilError(t, err)
+	t.Cleanup(func() { _ = l.Close() })
+	s.addr = l.Addr().String()
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			s.conns.Add(1)
+			go s.serveConn(conn, cfg)
+		}
+	}()
+	return s
+}
+
+func (s *testServer) serveConn(conn net.Conn, cfg *gossh.ServerConfig) {
+	defer conn.Close()
+	_, chans, reqs, err := gossh.NewServerConn(conn, cfg)
+	if err != nil {
+		return
+	}
+	go gossh.DiscardRequests(reqs)
+	for newChannel := range chans {
+		if newChannel.ChannelType() != "session" {
+			_ = newChannel.Reject(gossh.UnknownChannelType, "unknown channel type")
+			continue
+		}
+		ch, requests, err := newChannel.Accept()
+		if err != nil {
+			continue
+		}
+		go s.serveSession(ch, requests)
+	}
+}
+
+func (s *testServer) serveSession(ch gossh.Channel, requests <-chan *gossh.Request) {
+	defer ch.Close()
+	for req := range requests {
+		if req.Type != "exec" || len(req.Payload) < 4 {
+			_ = req.Reply(false, nil)
+			continue
+		}
+		command := string(req.Payload[4:])
+		s.mu.Lock()
+		s.commands = append(s.commands, command)
+		s.mu.Unlock()
+		_ = req.Reply(true, nil)
+
+		exitStatus := uint32(0)
+		if command == "docker system dial-stdio" {
+			_, _ = io.Copy(ch, ch)
+		} else {
+			_, _ = io.WriteString(ch.Stderr(), "docker: command not found\n")
+			exitStatus = 127
+		}
+		_, _ = ch.SendRequest("exit-status", false, binary.BigEndian.AppendUint32(nil, exitStatus))
+		return
+	}
+}
+
+func newTestKey(t *testing.T) (ed25519.PrivateKey, gossh.Signer) {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	assert.NilError(t, err)
+	signer, err := gossh.NewSignerFromKey(priv)
+	assert.NilError(t, err)
+	return priv, signer
+}
+
+func newTestSigner(t *testing.T) gossh.Signer {
+	t.Helper()
+	_, signer := newTestKey(t)
+	return signer
+}
+
+// setupHome creates a home directory with the given identity file, an ssh
+// config for the "docker-test" host, and a known_hosts file with the given
+// host key.
+func setupHome(t *testing.T, addr string, knownHostKey gossh.PublicKey, identity ed25519.PrivateKey) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("SSH_AUTH_SOCK", "")
+	orig := systemConfigFile
+	systemConfigFile = filepath.Join(home, "no-such-file")
+	t.Cleanup(func() { systemConfigFile = orig })
+
+	sshDir := filepath.Join(home, ".ssh")
+	assert.NilError(t, os.Mkdir(sshDir, 0o700))
+
+	block, err := gossh.MarshalPrivateKey(identity, "")
+	assert.NilError(t, err)
+	assert.NilError(t, os.WriteFile(filepath.Join(sshDir, "test_key"), pem.EncodeToMemory(block), 0o600))
+
+	host, port, err := net.SplitHostPort(addr)
+	assert.NilError(t, err)
+	sshConfig := strings.Join([]string{
+		"Host docker-test",
+		"  HostName " + host,
+		"  Port " + port,
+		"  User docker-user",
+		"  IdentityFile ~/.ssh/test_key",
+		"",
+	}, "\n")
+	assert.NilError(t, os.WriteFile(filepath.Join(sshDir, "config"), []byte(sshConfig), 0o600))
+
+	if knownHostKey != nil {
+		line := knownhosts.Line([]string{knownhosts.Normalize(addr)}, knownHostKey)
+		assert.NilError(t, os.WriteFile(filepath.Join(sshDir, "known_hosts"), []byte(line+"\n"), 0o600))
+	}
+}
+
+func TestNativeDialer(t *testing.T) {
+	clientKey, clientSigner := newTestKey(t)
+	server := newTestServer(t, clientSigner.PublicKey())
+	setupHome(t, server.addr, server.hostKey.PublicKey(), clientKey)
+
+	dialer, err := NewNativeDialer(&Spec{Host: "docker-test"}, "docker", "system", "dial-stdio")
+	assert.NilError(t, err)
+	defer dialer.Close()
+
+	for _, msg := range []string{"hello", "world"} {
+		conn, err := dialer.DialContext(context.Background(), "tcp", "")
+		assert.NilError(t, err)
+		_, err = conn.Write([]byte(msg))
+		assert.NilError(t, err)
+		assert.NilError(t, conn.(interface{ CloseWrite() error }).CloseWrite())
+		out, err := io.ReadAll(conn)
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(string(out), msg))
+		assert.Check(t, conn.Close())
+	}
+
+	assert.Check(t, is.Equal(server.conns.Load(), int32(1)), "expected the SSH connection to be reused")
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	assert.Check(t, is.DeepEqual(server.commands, []string{"docker system dial-stdio", "docker system dial-stdio"}))
+}
+
+func TestNativeDialerAgent(t *testing.T) {
+	clientKey, clientSigner := newTestKey(t)
+	server := newTestServer(t, clientSigner.PublicKey())
+	// The identity file is not authorized; only the key in ssh-agent is.
+	otherKey, _ := newTestKey(t)
+	setupHome(t, server.addr, server.hostKey.PublicKey(), otherKey)
+
+	keyring := agent.NewKeyring()
+	assert.NilError(t, keyring.Add(agent.AddedKey{PrivateKey: clientKey}))
+	sock := filepath.Join(t.TempDir(), "agent.sock")
+	l, err := net.Listen("unix", sock)
+	assert.NilError(t, err)
+	t.Cleanup(func() { _ = l.Close() })
+	t.Setenv("SSH_AUTH_SOCK", sock) // must be set after setupHome, which unsets it
+
+	var open atomic.Int32
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			open.Add(1)
+			go func() {
+				defer open.Add(-1)
+				_ = agent.ServeAgent(keyring, conn)
+			}()
+		}
+	}()
+
+	dialer, err := NewNativeDialer(&Spec{Host: "docker-test"}, "docker", "system", "dial-stdio")
+	assert.NilError(t, err)
+	for i := 0; i < 2; i++ {
+		conn, err := dialer.DialContext(context.Background(), "tcp", "")
+		assert.NilError(t, err)
+		assert.Check(t, conn.Close())
+	}
+	assert.Check(t, is.Equal(open.Load(), int32(1)))
+
+	assert.NilError(t, dialer.Close())
+	poll.WaitOn(t, func(poll.LogT) poll.Result {
+		if n := open.Load(); n != 0 {
+			return poll.Continue("%d connection(s) to ssh-agent still open", n)
+		}
+		return poll.Success()
+	})
+}
+
+func TestNativeDialerRemoteCommandFails(t *testing.T) {
+	clientKey, clientSigner := newTestKey(t)
+	server := newTestServer(t, clientSigner.PublicKey())
+	setupHome(t, server.addr, server.hostKey.PublicKey(), clientKey)
+
+	dialer, err := NewNativeDialer(&Spec{Host: "docker-test"}, "no-such-command")
+	assert.NilError(t, err)
+	defer dialer.Close()
+
+	conn, err := dialer.DialContext(context.Background(), "tcp", "")
+	assert.NilError(t, err)
+	defer conn.Close()
+	_, err = io.ReadAll(conn)
+	assert.Check(t, is.ErrorContains(err, `remote command "no-such-command" has exited with Process exited with status 127`))
+	assert.Check(t, is.ErrorContains(err, "docker: command not found"))
+}
+
+func TestNativeDialerHostKey(t *testing.T) {
+	tests := []struct {
+		doc          string
+		knownHostKey gossh.PublicKey
+		expectedErr  string
+	}{
+		{
+			doc:         "unknown host",
+			expectedErr: "no known_hosts file found",
+		},
+		{
+			doc:          "mismatched host key",
+			knownHostKey: newTestSigner(t).PublicKey(),
+			expectedErr:  "knownhosts: key mismatch",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.doc, func(t *testing.T) {
+			clientKey, clientSigner := newTestKey(t)
+			server := newTestServer(t, clientSigner.PublicKey())
+			setupHome(t, server.addr, tc.knownHostKey, clientKey)
+
+			dialer, err := NewNativeDialer(&Spec{Host: "docker-test"}, "docker", "system", "dial-stdio")
+			assert.NilError(t, err)
+			defer dialer.Close()
+
+			_, err = dialer.DialContext(context.Background(), "tcp", "")
+			assert.Check(t, is.ErrorContains(err, tc.expectedErr))
+		})
+	}
+}
+
+func TestNativeDialerProxyNotSupported(t *testing.T) {
+	clientKey, clientSigner := newTestKey(t)
+	server := newTestServer(t, clientSigner.PublicKey())
+	setupHome(t, server.addr, server.hostKey.PublicKey(), clientKey)
+	f, err := os.OpenFile(filepath.Join(os.Getenv("HOME"), ".ssh", "config"), os.O_APPEND|os.O_WRONLY, 0)
+	assert.NilError(t, err)
+	_, err = f.WriteString("Host proxied\n  ProxyJump docker-test\n")
+	assert.NilError(t, err)
+	assert.NilError(t, f.Close())
+
+	dialer, err := NewNativeDialer(&Spec{Host: "proxied"}, "docker", "system", "dial-stdio")
+	assert.NilError(t, err)
+	_, err = dialer.DialContext(context.Background(), "tcp", "")
+	assert.Check(t, is.Error(err, "ssh: ProxyJump is not supported by the native SSH transport"))
+}
@@ -87,7 +87,7 @@ func (ep *Endpoint) tlsConfig() (*tls.Config, error) {
 func (ep *Endpoint) ClientOpts() ([]client.Opt, error) {
 	var result []client.Opt
 	if ep.Host != "" {
-		helper, err := connhelper.GetConnectionHelper(ep.Host)
+		helper, err := ep.connectionHelper()
 		if err != nil {
 			return nil, err
 		}
@@ -138,6 +138,22 @@ func (ep *Endpoint) ClientOpts() ([]client.Opt, error) {
 	return result, nil
 }
 
+// connectionHelper returns the connection helper for the endpoint's host, or
+// nil if the host does not require a connection helper.
+func (ep *Endpoint) connectionHelper() (*connhelper.ConnectionHelper, error) {
+	switch ep.SSHTransport {
+	case "", connhelper.SSHTransportSystem:
+		return connhelper.GetConnectionHelper(ep.Host)
+	case connhelper.SSHTransportNative:
+		if strings.HasPrefix(ep.Host, "ssh://") {
+			return connhelper.GetNativeSSHConnectionHelper(ep.Host)
+		}
+		return connhelper.GetConnectionHelper(ep.Host)
+	default:
+		return nil, fmt.Errorf("invalid SSH transport %q: must be one of %s, %s", ep.SSHTransport, connhelper.SSHTransportSystem, connhelper.SSHTransportNative)
+	}
+}
+
 // isSocket checks if the given address is a Unix-socket (linux),
 // named pipe (Windows), or file-descriptor.
 func isSocket(addr string) bool {
@@ -4,4 +4,9 @@
 type EndpointMetaBase struct {
 	Host          string `json:",omitempty"`
 	SkipTLSVerify bool
+
+	// SSHTransport is the transport used to connect to ssh:// hosts; either
+	// "system" (the default) to execute the "ssh" binary, or "native" to use
+	// an in-process SSH client.
+	SSHTransport string `json:",omitempty"`
 }
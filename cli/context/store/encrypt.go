@@ -0,0 +1,76 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.23
+
+package store
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+)
+
+// ageHeader is the first line of a (binary) age-encrypted file.
+const ageHeader = "age-encryption.org/"
+
+// ExportEncrypted exports an existing context like [Export], and encrypts
+// the exported data with age (https://age-encryption.org) for the given
+// recipients. The encrypted data is ASCII-armored, so that it can be shared
+// as text. Use [ImportEncrypted] to import it.
+func ExportEncrypted(name string, s Reader, recipients ...age.Recipient) io.ReadCloser {
+	reader, writer := io.Pipe()
+	go func() {
+		exported := Export(name, s)
+		defer exported.Close()
+
+		aw := armor.NewWriter(writer)
+		w, err := age.Encrypt(aw, recipients...)
+		if err != nil {
+			writer.CloseWithError(err)
+			return
+		}
+		if _, err := io.Copy(w, exported); err != nil {
+			writer.CloseWithError(err)
+			return
+		}
+		if err := w.Close(); err != nil {
+			writer.CloseWithError(err)
+			return
+		}
+		writer.CloseWithError(aw.Close())
+	}()
+	return reader
+}
+
+// IsEncrypted returns whether the exported context data in r is encrypted
+// with age, either binary or ASCII-armored. It does not advance the reader.
+func IsEncrypted(r *bufio.Reader) (bool, error) {
+	head, err := r.Peek(512)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, err
+	}
+	head = bytes.TrimLeft(head, " \t\r\n")
+	return bytes.HasPrefix(head, []byte(ageHeader)) || bytes.HasPrefix(head, []byte(armor.Header)), nil
+}
+
+// ImportEncrypted decrypts exported context data that was encrypted with
+// age, using the given identities, and imports it into a store.
+func ImportEncrypted(name string, s Writer, reader io.Reader, identities ...age.Identity) error {
+	r := bufio.NewReader(reader)
+	head, err := r.Peek(len(ageHeader))
+	if err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	var src io.Reader = r
+	if string(head) != ageHeader {
+		src = armor.NewReader(r)
+	}
+	decrypted, err := age.Decrypt(src, identities...)
+	if err != nil {
+		return invalidParameter(err)
+	}
+	return Import(name, s, decrypted)
+}
@@ -0,0 +1,110 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.23
+
+package store
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+	"github.com/containerd/errdefs"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func newEncryptTestStore(t *testing.T) Store {
+	t.Helper()
+	s := New(t.TempDir(), testCfg)
+	assert.NilError(t, s.CreateOrUpdate(Metadata{
+		Endpoints: map[string]any{
+			"ep1": endpoint{Foo: "bar"},
+		},
+		Metadata: context{Bar: "baz"},
+		Name:     "source",
+	}))
+	assert.NilError(t, s.ResetEndpointTLSMaterial("source", "ep1", &EndpointTLSData{
+		Files: map[string][]byte{
+			"key.pem": []byte("private-key-data"),
+		},
+	}))
+	return s
+}
+
+func TestExportImportEncrypted(t *testing.T) {
+	s := newEncryptTestStore(t)
+	identity, err := age.GenerateX25519Identity()
+	assert.NilError(t, err)
+
+	r := ExportEncrypted("source", s, identity.Recipient())
+	defer r.Close()
+	exported, err := io.ReadAll(r)
+	assert.NilError(t, err)
+	assert.Check(t, strings.HasPrefix(string(exported), armor.Header+"\n"))
+	assert.Check(t, !bytes.Contains(exported, []byte("private-key-data")))
+
+	encrypted, err := IsEncrypted(bufio.NewReader(bytes.NewReader(exported)))
+	assert.NilError(t, err)
+	assert.Check(t, encrypted)
+
+	err = Import("dest", s, bytes.NewReader(exported))
+	assert.Check(t, is.ErrorType(err, errdefs.IsInvalidArgument))
+	assert.Check(t, is.ErrorContains(err, "context is encrypted"))
+
+	other, err := age.GenerateX25519Identity()
+	assert.NilError(t, err)
+	err = ImportEncrypted("dest", s, bytes.NewReader(exported), other)
+	assert.Check(t, is.ErrorType(err, errdefs.IsInvalidArgument))
+	assert.Check(t, is.ErrorContains(err, "no identity matched any of the recipients"))
+
+	assert.NilError(t, ImportEncrypted("dest", s, bytes.NewReader(exported), identity))
+	srcMeta, err := s.GetMetadata("source")
+	assert.NilError(t, err)
+	destMeta, err := s.GetMetadata("dest")
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(destMeta.Metadata, srcMeta.Metadata))
+	assert.Check(t, is.DeepEqual(destMeta.Endpoints, srcMeta.Endpoints))
+	data, err := s.GetTLSData("dest", "ep1", "key.pem")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(data), "private-key-data"))
+}
+
+func TestImportEncryptedBinary(t *testing.T) {
+	s := newEncryptTestStore(t)
+	recipient, err := age.NewScryptRecipient("passphrase")
+	assert.NilError(t, err)
+	recipient.SetWorkFactor(10)
+
+	var buf bytes.Buffer
+	w, err := age.Encrypt(&buf, recipient)
+	assert.NilError(t, err)
+	r := Export("source", s)
+	defer r.Close()
+	_, err = io.Copy(w, r)
+	assert.NilError(t, err)
+	assert.NilError(t, w.Close())
+
+	encrypted, err := IsEncrypted(bufio.NewReader(bytes.NewReader(buf.Bytes())))
+	assert.NilError(t, err)
+	assert.Check(t, encrypted)
+
+	identity, err := age.NewScryptIdentity("passphrase")
+	assert.NilError(t, err)
+	assert.NilError(t, ImportEncrypted("dest", s, &buf, identity))
+	data, err := s.GetTLSData("dest", "ep1", "key.pem")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(data), "private-key-data"))
+}
+
+func TestIsEncryptedPlain(t *testing.T) {
+	s := newEncryptTestStore(t)
+	r := Export("source", s)
+	defer r.Close()
+	encrypted, err := IsEncrypted(bufio.NewReader(r))
+	assert.NilError(t, err)
+	assert.Check(t, !encrypted)
+}
@@ -12,6 +12,9 @@ func invalidParameter(err error) error {
 type invalidParameterErr struct{ error }
 
 func (invalidParameterErr) InvalidParameter() {}
+func (e invalidParameterErr) Unwrap() error {
+	return e.error
+}
 
 func notFound(err error) error {
 	if err == nil || errdefs.IsNotFound(err) {
@@ -101,7 +101,8 @@ func New(dir string, cfg Config) *ContextStore {
 			config: cfg,
 		},
 		tls: &tlsStore{
-			root: tlsRoot,
+			root:    tlsRoot,
+			secrets: cfg.secretStore,
 		},
 	}
 }
@@ -355,6 +356,14 @@ func Import(name string, s Writer, reader io.Reader) error {
 	// Buffered reader will not advance the buffer, needed to determine content type
 	r := bufio.NewReader(reader)
 
+	encrypted, err := IsEncrypted(r)
+	if err != nil {
+		return err
+	}
+	if encrypted {
+		return invalidParameter(errors.New("context is encrypted: use ImportEncrypted to import it"))
+	}
+
 	importContentType, err := getImportContentType(r)
 	if err != nil {
 		return err
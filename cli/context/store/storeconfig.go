@@ -26,6 +26,14 @@ func EndpointTypeGetter(name string, getter TypeGetter) NamedTypeGetter {
 type Config struct {
 	contextType   TypeGetter
 	endpointTypes map[string]TypeGetter
+	secretStore   SecretStore
+}
+
+// WithSecretStore returns a copy of the config that stores private keys of
+// endpoints in the given SecretStore instead of the context store.
+func (c Config) WithSecretStore(s SecretStore) Config {
+	c.secretStore = s
+	return c
 }
 
 // SetEndpoint set an endpoint typing information
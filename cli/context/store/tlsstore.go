@@ -1,17 +1,40 @@
 package store
 
 import (
+	"bytes"
+	"encoding/pem"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/moby/sys/atomicwriter"
 )
 
-const tlsDir = "tls"
+const (
+	tlsDir = "tls"
+
+	// secretRefPrefix is the prefix of TLS files that contain a reference
+	// to data that's stored in a [SecretStore] instead of the data itself.
+	secretRefPrefix = "docker-context-secret:"
+)
+
+// SecretStore stores secret TLS data of endpoints, such as private keys,
+// outside the context store, for example, in the OS keychain through a
+// credential helper. Keys have the format "<context>/<endpoint>/<file>".
+type SecretStore interface {
+	// Get returns the data stored for the given key.
+	Get(key string) ([]byte, error)
+	// Store stores data for the given key.
+	Store(key string, data []byte) error
+	// Erase removes the data stored for the given key.
+	Erase(key string) error
+}
 
 type tlsStore struct {
-	root string
+	root    string
+	secrets SecretStore
 }
 
 func (s *tlsStore) contextDir(name string) string {
@@ -31,6 +54,13 @@ func (s *tlsStore) createOrUpdate(name, endpointName, filename string, data []by
 	if err := os.MkdirAll(endpointDir, 0o700); err != nil {
 		return err
 	}
+	if s.secrets != nil && isPrivateKey(data) {
+		key := name + "/" + endpointName + "/" + filename
+		if err := s.secrets.Store(key, data); err != nil {
+			return fmt.Errorf("failed to store TLS data for endpoint %s: %w", endpointName, err)
+		}
+		data = []byte(secretRefPrefix + key + "\n")
+	}
 	return atomicwriter.WriteFile(filepath.Join(endpointDir, filename), data, 0o600)
 }
 
@@ -42,24 +72,68 @@ func (s *tlsStore) getData(name, endpointName, filename string) ([]byte, error)
 		}
 		return nil, fmt.Errorf("failed to read TLS data for endpoint %s: %w", endpointName, err)
 	}
+	key, ok := parseSecretRef(data)
+	if !ok {
+		return data, nil
+	}
+	if s.secrets == nil {
+		return nil, fmt.Errorf("failed to read TLS data for endpoint %s: data is stored in a credential helper, but no credential helper is configured", endpointName)
+	}
+	data, err = s.secrets.Get(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read TLS data for endpoint %s: %w", endpointName, err)
+	}
 	return data, nil
 }
 
 // remove deletes all TLS data for the given context.
 func (s *tlsStore) remove(name string) error {
+	errs := s.eraseSecrets(s.contextDir(name))
 	if err := os.RemoveAll(s.contextDir(name)); err != nil {
 		return fmt.Errorf("failed to remove TLS data: %w", err)
 	}
+	if errs != nil {
+		return fmt.Errorf("failed to remove TLS data: %w", errs)
+	}
 	return nil
 }
 
 func (s *tlsStore) removeEndpoint(name, endpointName string) error {
+	errs := s.eraseSecrets(s.endpointDir(name, endpointName))
 	if err := os.RemoveAll(s.endpointDir(name, endpointName)); err != nil {
 		return fmt.Errorf("failed to remove TLS data for endpoint %s: %w", endpointName, err)
 	}
+	if errs != nil {
+		return fmt.Errorf("failed to remove TLS data for endpoint %s: %w", endpointName, errs)
+	}
 	return nil
 }
 
+// eraseSecrets erases the data that is stored in the SecretStore for the
+// TLS files in dir and its subdirectories.
+func (s *tlsStore) eraseSecrets(dir string) error {
+	if s.secrets == nil {
+		return nil
+	}
+	var errs []error
+	_ = filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		if key, ok := parseSecretRef(data); ok {
+			if err := s.secrets.Erase(key); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return nil
+	})
+	return errors.Join(errs...)
+}
+
 func (s *tlsStore) listContextData(name string) (map[string]EndpointFiles, error) {
 	contextDir := s.contextDir(name)
 	epFSs, err := os.ReadDir(contextDir)
@@ -93,3 +167,19 @@ func (s *tlsStore) listContextData(name string) (map[string]EndpointFiles, error
 
 // EndpointFiles is a slice of strings representing file names
 type EndpointFiles []string
+
+// isPrivateKey returns whether data is a PEM-encoded private key, which
+// is stored in the SecretStore if configured.
+func isPrivateKey(data []byte) bool {
+	block, _ := pem.Decode(data)
+	return block != nil && strings.HasSuffix(block.Type, "PRIVATE KEY")
+}
+
+// parseSecretRef returns the key of the data in the SecretStore if data
+// is a reference to it.
+func parseSecretRef(data []byte) (key string, ok bool) {
+	if !bytes.HasPrefix(data, []byte(secretRefPrefix)) {
+		return "", false
+	}
+	return strings.TrimSpace(string(data[len(secretRefPrefix):])), true
+}
@@ -6,6 +6,7 @@
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/containerd/errdefs"
 	"github.com/distribution/reference"
@@ -21,6 +22,42 @@ type Store interface {
 	Get(listRef reference.Reference, manifest reference.Reference) (types.ImageManifest, error)
 	GetList(listRef reference.Reference) ([]types.ImageManifest, error)
 	Save(listRef reference.Reference, manifest reference.Reference, image types.ImageManifest) error
+	GetListOptions(listRef reference.Reference) (types.ListOptions, error)
+	SaveListOptions(listRef reference.Reference, options types.ListOptions) error
+	List() ([]ListInfo, error)
+	SetPushed(listRef reference.Reference, dgst digest.Digest) error
+}
+
+// ListInfo describes a local manifest list.
+type ListInfo struct {
+	// Name is the reference of the manifest list.
+	Name string
+	// Manifests are the manifests in the manifest list.
+	Manifests []types.ImageManifest
+	// Updated is the time the manifest list was last changed.
+	Updated time.Time
+	// Pushed is the time the manifest list was last pushed, if it was
+	// pushed after it was last changed.
+	Pushed time.Time `json:",omitempty"`
+	// Digest is the digest of the manifest list when it was pushed.
+	Digest digest.Digest `json:",omitempty"`
+}
+
+// Files in the directory of a manifest list that store the properties of
+// the manifest list itself. Their names start with a dot, so that they cannot
+// conflict with the name of a manifest file.
+const (
+	// listOptionsFile stores the ListOptions of the manifest list.
+	listOptionsFile = ".list-options.json"
+	// listStatusFile stores the name of the manifest list, and the time
+	// and digest of the last push.
+	listStatusFile = ".list-status.json"
+)
+
+type listStatus struct {
+	Name   string
+	Pushed time.Time     `json:",omitempty"`
+	Digest digest.Digest `json:",omitempty"`
 }
 
 // fsStore manages manifest files stored on the local filesystem
@@ -121,6 +158,9 @@ func (s *fsStore) listManifests(transaction string) ([]string, error) {
 
 	filenames := make([]string, 0, len(fileInfos))
 	for _, info := range fileInfos {
+		if info.Name() == listOptionsFile || info.Name() == listStatusFile {
+			continue
+		}
 		filenames = append(filenames, info.Name())
 	}
 	return filenames, nil
@@ -128,7 +168,7 @@ func (s *fsStore) listManifests(transaction string) ([]string, error) {
 
 // Save a manifest as part of a local manifest list
 func (s *fsStore) Save(listRef reference.Reference, manifest reference.Reference, image types.ImageManifest) error {
-	if err := s.createManifestListDirectory(listRef.String()); err != nil {
+	if err := s.createManifestListDirectory(listRef); err != nil {
 		return err
 	}
 	filename := manifestToFilename(s.root, listRef.String(), manifest.String())
@@ -139,9 +179,141 @@ func (s *fsStore) Save(listRef reference.Reference, manifest reference.Reference
 	return os.WriteFile(filename, bytes, 0o644)
 }
 
-func (s *fsStore) createManifestListDirectory(transaction string) error {
-	path := filepath.Join(s.root, makeFilesafeName(transaction))
-	return os.MkdirAll(path, 0o755)
+// GetListOptions returns the options of a local manifest list. It returns
+// the default options if none were saved.
+func (s *fsStore) GetListOptions(listRef reference.Reference) (types.ListOptions, error) {
+	var options types.ListOptions
+	bytes, err := os.ReadFile(filepath.Join(s.root, makeFilesafeName(listRef.String()), listOptionsFile))
+	switch {
+	case os.IsNotExist(err):
+		return options, nil
+	case err != nil:
+		return options, err
+	}
+	if err := json.Unmarshal(bytes, &options); err != nil {
+		return options, err
+	}
+	return options, nil
+}
+
+// SaveListOptions saves the options of a local manifest list
+func (s *fsStore) SaveListOptions(listRef reference.Reference, options types.ListOptions) error {
+	if err := s.createManifestListDirectory(listRef); err != nil {
+		return err
+	}
+	bytes, err := json.Marshal(options)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(s.root, makeFilesafeName(listRef.String()), listOptionsFile), bytes, 0o644)
+}
+
+// List returns all local manifest lists.
+func (s *fsStore) List() ([]ListInfo, error) {
+	dirs, err := os.ReadDir(s.root)
+	switch {
+	case os.IsNotExist(err):
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+
+	lists := make([]ListInfo, 0, len(dirs))
+	for _, dir := range dirs {
+		if !dir.IsDir() {
+			continue
+		}
+		info, err := s.getListInfo(dir.Name())
+		if err != nil {
+			return nil, err
+		}
+		lists = append(lists, info)
+	}
+	return lists, nil
+}
+
+func (s *fsStore) getListInfo(dirName string) (ListInfo, error) {
+	dir := filepath.Join(s.root, dirName)
+	status, err := readListStatus(dir)
+	if err != nil {
+		return ListInfo{}, err
+	}
+	info := ListInfo{Name: status.Name}
+	if info.Name == "" {
+		// Manifest lists that were created before the name was stored.
+		info.Name = nameFromFilesafeName(dirName)
+	}
+
+	fileInfos, err := os.ReadDir(dir)
+	if err != nil {
+		return ListInfo{}, err
+	}
+	for _, fi := range fileInfos {
+		if fi.Name() == listStatusFile {
+			continue
+		}
+		st, err := fi.Info()
+		if err != nil {
+			return ListInfo{}, err
+		}
+		if st.ModTime().After(info.Updated) {
+			info.Updated = st.ModTime()
+		}
+		if fi.Name() == listOptionsFile {
+			continue
+		}
+		manifest, err := s.getFromFilename(fileRef(fi.Name()), filepath.Join(dir, fi.Name()))
+		if err != nil {
+			return ListInfo{}, err
+		}
+		info.Manifests = append(info.Manifests, manifest)
+	}
+	if !status.Pushed.Before(info.Updated) {
+		info.Pushed = status.Pushed
+		info.Digest = status.Digest
+	}
+	return info, nil
+}
+
+// SetPushed records that a manifest list was pushed with the given digest.
+func (s *fsStore) SetPushed(listRef reference.Reference, dgst digest.Digest) error {
+	return s.writeListStatus(listRef, listStatus{Name: listRef.String(), Pushed: time.Now(), Digest: dgst})
+}
+
+func readListStatus(dir string) (listStatus, error) {
+	var status listStatus
+	bytes, err := os.ReadFile(filepath.Join(dir, listStatusFile))
+	switch {
+	case os.IsNotExist(err):
+		return status, nil
+	case err != nil:
+		return status, err
+	}
+	if err := json.Unmarshal(bytes, &status); err != nil {
+		return status, fmt.Errorf("invalid manifest list status %s: %w", filepath.Join(dir, listStatusFile), err)
+	}
+	return status, nil
+}
+
+func (s *fsStore) writeListStatus(listRef reference.Reference, status listStatus) error {
+	bytes, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(s.root, makeFilesafeName(listRef.String()), listStatusFile), bytes, 0o644)
+}
+
+// createManifestListDirectory creates the directory of a manifest list if
+// it does not exist, and records the name of the manifest list in it.
+func (s *fsStore) createManifestListDirectory(listRef reference.Reference) error {
+	path := filepath.Join(s.root, makeFilesafeName(listRef.String()))
+	if _, err := os.Stat(filepath.Join(path, listStatusFile)); err == nil {
+		return nil
+	}
+	if err := os.MkdirAll(path, 0o755); err != nil {
+		return err
+	}
+	return s.writeListStatus(listRef, listStatus{Name: listRef.String()})
 }
 
 func manifestToFilename(root, manifestList, manifest string) string {
@@ -153,6 +325,25 @@ func makeFilesafeName(ref string) string {
 	return strings.ReplaceAll(fileName, "/", "_")
 }
 
+// nameFromFilesafeName returns the reference that makeFilesafeName was
+// called with. The result is ambiguous if the name of the repository
+// contains a "-", so it is only used if the name of a manifest list was not
+// stored.
+func nameFromFilesafeName(fileName string) string {
+	name := strings.ReplaceAll(fileName, "_", "/")
+	if i := strings.LastIndex(name, "-"); i > strings.LastIndex(name, "/") {
+		name = name[:i] + ":" + name[i+1:]
+	}
+	return name
+}
+
+// fileRef is a reference.Reference for a manifest file, used in errors.
+type fileRef string
+
+func (r fileRef) String() string {
+	return string(r)
+}
+
 func newNotFoundError(ref string) error {
 	return errdefs.ErrNotFound.WithMessage("No such manifest: " + ref)
 }
@@ -2,7 +2,9 @@
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/containerd/errdefs"
 	"github.com/distribution/reference"
@@ -120,3 +122,65 @@ func TestStoreGetListDoesNotExist(t *testing.T) {
 	assert.Error(t, err, "No such manifest: list")
 	assert.Check(t, errdefs.IsNotFound(err))
 }
+
+func TestStoreListOptions(t *testing.T) {
+	store := NewStore(t.TempDir())
+	listRef := ref("list")
+
+	options, err := store.GetListOptions(listRef)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(options, types.ListOptions{}))
+
+	assert.NilError(t, store.Save(listRef, ref("first"), types.ImageManifest{Ref: sref(t, "first")}))
+	expected := types.ListOptions{
+		MediaType:   "application/vnd.oci.image.index.v1+json",
+		Annotations: map[string]string{"org.opencontainers.image.version": "1.0"},
+	}
+	assert.NilError(t, store.SaveListOptions(listRef, expected))
+
+	options, err = store.GetListOptions(listRef)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(options, expected))
+
+	list, err := store.GetList(listRef)
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(list, 1))
+}
+
+func TestStoreList(t *testing.T) {
+	tmpDir := t.TempDir()
+	store := NewStore(tmpDir)
+
+	lists, err := store.List()
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(lists, 0))
+
+	assert.NilError(t, store.Save(ref("example.com/list:v1"), ref("first"), types.ImageManifest{Ref: sref(t, "first")}))
+	assert.NilError(t, store.Save(ref("example.com/list:v1"), ref("second"), types.ImageManifest{Ref: sref(t, "second")}))
+	assert.NilError(t, store.Save(ref("example.com/other:v1"), ref("first"), types.ImageManifest{Ref: sref(t, "first")}))
+	assert.NilError(t, store.SetPushed(ref("example.com/other:v1"), "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"))
+
+	// A manifest list that was created before the name was stored.
+	assert.NilError(t, os.Mkdir(filepath.Join(tmpDir, "example.com_legacy-v2"), 0o755))
+
+	lists, err = store.List()
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(lists, 3))
+	assert.Check(t, is.Equal(lists[0].Name, "example.com/legacy:v2"))
+	assert.Check(t, is.Equal(lists[1].Name, "example.com/list:v1"))
+	assert.Check(t, is.Len(lists[1].Manifests, 2))
+	assert.Check(t, !lists[1].Updated.IsZero())
+	assert.Check(t, lists[1].Pushed.IsZero())
+	assert.Check(t, is.Equal(lists[2].Name, "example.com/other:v1"))
+	assert.Check(t, !lists[2].Pushed.IsZero())
+	assert.Check(t, is.Equal(lists[2].Digest.String(), "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"))
+
+	// Changing a manifest list after it was pushed resets the pushed state.
+	later := time.Now().Add(time.Minute)
+	assert.NilError(t, store.Save(ref("example.com/other:v1"), ref("second"), types.ImageManifest{Ref: sref(t, "second")}))
+	assert.NilError(t, os.Chtimes(filepath.Join(tmpDir, "example.com_other-v1", "second"), later, later))
+	lists, err = store.List()
+	assert.NilError(t, err)
+	assert.Check(t, lists[2].Pushed.IsZero())
+	assert.Check(t, is.Equal(lists[2].Digest.String(), ""))
+}
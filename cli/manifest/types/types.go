@@ -25,6 +25,17 @@ type ImageManifest struct {
 	OCIManifest *ocischema.DeserializedManifest `json:",omitempty"`
 }
 
+// ListOptions are the properties of a local manifest list that apply to the
+// manifest list itself, rather than to the manifests it contains.
+type ListOptions struct {
+	// MediaType is the media type of the manifest list; either a Docker
+	// manifest list, or an OCI image index. If empty, an OCI image index is
+	// created if the first manifest in the list is an OCI image manifest.
+	MediaType string `json:",omitempty"`
+	// Annotations are the annotations of an OCI image index.
+	Annotations map[string]string `json:",omitempty"`
+}
+
 // OCIPlatform creates an OCI platform from a manifest list platform spec
 func OCIPlatform(ps *manifestlist.PlatformSpec) *ocispec.Platform {
 	if ps == nil {
@@ -79,7 +79,7 @@ func dockerMain(ctx context.Context) error {
 	ctx, cancelNotify := notifyContext(ctx, platformsignals.TerminationSignals...)
 	defer cancelNotify()
 
-	dockerCli, err := command.NewDockerCli(command.WithBaseContext(ctx))
+	dockerCli, err := command.NewDockerCli(command.WithBaseContext(ctx), command.WithConnectionPlugins(pluginmanager.GetConnectionHelper))
 	if err != nil {
 		return err
 	}
@@ -437,6 +437,7 @@ func runDocker(ctx context.Context, dockerCli *command.DockerCli) error {
 	if err != nil {
 		return err
 	}
+	args = processMultipleContexts(tcmd, cmd, args)
 
 	if err := tcmd.Initialize(command.WithEnableGlobalMeterProvider(), command.WithEnableGlobalTracerProvider()); err != nil {
 		return err
@@ -513,6 +514,20 @@ func runDocker(ctx context.Context, dockerCli *command.DockerCli) error {
 	return err
 }
 
+// processMultipleContexts rewrites "docker --context a,b COMMAND" to
+// "docker context exec --contexts a,b -- COMMAND", which runs the command
+// against each of the contexts. Context names cannot contain commas, so
+// a comma in the "--context" option always separates multiple contexts.
+func processMultipleContexts(tcmd *cli.TopLevelCommand, cmd *cobra.Command, args []string) []string {
+	f := cmd.Flags().Lookup("context")
+	if f == nil || !strings.Contains(f.Value.String(), ",") || len(args) == 0 || hasCompletionArg(args) {
+		return args
+	}
+	contexts := f.Value.String()
+	tcmd.SetFlag("context", "")
+	return append([]string{"context", "exec", "--contexts", contexts, "--"}, args...)
+}
+
 // hasCompletionArg returns true if a cobra completion arg request is found.
 func hasCompletionArg(args []string) bool {
 	for _, arg := range args {
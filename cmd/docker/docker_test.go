@@ -54,6 +54,40 @@ func TestClientDebugEnabled(t *testing.T) {
 	assert.Check(t, is.Equal(logrus.DebugLevel, logrus.GetLevel()))
 }
 
+func TestProcessMultipleContexts(t *testing.T) {
+	tests := []struct {
+		doc          string
+		args         []string
+		expectedArgs []string
+		expectedCtx  string
+	}{
+		{
+			doc:          "single context",
+			args:         []string{"--context", "foo", "ps", "-a"},
+			expectedArgs: []string{"ps", "-a"},
+			expectedCtx:  "foo",
+		},
+		{
+			doc:          "multiple contexts",
+			args:         []string{"--context", "foo,bar", "ps", "-a"},
+			expectedArgs: []string{"context", "exec", "--contexts", "foo,bar", "--", "ps", "-a"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.doc, func(t *testing.T) {
+			cli, err := command.NewDockerCli(command.WithBaseContext(context.TODO()))
+			assert.NilError(t, err)
+			tcmd := newDockerCommand(cli)
+			tcmd.SetArgs(tc.args)
+			cmd, args, err := tcmd.HandleGlobalFlags()
+			assert.NilError(t, err)
+			args = processMultipleContexts(tcmd, cmd, args)
+			assert.Check(t, is.DeepEqual(args, tc.expectedArgs))
+			assert.Check(t, is.Equal(cmd.Flags().Lookup("context").Value.String(), tc.expectedCtx))
+		})
+	}
+}
+
 var discard = io.NopCloser(bytes.NewBuffer(nil))
 
 func runCliCommand(t *testing.T, r io.ReadCloser, w io.Writer, args ...string) error {
@@ -0,0 +1,131 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.23
+
+package jsonstream
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/docker/cli/cli/streams"
+)
+
+// Job is a named operation that produces a stream of JSON messages, such
+// as an image pull or push.
+type Job struct {
+	// Name is used to identify the job's messages in the combined output.
+	Name string
+	// Run starts the operation and returns its stream of JSON messages.
+	Run func(ctx context.Context) (io.ReadCloser, error)
+}
+
+// Result is the outcome of running a [Job].
+type Result struct {
+	Name string
+	Err  error
+}
+
+// RunParallel runs the given jobs, with at most parallel jobs running
+// concurrently, and displays their progress as a single stream.
+//
+// The ID of each message is prefixed with the name of the job it belongs
+// to, and messages without an ID are shown on the job's status line. Errors
+// reported by a job do not stop other jobs; they are shown in the job's
+// status line, and returned in its [Result]. Results are returned in the
+// same order as the jobs.
+func RunParallel(ctx context.Context, out *streams.Out, parallel int, jobs []Job, opts ...Options) []Result {
+	if parallel < 1 {
+		parallel = 1
+	}
+
+	pr, pw := io.Pipe()
+	mux := &multiplexer{enc: json.NewEncoder(pw)}
+
+	displayDone := make(chan error, 1)
+	go func() {
+		err := Display(ctx, pr, out, opts...)
+		// Make sure jobs don't block on writing if the display stopped.
+		_ = pr.CloseWithError(errors.New("display stopped"))
+		displayDone <- err
+	}()
+
+	results := make([]Result, len(jobs))
+	sem := make(chan struct{}, parallel)
+	var wg sync.WaitGroup
+	for i, job := range jobs {
+		results[i].Name = job.Name
+		mux.send(JSONMessage{ID: job.Name, Status: "Waiting"})
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				results[i].Err = ctx.Err()
+				return
+			}
+			results[i].Err = mux.run(ctx, job)
+		}()
+	}
+	wg.Wait()
+	_ = pw.Close()
+
+	if err := <-displayDone; err != nil {
+		for i := range results {
+			if results[i].Err == nil {
+				results[i].Err = err
+			}
+		}
+	}
+	return results
+}
+
+type multiplexer struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+func (m *multiplexer) send(jm JSONMessage) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_ = m.enc.Encode(jm)
+}
+
+// run runs the job, and forwards its messages to the combined stream.
+func (m *multiplexer) run(ctx context.Context, job Job) error {
+	rc, err := job.Run(ctx)
+	if err != nil {
+		m.send(JSONMessage{ID: job.Name, Status: "Error: " + err.Error()})
+		return err
+	}
+	defer rc.Close()
+
+	dec := json.NewDecoder(rc)
+	for {
+		var jm JSONMessage
+		if err := dec.Decode(&jm); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			m.send(JSONMessage{ID: job.Name, Status: "Error: " + err.Error()})
+			return err
+		}
+		if jm.Error != nil {
+			m.send(JSONMessage{ID: job.Name, Status: "Error: " + jm.Error.Message})
+			return jm.Error
+		}
+		if jm.ID == "" {
+			jm.ID = job.Name
+		} else {
+			jm.ID = job.Name + " " + jm.ID
+		}
+		m.send(jm)
+	}
+	m.send(JSONMessage{ID: job.Name, Status: "Done"})
+	return nil
+}
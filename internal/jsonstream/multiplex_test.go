@@ -0,0 +1,64 @@
+package jsonstream
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/docker/cli/cli/streams"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestRunParallel(t *testing.T) {
+	job := func(name string, stream string, err error) Job {
+		return Job{
+			Name: name,
+			Run: func(context.Context) (io.ReadCloser, error) {
+				if err != nil {
+					return nil, err
+				}
+				return io.NopCloser(strings.NewReader(stream)), nil
+			},
+		}
+	}
+
+	var buf bytes.Buffer
+	results := RunParallel(context.Background(), streams.NewOut(&buf), 2, []Job{
+		job("one", `{"status":"Pulling from library/one","id":"latest"}`+"\n"+`{"status":"Pull complete","id":"abc123"}`+"\n", nil),
+		job("two", `{"status":"Digest: sha256:1234"}`+"\n", nil),
+		job("three", `{"errorDetail":{"message":"manifest unknown"},"error":"manifest unknown"}`+"\n", nil),
+		job("four", "", errors.New("connection refused")),
+	})
+
+	assert.Check(t, is.Len(results, 4))
+	assert.Check(t, is.Equal(results[0].Name, "one"))
+	assert.Check(t, results[0].Err == nil)
+	assert.Check(t, results[1].Err == nil)
+	assert.Check(t, is.ErrorContains(results[2].Err, "manifest unknown"))
+	assert.Check(t, is.ErrorContains(results[3].Err, "connection refused"))
+
+	out := buf.String()
+	assert.Check(t, is.Contains(out, "one latest: Pulling from library/one\n"))
+	assert.Check(t, is.Contains(out, "one abc123: Pull complete\n"))
+	assert.Check(t, is.Contains(out, "two: Digest: sha256:1234\n"))
+	assert.Check(t, is.Contains(out, "three: Error: manifest unknown\n"))
+	assert.Check(t, is.Contains(out, "four: Error: connection refused\n"))
+	assert.Check(t, is.Contains(out, "one: Done\n"))
+}
+
+func TestRunParallelCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results := RunParallel(ctx, streams.NewOut(io.Discard), 1, []Job{
+		{Name: "one", Run: func(context.Context) (io.ReadCloser, error) {
+			return io.NopCloser(strings.NewReader("")), nil
+		}},
+	})
+	assert.Check(t, is.Len(results, 1))
+	assert.Check(t, is.ErrorIs(results[0].Err, context.Canceled))
+}
@@ -25,14 +25,21 @@ type OAuthAPI interface {
 	GetAutoPAT(ctx context.Context, audience string, res TokenResponse) (string, error)
 }
 
-// API represents API interactions with Auth0.
+// API represents API interactions with Auth0, or with another authorization
+// server that supports the device authorization grant.
 type API struct {
 	// TenantURL is the base used for each request to Auth0.
 	TenantURL string
+	// Metadata contains the endpoints of the authorization server. Endpoints
+	// that are not set use the Auth0 endpoints relative to TenantURL.
+	Metadata ServerMetadata
 	// ClientID is the client ID for the application to auth with the tenant.
 	ClientID string
 	// Scopes are the scopes that are requested during the device auth flow.
 	Scopes []string
+	// HTTPClient is the client that is used for requests to the tenant. It
+	// defaults to [http.DefaultClient].
+	HTTPClient *http.Client
 }
 
 // TokenResponse represents the response of the /oauth/token route.
@@ -55,12 +62,13 @@ type TokenResponse struct {
 func (a API) GetDeviceCode(ctx context.Context, audience string) (State, error) {
 	data := url.Values{
 		"client_id": {a.ClientID},
-		"audience":  {audience},
 		"scope":     {strings.Join(a.Scopes, " ")},
 	}
+	if audience != "" {
+		data.Set("audience", audience)
+	}
 
-	deviceCodeURL := a.TenantURL + "/oauth/device/code"
-	resp, err := postForm(ctx, deviceCodeURL, strings.NewReader(data.Encode()))
+	resp, err := a.postForm(ctx, a.deviceCodeURL(), strings.NewReader(data.Encode()))
 	if err != nil {
 		return State{}, err
 	}
@@ -77,6 +85,11 @@ func (a API) GetDeviceCode(ctx context.Context, audience string) (State, error)
 	if err != nil {
 		return state, fmt.Errorf("failed to get device code: %w", err)
 	}
+	if state.VerificationURI == "" {
+		// verification_uri_complete is optional; fall back to the URL where
+		// the user has to enter the code.
+		state.VerificationURI = state.BaseVerificationURI
+	}
 
 	return state, nil
 }
@@ -125,7 +138,13 @@ func (a API) WaitForDeviceToken(ctx context.Context, state State) (TokenResponse
 			}
 
 			if res.Error != nil {
-				if *res.Error == "authorization_pending" {
+				switch *res.Error {
+				case "authorization_pending":
+					continue
+				case "slow_down":
+					// the authorization server requests us to increase
+					// the interval by 5 seconds (RFC 8628, section 3.5).
+					state.Interval = int(state.IntervalDuration()/time.Second) + defaultPollInterval
 					continue
 				}
 
@@ -165,9 +184,8 @@ func (a API) getDeviceToken(ctx context.Context, state State) (TokenResponse, er
 		"grant_type":  {"urn:ietf:params:oauth:grant-type:device_code"},
 		"device_code": {state.DeviceCode},
 	}
-	oauthTokenURL := a.TenantURL + "/oauth/token"
 
-	resp, err := postForm(ctx, oauthTokenURL, strings.NewReader(data.Encode()))
+	resp, err := a.postForm(ctx, a.tokenURL(), strings.NewReader(data.Encode()))
 	if err != nil {
 		return TokenResponse{}, fmt.Errorf("failed to get tokens: %w", err)
 	}
@@ -187,16 +205,46 @@ func (a API) getDeviceToken(ctx context.Context, state State) (TokenResponse, er
 	return res, nil
 }
 
+// Refresh exchanges a refresh token for new tokens with the tenant.
+func (a API) Refresh(ctx context.Context, refreshToken string) (TokenResponse, error) {
+	data := url.Values{
+		"client_id":     {a.ClientID},
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+	}
+
+	resp, err := a.postForm(ctx, a.tokenURL(), strings.NewReader(data.Encode()))
+	if err != nil {
+		return TokenResponse{}, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return TokenResponse{}, tryDecodeOAuthError(resp)
+	}
+
+	var res TokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return res, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return res, nil
+}
+
 // RevokeToken revokes a refresh token with the tenant so that it can no longer
 // be used to get new tokens.
 func (a API) RevokeToken(ctx context.Context, refreshToken string) error {
+	revokeURL := a.revokeURL()
+	if revokeURL == "" {
+		return errors.New("the authorization server does not support revoking tokens")
+	}
 	data := url.Values{
 		"client_id": {a.ClientID},
 		"token":     {refreshToken},
 	}
 
-	revokeURL := a.TenantURL + "/oauth/revoke"
-	resp, err := postForm(ctx, revokeURL, strings.NewReader(data.Encode()))
+	resp, err := a.postForm(ctx, revokeURL, strings.NewReader(data.Encode()))
 	if err != nil {
 		return err
 	}
@@ -211,20 +259,58 @@ func (a API) RevokeToken(ctx context.Context, refreshToken string) error {
 	return nil
 }
 
-func postForm(ctx context.Context, reqURL string, data io.Reader) (*http.Response, error) {
+func (a API) deviceCodeURL() string {
+	if a.Metadata.DeviceAuthorizationEndpoint != "" {
+		return a.Metadata.DeviceAuthorizationEndpoint
+	}
+	return a.TenantURL + "/oauth/device/code"
+}
+
+func (a API) tokenURL() string {
+	if a.Metadata.TokenEndpoint != "" {
+		return a.Metadata.TokenEndpoint
+	}
+	return a.TenantURL + "/oauth/token"
+}
+
+// revokeURL returns the revocation endpoint, or an empty string if the
+// authorization server doesn't have one.
+func (a API) revokeURL() string {
+	if a.Metadata.RevocationEndpoint != "" {
+		return a.Metadata.RevocationEndpoint
+	}
+	if a.TenantURL == "" {
+		return ""
+	}
+	return a.TenantURL + "/oauth/revoke"
+}
+
+func (a API) postForm(ctx context.Context, reqURL string, data io.Reader) (*http.Response, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, reqURL, data)
 	if err != nil {
 		return nil, err
 	}
 
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	cliVersion := strings.ReplaceAll(version.Version, ".", "_")
-	req.Header.Set("User-Agent", fmt.Sprintf("docker-cli:%s:%s-%s", cliVersion, runtime.GOOS, runtime.GOARCH))
+	req.Header.Set("User-Agent", userAgent())
+
+	return httpClient(a.HTTPClient).Do(req)
+}
 
-	return http.DefaultClient.Do(req)
+// httpClient returns c, or [http.DefaultClient] if c is nil.
+func httpClient(c *http.Client) *http.Client {
+	if c == nil {
+		return http.DefaultClient
+	}
+	return c
+}
+
+func userAgent() string {
+	cliVersion := strings.ReplaceAll(version.Version, ".", "_")
+	return fmt.Sprintf("docker-cli:%s:%s-%s", cliVersion, runtime.GOOS, runtime.GOARCH)
 }
 
-func (API) GetAutoPAT(ctx context.Context, audience string, res TokenResponse) (string, error) {
+func (a API) GetAutoPAT(ctx context.Context, audience string, res TokenResponse) (string, error) {
 	patURL := audience + "/v2/access-tokens/desktop-generate"
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, patURL, nil)
 	if err != nil {
@@ -233,7 +319,7 @@ func (API) GetAutoPAT(ctx context.Context, audience string, res TokenResponse) (
 
 	req.Header.Set("Authorization", "Bearer "+res.AccessToken)
 	req.Header.Set("Content-Type", "application/json")
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := httpClient(a.HTTPClient).Do(req)
 	if err != nil {
 		return "", err
 	}
@@ -53,6 +53,32 @@ func TestGetDeviceCode(t *testing.T) {
 		assert.Equal(t, path, "/oauth/device/code")
 	})
 
+	t.Run("custom endpoint", func(t *testing.T) {
+		t.Parallel()
+		var path string
+		var hasAudience bool
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r.ParseForm()
+			path = r.URL.Path
+			hasAudience = r.Form.Has("audience")
+
+			_, _ = w.Write([]byte(`{"device_code":"aDeviceCode","user_code":"aUserCode","verification_uri":"https://auth.example.com/device","expires_in":60}`))
+		}))
+		defer ts.Close()
+		api := API{
+			Metadata: ServerMetadata{DeviceAuthorizationEndpoint: ts.URL + "/device/authorize"},
+			ClientID: "aClientID",
+		}
+
+		state, err := api.GetDeviceCode(context.Background(), "")
+		assert.NilError(t, err)
+
+		assert.Equal(t, path, "/device/authorize")
+		assert.Check(t, !hasAudience)
+		assert.Equal(t, state.VerificationURI, "https://auth.example.com/device")
+		assert.Equal(t, state.IntervalDuration(), 5*time.Second)
+	})
+
 	t.Run("error w/ description", func(t *testing.T) {
 		t.Parallel()
 		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
@@ -333,6 +359,69 @@ func TestRevoke(t *testing.T) {
 	})
 }
 
+func TestRefresh(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		expectedToken := TokenResponse{
+			AccessToken:  "a-new-token",
+			RefreshToken: "a-new-refresh-token",
+			ExpiresIn:    300,
+		}
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "POST", r.Method)
+			assert.Equal(t, "/token", r.URL.Path)
+			assert.Equal(t, r.FormValue("client_id"), "aClientID")
+			assert.Equal(t, r.FormValue("grant_type"), "refresh_token")
+			assert.Equal(t, r.FormValue("refresh_token"), "a-refresh-token")
+
+			jsonResponse, err := json.Marshal(expectedToken)
+			assert.NilError(t, err)
+			_, _ = w.Write(jsonResponse)
+		}))
+		defer ts.Close()
+		api := API{
+			Metadata: ServerMetadata{TokenEndpoint: ts.URL + "/token"},
+			ClientID: "aClientID",
+		}
+
+		token, err := api.Refresh(context.Background(), "a-refresh-token")
+		assert.NilError(t, err)
+		assert.DeepEqual(t, token, expectedToken)
+	})
+
+	t.Run("error w/ description", func(t *testing.T) {
+		t.Parallel()
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			jsonResponse, err := json.Marshal(TokenResponse{
+				ErrorDescription: "refresh token expired",
+			})
+			assert.NilError(t, err)
+
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write(jsonResponse)
+		}))
+		defer ts.Close()
+		api := API{
+			TenantURL: ts.URL,
+			ClientID:  "aClientID",
+		}
+
+		_, err := api.Refresh(context.Background(), "a-refresh-token")
+		assert.ErrorContains(t, err, "refresh token expired")
+	})
+}
+
+func TestRevokeNoEndpoint(t *testing.T) {
+	api := API{
+		Metadata: ServerMetadata{TokenEndpoint: "https://auth.example.com/token"},
+		ClientID: "aClientID",
+	}
+	err := api.RevokeToken(context.Background(), "a-refresh-token")
+	assert.ErrorContains(t, err, "does not support revoking tokens")
+}
+
 func TestGetAutoPAT(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,147 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ServerMetadata contains the endpoints of an authorization server, as
+// published in its OpenID Connect discovery document, or its OAuth 2.0
+// authorization server metadata (RFC 8414).
+type ServerMetadata struct {
+	Issuer                      string `json:"issuer,omitempty"`
+	DeviceAuthorizationEndpoint string `json:"device_authorization_endpoint,omitempty"`
+	TokenEndpoint               string `json:"token_endpoint,omitempty"`
+	RevocationEndpoint          string `json:"revocation_endpoint,omitempty"`
+}
+
+// Discover fetches the metadata of the authorization server at issuerURL. It
+// tries the OpenID Connect discovery document first, and the OAuth 2.0
+// authorization server metadata if it's not found. An error is returned if
+// the issuer in the metadata is not issuerURL, or if the authorization server
+// doesn't support the device authorization grant.
+//
+// The issuer and the endpoints must use https, except for loopback addresses.
+// Requests are made with client, or [http.DefaultClient] if client is nil.
+func Discover(ctx context.Context, client *http.Client, issuerURL string) (ServerMetadata, error) {
+	u, err := url.Parse(strings.TrimSuffix(issuerURL, "/"))
+	if err != nil {
+		return ServerMetadata{}, fmt.Errorf("invalid issuer URL: %w", err)
+	}
+	if !isSecureURL(u) {
+		return ServerMetadata{}, fmt.Errorf("invalid issuer URL %q: must start with https://", issuerURL)
+	}
+
+	candidates := []string{
+		// OpenID Connect Discovery 1.0, section 4: appended to the issuer.
+		u.String() + "/.well-known/openid-configuration",
+		// RFC 8414, section 3: inserted between the host and the path.
+		u.Scheme + "://" + u.Host + "/.well-known/oauth-authorization-server" + u.Path,
+	}
+
+	var errs []error
+	for _, metadataURL := range candidates {
+		metadata, err := getMetadata(ctx, client, metadataURL)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		// The issuer must be identical to the issuer that the metadata was
+		// requested for; see RFC 8414, section 3.3, and OpenID Connect
+		// Discovery 1.0, section 4.3.
+		if strings.TrimSuffix(metadata.Issuer, "/") != u.String() {
+			return ServerMetadata{}, fmt.Errorf("issuer %q in the metadata of authorization server %s does not match the issuer", metadata.Issuer, issuerURL)
+		}
+		if metadata.DeviceAuthorizationEndpoint == "" || metadata.TokenEndpoint == "" {
+			return ServerMetadata{}, fmt.Errorf("authorization server %s does not support the device authorization grant", issuerURL)
+		}
+		for _, endpoint := range []string{metadata.DeviceAuthorizationEndpoint, metadata.TokenEndpoint, metadata.RevocationEndpoint} {
+			if endpoint == "" {
+				continue
+			}
+			if eu, err := url.Parse(endpoint); err != nil || !isSecureURL(eu) {
+				return ServerMetadata{}, fmt.Errorf("invalid endpoint %q in the metadata of authorization server %s: must start with https://", endpoint, issuerURL)
+			}
+		}
+		return metadata, nil
+	}
+	return ServerMetadata{}, fmt.Errorf("failed to discover authorization server metadata for %s: %w", issuerURL, errors.Join(errs...))
+}
+
+// isSecureURL returns whether u uses https. Plain http is only accepted for
+// loopback addresses, such as a local authorization server for testing.
+func isSecureURL(u *url.URL) bool {
+	switch u.Scheme {
+	case "https":
+		return true
+	case "http":
+		host := u.Hostname()
+		if host == "localhost" {
+			return true
+		}
+		ip := net.ParseIP(host)
+		return ip != nil && ip.IsLoopback()
+	default:
+		return false
+	}
+}
+
+func getMetadata(ctx context.Context, client *http.Client, metadataURL string) (ServerMetadata, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, metadataURL, http.NoBody)
+	if err != nil {
+		return ServerMetadata{}, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", userAgent())
+
+	resp, err := httpClient(client).Do(req)
+	if err != nil {
+		return ServerMetadata{}, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return ServerMetadata{}, fmt.Errorf("unexpected response from %s: %s", metadataURL, resp.Status)
+	}
+
+	var metadata ServerMetadata
+	if err := json.NewDecoder(resp.Body).Decode(&metadata); err != nil {
+		return ServerMetadata{}, fmt.Errorf("failed to decode metadata from %s: %w", metadataURL, err)
+	}
+	return metadata, nil
+}
+
+// ResolveMetadata returns the configured metadata, with the endpoints that are
+// not configured discovered from the configured issuer. Discovery is skipped
+// if both the device authorization endpoint and the token endpoint are set.
+// Requests are made with client, or [http.DefaultClient] if client is nil.
+func ResolveMetadata(ctx context.Context, client *http.Client, configured ServerMetadata) (ServerMetadata, error) {
+	if configured.DeviceAuthorizationEndpoint != "" && configured.TokenEndpoint != "" {
+		return configured, nil
+	}
+	if configured.Issuer == "" {
+		return ServerMetadata{}, errors.New("no issuer or endpoints are configured for the authorization server")
+	}
+	metadata, err := Discover(ctx, client, configured.Issuer)
+	if err != nil {
+		return ServerMetadata{}, err
+	}
+	if configured.DeviceAuthorizationEndpoint != "" {
+		metadata.DeviceAuthorizationEndpoint = configured.DeviceAuthorizationEndpoint
+	}
+	if configured.TokenEndpoint != "" {
+		metadata.TokenEndpoint = configured.TokenEndpoint
+	}
+	if configured.RevocationEndpoint != "" {
+		metadata.RevocationEndpoint = configured.RevocationEndpoint
+	}
+	return metadata, nil
+}
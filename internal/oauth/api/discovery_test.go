@@ -0,0 +1,160 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestDiscover(t *testing.T) {
+	t.Parallel()
+
+	t.Run("openid configuration", func(t *testing.T) {
+		t.Parallel()
+		var ts *httptest.Server
+		ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "GET", r.Method)
+			assert.Equal(t, "/realms/registry/.well-known/openid-configuration", r.URL.Path)
+			_, _ = w.Write([]byte(`{
+				"issuer": "` + ts.URL + `/realms/registry",
+				"device_authorization_endpoint": "https://auth.example.com/device",
+				"token_endpoint": "https://auth.example.com/token",
+				"revocation_endpoint": "https://auth.example.com/revoke",
+				"jwks_uri": "https://auth.example.com/certs"
+			}`))
+		}))
+		defer ts.Close()
+
+		metadata, err := Discover(context.Background(), nil, ts.URL+"/realms/registry/")
+		assert.NilError(t, err)
+		assert.DeepEqual(t, metadata, ServerMetadata{
+			Issuer:                      ts.URL + "/realms/registry",
+			DeviceAuthorizationEndpoint: "https://auth.example.com/device",
+			TokenEndpoint:               "https://auth.example.com/token",
+			RevocationEndpoint:          "https://auth.example.com/revoke",
+		})
+	})
+
+	t.Run("authorization server metadata", func(t *testing.T) {
+		t.Parallel()
+		var ts *httptest.Server
+		ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/.well-known/oauth-authorization-server/tenant" {
+				http.NotFound(w, r)
+				return
+			}
+			_, _ = w.Write([]byte(`{"issuer": "` + ts.URL + `/tenant", "device_authorization_endpoint": "https://auth.example.com/device", "token_endpoint": "https://auth.example.com/token"}`))
+		}))
+		defer ts.Close()
+
+		metadata, err := Discover(context.Background(), nil, ts.URL+"/tenant")
+		assert.NilError(t, err)
+		assert.DeepEqual(t, metadata, ServerMetadata{
+			Issuer:                      ts.URL + "/tenant",
+			DeviceAuthorizationEndpoint: "https://auth.example.com/device",
+			TokenEndpoint:               "https://auth.example.com/token",
+		})
+	})
+
+	t.Run("no device authorization", func(t *testing.T) {
+		t.Parallel()
+		var ts *httptest.Server
+		ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"issuer": "` + ts.URL + `", "token_endpoint": "https://auth.example.com/token"}`))
+		}))
+		defer ts.Close()
+
+		_, err := Discover(context.Background(), nil, ts.URL)
+		assert.ErrorContains(t, err, "does not support the device authorization grant")
+	})
+
+	t.Run("issuer mismatch", func(t *testing.T) {
+		t.Parallel()
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"issuer": "https://attacker.example.com", "device_authorization_endpoint": "https://attacker.example.com/device", "token_endpoint": "https://attacker.example.com/token"}`))
+		}))
+		defer ts.Close()
+
+		_, err := Discover(context.Background(), nil, ts.URL)
+		assert.ErrorContains(t, err, `issuer "https://attacker.example.com" in the metadata of authorization server`)
+		assert.ErrorContains(t, err, "does not match the issuer")
+	})
+
+	t.Run("insecure endpoint", func(t *testing.T) {
+		t.Parallel()
+		var ts *httptest.Server
+		ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"issuer": "` + ts.URL + `", "device_authorization_endpoint": "https://auth.example.com/device", "token_endpoint": "http://auth.example.com/token"}`))
+		}))
+		defer ts.Close()
+
+		_, err := Discover(context.Background(), nil, ts.URL)
+		assert.ErrorContains(t, err, `invalid endpoint "http://auth.example.com/token"`)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+		ts := httptest.NewServer(http.NotFoundHandler())
+		defer ts.Close()
+
+		_, err := Discover(context.Background(), nil, ts.URL)
+		assert.ErrorContains(t, err, "failed to discover authorization server metadata")
+		assert.ErrorContains(t, err, "404 Not Found")
+	})
+
+	t.Run("invalid issuer", func(t *testing.T) {
+		t.Parallel()
+		_, err := Discover(context.Background(), nil, "registry.example.com")
+		assert.ErrorContains(t, err, "must start with https://")
+	})
+
+	t.Run("insecure issuer", func(t *testing.T) {
+		t.Parallel()
+		_, err := Discover(context.Background(), nil, "http://registry.example.com")
+		assert.ErrorContains(t, err, `invalid issuer URL "http://registry.example.com": must start with https://`)
+	})
+}
+
+func TestResolveMetadata(t *testing.T) {
+	t.Parallel()
+
+	t.Run("configured endpoints", func(t *testing.T) {
+		t.Parallel()
+		configured := ServerMetadata{
+			DeviceAuthorizationEndpoint: "https://auth.example.com/device",
+			TokenEndpoint:               "https://auth.example.com/token",
+		}
+		metadata, err := ResolveMetadata(context.Background(), nil, configured)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, metadata, configured)
+	})
+
+	t.Run("configured endpoints override discovered", func(t *testing.T) {
+		t.Parallel()
+		var ts *httptest.Server
+		ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"issuer": "` + ts.URL + `", "device_authorization_endpoint": "https://auth.example.com/device", "token_endpoint": "https://auth.example.com/token"}`))
+		}))
+		defer ts.Close()
+
+		metadata, err := ResolveMetadata(context.Background(), nil, ServerMetadata{
+			Issuer:        ts.URL,
+			TokenEndpoint: "https://proxy.example.com/token",
+		})
+		assert.NilError(t, err)
+		assert.DeepEqual(t, metadata, ServerMetadata{
+			Issuer:                      ts.URL,
+			DeviceAuthorizationEndpoint: "https://auth.example.com/device",
+			TokenEndpoint:               "https://proxy.example.com/token",
+		})
+	})
+
+	t.Run("no issuer", func(t *testing.T) {
+		t.Parallel()
+		_, err := ResolveMetadata(context.Background(), nil, ServerMetadata{})
+		assert.ErrorContains(t, err, "no issuer or endpoints are configured")
+	})
+}
@@ -9,13 +9,24 @@ type State struct {
 	DeviceCode      string `json:"device_code"`
 	UserCode        string `json:"user_code"`
 	VerificationURI string `json:"verification_uri_complete"`
-	ExpiresIn       int    `json:"expires_in"`
-	Interval        int    `json:"interval"`
+	// BaseVerificationURI is the URL where the user enters the UserCode. It's
+	// used if the authorization server doesn't return a VerificationURI that
+	// includes the code.
+	BaseVerificationURI string `json:"verification_uri,omitempty"`
+	ExpiresIn           int    `json:"expires_in"`
+	Interval            int    `json:"interval"`
 }
 
+// defaultPollInterval is the interval (in seconds) to poll for the device token
+// if the authorization server doesn't specify one, as defined in RFC 8628.
+const defaultPollInterval = 5
+
 // IntervalDuration returns the duration that should be waited between each auth
 // polling event.
 func (s State) IntervalDuration() time.Duration {
+	if s.Interval <= 0 {
+		return time.Second * defaultPollInterval
+	}
 	return time.Second * time.Duration(s.Interval)
 }
 
@@ -14,6 +14,13 @@ type Claims struct {
 
 	// Scope is the scopes for the claims as a string that is space delimited.
 	Scope string `json:"scope,omitempty"`
+
+	// PreferredUsername is the standard OpenID Connect claim for the
+	// username of the user.
+	PreferredUsername string `json:"preferred_username,omitempty"`
+
+	// Email is the standard OpenID Connect claim for the user's email address.
+	Email string `json:"email,omitempty"`
 }
 
 // DomainClaims represents a custom claim data set that doesn't change the spec
@@ -72,7 +79,8 @@ func GetClaims(accessToken string) (Claims, error) {
 }
 
 // allowedSignatureAlgorithms is a list of allowed signature algorithms for JWTs.
-// We add all supported algorithms for Auth0, including with higher key lengths.
+// We add all supported algorithms for Auth0, including with higher key lengths,
+// and the ECDSA algorithms that are used by other OpenID Connect providers.
 // See auth0 docs: https://auth0.com/docs/get-started/applications/signing-algorithms
 var allowedSignatureAlgorithms = []jose.SignatureAlgorithm{
 	jose.HS256,
@@ -84,6 +92,9 @@ func GetClaims(accessToken string) (Claims, error) {
 	jose.PS256,
 	jose.PS384,
 	jose.PS512,
+	jose.ES256,
+	jose.ES384,
+	jose.ES512,
 }
 
 // parseSigned parses a JWT and returns the signature object or error. This does
@@ -6,6 +6,7 @@
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
 	"strings"
 
@@ -25,12 +26,13 @@
 // OAuthManager is the manager responsible for handling authentication
 // flows with the oauth tenant.
 type OAuthManager struct {
-	store       credentials.Store
-	tenant      string
-	audience    string
-	clientID    string
-	api         api.OAuthAPI
-	openBrowser func(string) error
+	store         credentials.Store
+	serverAddress string
+	tenant        string
+	audience      string
+	clientID      string
+	api           api.OAuthAPI
+	openBrowser   func(string) error
 }
 
 // OAuthManagerOptions are the options used for New to create a new auth manager.
@@ -42,6 +44,17 @@ type OAuthManagerOptions struct {
 	Tenant      string
 	DeviceName  string
 	OpenBrowser func(string) error
+
+	// ServerAddress is the address of the registry to log in to. It
+	// defaults to Docker Hub.
+	ServerAddress string
+	// Metadata contains the endpoints of the authorization server for
+	// registries other than Docker Hub. The Auth0 endpoints of Tenant
+	// are used for endpoints that are not set.
+	Metadata api.ServerMetadata
+	// HTTPClient is the client that is used for requests to the
+	// authorization server. It defaults to [http.DefaultClient].
+	HTTPClient *http.Client
 }
 
 func New(options OAuthManagerOptions) *OAuthManager {
@@ -59,15 +72,23 @@ func New(options OAuthManagerOptions) *OAuthManager {
 		openBrowser = browser.OpenURL
 	}
 
+	var tenantURL string
+	if options.Tenant != "" {
+		tenantURL = "https://" + options.Tenant
+	}
+
 	return &OAuthManager{
-		clientID: options.ClientID,
-		audience: options.Audience,
-		tenant:   options.Tenant,
-		store:    options.Store,
+		clientID:      options.ClientID,
+		audience:      options.Audience,
+		tenant:        options.Tenant,
+		store:         options.Store,
+		serverAddress: options.ServerAddress,
 		api: api.API{
-			TenantURL: "https://" + options.Tenant,
-			ClientID:  options.ClientID,
-			Scopes:    scopes,
+			TenantURL:  tenantURL,
+			Metadata:   options.Metadata,
+			ClientID:   options.ClientID,
+			Scopes:     scopes,
+			HTTPClient: options.HTTPClient,
 		},
 		openBrowser: openBrowser,
 	}
@@ -82,6 +103,10 @@ func New(options OAuthManagerOptions) *OAuthManager {
 // tokens to create a Hub PAT which is returned to the caller.
 // The retrieved tokens are stored in the credentials store (under a separate
 // key), and the refresh token is concatenated with the client ID.
+//
+// For registries other than Docker Hub, the access token is returned as the
+// password, and only the refresh token is stored under a separate key, so
+// that the access token can be refreshed when it expires.
 func (m *OAuthManager) LoginDevice(ctx context.Context, w io.Writer) (*types.AuthConfig, error) {
 	state, err := m.api.GetDeviceCode(ctx, m.audience)
 	if err != nil {
@@ -134,6 +159,10 @@ func (m *OAuthManager) LoginDevice(ctx context.Context, w io.Writer) (*types.Aut
 	case tokenRes = <-tokenResChan:
 	}
 
+	if !m.isDockerHub() {
+		return m.registryAuthConfig(tokenRes)
+	}
+
 	claims, err := oauth.GetClaims(tokenRes.AccessToken)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse token claims: %w", err)
@@ -162,15 +191,15 @@ func (m *OAuthManager) LoginDevice(ctx context.Context, w io.Writer) (*types.Aut
 // If the refresh token is not found in the store, an error is not
 // returned.
 func (m *OAuthManager) Logout(ctx context.Context) error {
-	refreshConfig, err := m.store.Get(refreshTokenKey)
+	refreshConfig, err := m.store.Get(m.refreshTokenKey())
 	if err != nil {
 		return err
 	}
 	if refreshConfig.Password == "" {
 		return nil
 	}
-	parts := strings.Split(refreshConfig.Password, "..")
-	if len(parts) != 2 {
+	refreshToken, _, ok := oauth.SplitRefreshToken(refreshConfig.Password)
+	if !ok {
 		// the token wasn't stored by the CLI, so don't revoke it
 		// or erase it from the store/error
 		return nil
@@ -180,7 +209,7 @@ func (m *OAuthManager) Logout(ctx context.Context) error {
 	if err := m.eraseTokensFromStore(); err != nil {
 		return fmt.Errorf("failed to erase tokens: %w", err)
 	}
-	if err := m.api.RevokeToken(ctx, parts[0]); err != nil {
+	if err := m.api.RevokeToken(ctx, refreshToken); err != nil {
 		return fmt.Errorf("credentials erased successfully, but there was a failure to revoke the OAuth refresh token with the tenant: %w", err)
 	}
 	return nil
@@ -191,6 +220,71 @@ func (m *OAuthManager) Logout(ctx context.Context) error {
 	refreshTokenKey = registry.IndexServer + "refresh-token"
 )
 
+// isDockerHub returns whether the manager logs in to Docker Hub.
+func (m *OAuthManager) isDockerHub() bool {
+	return m.serverAddress == "" || m.serverAddress == registry.IndexServer
+}
+
+func (m *OAuthManager) refreshTokenKey() string {
+	if m.isDockerHub() {
+		return refreshTokenKey
+	}
+	return oauth.RefreshTokenKey(m.serverAddress)
+}
+
+// registryAuthConfig returns the credentials for a registry other than Docker
+// Hub, using the access token as password, and stores the refresh token.
+func (m *OAuthManager) registryAuthConfig(tokenRes api.TokenResponse) (*types.AuthConfig, error) {
+	username, err := usernameFromTokens(tokenRes)
+	if err != nil {
+		// The registry may issue opaque (non-JWT) tokens, and no ID token,
+		// so fall back to the username of the stored credentials.
+		stored, getErr := m.store.Get(m.serverAddress)
+		if getErr != nil || stored.Username == "" {
+			return nil, fmt.Errorf("failed to parse token claims: %w", err)
+		}
+		username = stored.Username
+	}
+	if tokenRes.RefreshToken != "" {
+		err := m.store.Store(types.AuthConfig{
+			Username:      username,
+			Password:      oauth.JoinRefreshToken(tokenRes.RefreshToken, m.clientID),
+			ServerAddress: m.refreshTokenKey(),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to store tokens: %w", err)
+		}
+	}
+	return &types.AuthConfig{
+		Username:      username,
+		Password:      tokenRes.AccessToken,
+		ServerAddress: m.serverAddress,
+	}, nil
+}
+
+// usernameFromTokens returns the username from the standard claims of the ID
+// token, or of the access token if there's no ID token.
+func usernameFromTokens(tokenRes api.TokenResponse) (string, error) {
+	token := tokenRes.IDToken
+	if token == "" {
+		token = tokenRes.AccessToken
+	}
+	claims, err := oauth.GetClaims(token)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case claims.PreferredUsername != "":
+		return claims.PreferredUsername, nil
+	case claims.Email != "":
+		return claims.Email, nil
+	case claims.Subject != "":
+		return claims.Subject, nil
+	default:
+		return "", errors.New("token does not contain a username")
+	}
+}
+
 func (m *OAuthManager) storeTokensInStore(tokens api.TokenResponse, username string) error {
 	return errors.Join(
 		m.store.Store(types.AuthConfig{
@@ -200,13 +294,16 @@ func (m *OAuthManager) storeTokensInStore(tokens api.TokenResponse, username str
 		}),
 		m.store.Store(types.AuthConfig{
 			Username:      username,
-			Password:      tokens.RefreshToken + ".." + m.clientID,
+			Password:      oauth.JoinRefreshToken(tokens.RefreshToken, m.clientID),
 			ServerAddress: refreshTokenKey,
 		}),
 	)
 }
 
 func (m *OAuthManager) eraseTokensFromStore() error {
+	if !m.isDockerHub() {
+		return m.store.Erase(m.refreshTokenKey())
+	}
 	return errors.Join(
 		m.store.Erase(accessTokenKey),
 		m.store.Erase(refreshTokenKey),
@@ -1,3 +1,6 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.23
+
 package manager
 
 import (
@@ -10,7 +13,10 @@
 	"github.com/docker/cli/cli/config/credentials"
 	"github.com/docker/cli/cli/config/types"
 	"github.com/docker/cli/internal/oauth/api"
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
 	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
 )
 
 const (
@@ -135,6 +141,124 @@ func TestLoginDevice(t *testing.T) {
 		assert.Equal(t, store.configs["https://index.docker.io/v1/refresh-token"].Password, "refresh-token..client-id")
 	})
 
+	t.Run("other registry", func(t *testing.T) {
+		idToken := newTestToken(t, map[string]any{
+			"sub":                "0123-456789",
+			"email":              "bork@example.com",
+			"preferred_username": "bork",
+		})
+		a := &testAPI{
+			getDeviceToken: func(audience string) (api.State, error) {
+				return api.State{
+					DeviceCode: "device-code",
+					UserCode:   "0123-4567",
+				}, nil
+			},
+			waitForDeviceToken: func(state api.State) (api.TokenResponse, error) {
+				return api.TokenResponse{
+					AccessToken:  "an-access-token",
+					IDToken:      idToken,
+					RefreshToken: "refresh-token",
+				}, nil
+			},
+			getAutoPAT: func(audience string, res api.TokenResponse) (string, error) {
+				t.Error("unexpected call to GetAutoPAT")
+				return "", nil
+			},
+		}
+		store := newStore(map[string]types.AuthConfig{})
+		manager := OAuthManager{
+			clientID:      "client-id",
+			serverAddress: "registry.example.com",
+			store:         credentials.NewFileStore(store),
+			api:           a,
+			openBrowser: func(url string) error {
+				return nil
+			},
+		}
+
+		authConfig, err := manager.LoginDevice(context.Background(), os.Stderr)
+		assert.NilError(t, err)
+
+		assert.DeepEqual(t, authConfig, &types.AuthConfig{
+			Username:      "bork",
+			Password:      "an-access-token",
+			ServerAddress: "registry.example.com",
+		})
+		assert.Equal(t, len(store.configs), 1)
+		assert.Equal(t, store.configs["registry.example.com/refresh-token"].Password, "refresh-token..client-id")
+	})
+
+	t.Run("other registry without username", func(t *testing.T) {
+		a := &testAPI{
+			getDeviceToken: func(audience string) (api.State, error) {
+				return api.State{
+					DeviceCode: "device-code",
+					UserCode:   "0123-4567",
+				}, nil
+			},
+			waitForDeviceToken: func(state api.State) (api.TokenResponse, error) {
+				return api.TokenResponse{
+					AccessToken: newTestToken(t, map[string]any{"scope": "openid"}),
+				}, nil
+			},
+		}
+		manager := OAuthManager{
+			serverAddress: "registry.example.com",
+			store:         credentials.NewFileStore(newStore(map[string]types.AuthConfig{})),
+			api:           a,
+			openBrowser: func(url string) error {
+				return nil
+			},
+		}
+
+		_, err := manager.LoginDevice(context.Background(), os.Stderr)
+		assert.ErrorContains(t, err, "token does not contain a username")
+	})
+
+	t.Run("other registry with opaque token", func(t *testing.T) {
+		a := &testAPI{
+			getDeviceToken: func(audience string) (api.State, error) {
+				return api.State{
+					DeviceCode: "device-code",
+					UserCode:   "0123-4567",
+				}, nil
+			},
+			waitForDeviceToken: func(state api.State) (api.TokenResponse, error) {
+				return api.TokenResponse{
+					AccessToken:  "an-opaque-access-token",
+					RefreshToken: "refresh-token",
+				}, nil
+			},
+		}
+		store := newStore(map[string]types.AuthConfig{
+			"registry.example.com": {
+				Username:      "bork",
+				Password:      "an-old-access-token",
+				ServerAddress: "registry.example.com",
+			},
+		})
+		manager := OAuthManager{
+			serverAddress: "registry.example.com",
+			clientID:      "client-id",
+			store:         credentials.NewFileStore(store),
+			api:           a,
+			openBrowser: func(url string) error {
+				return nil
+			},
+		}
+
+		authConfig, err := manager.LoginDevice(context.Background(), os.Stderr)
+		assert.NilError(t, err)
+
+		assert.DeepEqual(t, authConfig, &types.AuthConfig{
+			Username:      "bork",
+			Password:      "an-opaque-access-token",
+			ServerAddress: "registry.example.com",
+		})
+		assert.Equal(t, store.configs["registry.example.com/refresh-token"].Username, "bork")
+	})
+
 	t.Run("timeout", func(t *testing.T) {
 		getDeviceToken := func(audience string) (api.State, error) {
 			return api.State{
@@ -276,6 +400,36 @@ func TestLogout(t *testing.T) {
 		assert.Check(t, !triedRevoke)
 	})
 
+	t.Run("other registry", func(t *testing.T) {
+		var receivedToken string
+		a := &testAPI{
+			revokeToken: func(token string) error {
+				receivedToken = token
+				return nil
+			},
+		}
+		store := newStore(map[string]types.AuthConfig{
+			"https://index.docker.io/v1/refresh-token": {
+				Password: "a-hub-refresh-token..client-id",
+			},
+			"registry.example.com/refresh-token": {
+				Password: "a-refresh-token..client-id",
+			},
+		})
+		manager := OAuthManager{
+			serverAddress: "registry.example.com",
+			store:         credentials.NewFileStore(store),
+			api:           a,
+		}
+
+		err := manager.Logout(context.Background())
+		assert.NilError(t, err)
+
+		assert.Equal(t, receivedToken, "a-refresh-token")
+		assert.Equal(t, len(store.configs), 1)
+		assert.Check(t, is.Contains(store.configs, "https://index.docker.io/v1/refresh-token"))
+	})
+
 	t.Run("no refresh token", func(t *testing.T) {
 		a := &testAPI{}
 		var triedRevoke bool
@@ -342,6 +496,16 @@ func (t *testAPI) GetAutoPAT(_ context.Context, audience string, res api.TokenRe
 	return "", nil
 }
 
+// newTestToken returns an (insecure) signed JWT with the given claims.
+func newTestToken(t *testing.T, claims map[string]any) string {
+	t.Helper()
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.HS256, Key: []byte("not-a-secret-key-for-testing-only")}, nil)
+	assert.NilError(t, err)
+	token, err := jwt.Signed(signer).Claims(claims).Serialize()
+	assert.NilError(t, err)
+	return token
+}
+
 type fakeStore struct {
 	configs map[string]types.AuthConfig
 }
@@ -0,0 +1,32 @@
+package oauth
+
+import "strings"
+
+// refreshTokenSeparator separates the refresh token from the client ID it was
+// issued to when it's stored in the credentials store.
+const refreshTokenSeparator = ".."
+
+// RefreshTokenKey returns the key under which the refresh token for a registry
+// other than Docker Hub is stored in the credentials store.
+func RefreshTokenKey(serverAddress string) string {
+	return serverAddress + "/refresh-token"
+}
+
+// JoinRefreshToken concatenates a refresh token with the client ID it was
+// issued to, for storing it in the credentials store.
+func JoinRefreshToken(refreshToken, clientID string) string {
+	return refreshToken + refreshTokenSeparator + clientID
+}
+
+// SplitRefreshToken splits a value that was stored with [JoinRefreshToken]
+// into the refresh token and client ID. It returns false if the value wasn't
+// stored by the CLI. The value is split at the last separator, as refresh
+// tokens may contain the separator themselves; for example, an encrypted JWT
+// (JWE) with an empty encrypted key.
+func SplitRefreshToken(v string) (refreshToken, clientID string, ok bool) {
+	i := strings.LastIndex(v, refreshTokenSeparator)
+	if i <= 0 || i+len(refreshTokenSeparator) == len(v) {
+		return "", "", false
+	}
+	return v[:i], v[i+len(refreshTokenSeparator):], true
+}
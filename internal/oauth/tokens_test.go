@@ -0,0 +1,53 @@
+package oauth
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestSplitRefreshToken(t *testing.T) {
+	tests := []struct {
+		doc          string
+		value        string
+		refreshToken string
+		clientID     string
+		ok           bool
+	}{
+		{
+			doc:          "opaque token",
+			value:        JoinRefreshToken("v1.refresh-token", "docker-cli"),
+			refreshToken: "v1.refresh-token",
+			clientID:     "docker-cli",
+			ok:           true,
+		},
+		{
+			doc:          "JWE with an empty encrypted key",
+			value:        JoinRefreshToken("eyJhbGciOiJkaXIiLCJlbmMiOiJBMjU2R0NNIn0..iv.ciphertext.tag", "docker-cli"),
+			refreshToken: "eyJhbGciOiJkaXIiLCJlbmMiOiJBMjU2R0NNIn0..iv.ciphertext.tag",
+			clientID:     "docker-cli",
+			ok:           true,
+		},
+		{
+			doc:   "no client ID",
+			value: "refresh-token",
+		},
+		{
+			doc:   "empty client ID",
+			value: "refresh-token..",
+		},
+		{
+			doc:   "empty refresh token",
+			value: "..docker-cli",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.doc, func(t *testing.T) {
+			refreshToken, clientID, ok := SplitRefreshToken(tc.value)
+			assert.Check(t, is.Equal(ok, tc.ok))
+			assert.Check(t, is.Equal(refreshToken, tc.refreshToken))
+			assert.Check(t, is.Equal(clientID, tc.clientID))
+		})
+	}
+}
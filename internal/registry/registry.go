@@ -7,6 +7,7 @@
 	"fmt"
 	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -133,6 +134,22 @@ func Headers(userAgent string, metaHeaders http.Header) []transport.RequestModif
 	return modifiers
 }
 
+// NewHTTPClient returns an HTTP client for requests to the server at rawURL
+// that are not made to a registry, such as requests to an OAuth authorization
+// server. It uses the proxy that is configured in the environment, and the
+// certificates for the host of rawURL in [CertsDir].
+func NewHTTPClient(ctx context.Context, rawURL string) (*http.Client, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, invalidParam(err)
+	}
+	tlsConfig, err := newTLSConfig(ctx, u.Host, true)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{Transport: newTransport(tlsConfig)}, nil
+}
+
 // newTransport returns a new HTTP transport. If tlsConfig is nil, it uses the
 // default TLS configuration.
 func newTransport(tlsConfig *tls.Config) http.RoundTripper {
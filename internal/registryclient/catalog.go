@@ -0,0 +1,63 @@
+package registryclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/distribution/reference"
+	distributionclient "github.com/docker/distribution/registry/client"
+	"github.com/docker/distribution/registry/client/auth"
+)
+
+// catalogPageSize is the number of repositories that is requested for each
+// page of the catalog.
+const catalogPageSize = 100
+
+// GetCatalog returns the names of the repositories in the registry, using the
+// catalog API. Registries may only return the repositories that the user has
+// access to, or not support the catalog API at all.
+func (c *client) GetCatalog(ctx context.Context, domain string) ([]string, error) {
+	// The repository is only used to resolve the endpoint of the registry.
+	ref, err := reference.ParseNormalizedNamed(domain + "/catalog")
+	if err != nil {
+		return nil, fmt.Errorf("invalid registry %s: %w", domain, err)
+	}
+	repoEndpoint, err := newDefaultRepositoryEndpoint(ref, c.insecureRegistry)
+	if err != nil {
+		return nil, err
+	}
+	httpTransport, err := getHTTPTransport(
+		c.authConfigResolver(ctx, repoEndpoint.indexInfo),
+		repoEndpoint.endpoint,
+		auth.RegistryScope{Name: "catalog", Actions: []string{"*"}},
+		c.userAgent,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure transport: %w", err)
+	}
+	registry, err := distributionclient.NewRegistry(repoEndpoint.BaseURL(), httpTransport)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		repositories []string
+		last         string
+	)
+	for {
+		entries := make([]string, catalogPageSize)
+		n, err := registry.Repositories(ctx, entries, last)
+		repositories = append(repositories, entries[:n]...)
+		switch {
+		case errors.Is(err, io.EOF):
+			return repositories, nil
+		case err != nil:
+			return nil, fmt.Errorf("failed to list repositories of %s: %w", domain, err)
+		case n == 0:
+			return repositories, nil
+		}
+		last = entries[n-1]
+	}
+}
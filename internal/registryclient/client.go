@@ -2,7 +2,9 @@
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
 
@@ -10,8 +12,10 @@
 	manifesttypes "github.com/docker/cli/cli/manifest/types"
 	"github.com/docker/distribution"
 	distributionclient "github.com/docker/distribution/registry/client"
+	"github.com/docker/distribution/registry/client/auth"
 	registrytypes "github.com/moby/moby/api/types/registry"
 	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/sirupsen/logrus"
 )
 
@@ -20,8 +24,17 @@
 type RegistryClient interface {
 	GetManifest(ctx context.Context, ref reference.Named) (manifesttypes.ImageManifest, error)
 	GetManifestList(ctx context.Context, ref reference.Named) ([]manifesttypes.ImageManifest, error)
+	GetBlob(ctx context.Context, ref reference.Named, dgst digest.Digest) ([]byte, error)
+	OpenBlob(ctx context.Context, ref reference.Named, dgst digest.Digest) (io.ReadCloser, error)
 	MountBlob(ctx context.Context, source reference.Canonical, target reference.Named) error
 	PutManifest(ctx context.Context, ref reference.Named, manifest distribution.Manifest) (digest.Digest, error)
+	GetDescriptor(ctx context.Context, ref reference.Named) (ocispec.Descriptor, error)
+	GetRawManifest(ctx context.Context, ref reference.Named) (ocispec.Descriptor, []byte, error)
+	PushBlob(ctx context.Context, ref reference.Named, desc ocispec.Descriptor, content io.Reader) error
+	PutBlob(ctx context.Context, ref reference.Named, mediaType string, content []byte) (ocispec.Descriptor, error)
+	GetReferrers(ctx context.Context, subject reference.Canonical, artifactType string) ([]ocispec.Descriptor, error)
+	PutReferrer(ctx context.Context, ref reference.Named, manifest ocispec.Manifest) (ocispec.Descriptor, error)
+	GetCatalog(ctx context.Context, domain string) ([]string, error)
 }
 
 // NewRegistryClient returns a new RegistryClient with a resolver
@@ -145,12 +158,15 @@ func (c *client) getRepositoryForReference(ctx context.Context, ref reference.Na
 }
 
 func (c *client) getHTTPTransportForRepoEndpoint(ctx context.Context, repoEndpoint repositoryEndpoint) (http.RoundTripper, error) {
+	actions := repoEndpoint.actions
+	if len(actions) == 0 {
+		actions = []string{"pull"}
+	}
 	httpTransport, err := getHTTPTransport(
 		c.authConfigResolver(ctx, repoEndpoint.indexInfo),
 		repoEndpoint.endpoint,
-		repoEndpoint.repoName,
+		auth.RepositoryScope{Repository: repoEndpoint.repoName, Actions: actions},
 		c.userAgent,
-		repoEndpoint.actions,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to configure transport: %w", err)
@@ -184,6 +200,112 @@ func (c *client) GetManifestList(ctx context.Context, ref reference.Named) ([]ma
 	return result, err
 }
 
+// GetBlob returns the content of the blob with the given digest in the
+// repository of the reference, such as an image config.
+func (c *client) GetBlob(ctx context.Context, ref reference.Named, dgst digest.Digest) ([]byte, error) {
+	var result []byte
+	fetch := func(ctx context.Context, repo distribution.Repository, ref reference.Named) (bool, error) {
+		var err error
+		result, err = pullBlob(ctx, dgst, repo)
+		return result != nil, err
+	}
+
+	err := c.iterateEndpoints(ctx, ref, fetch)
+	return result, err
+}
+
+// OpenBlob returns a reader for the content of the blob with the given
+// digest in the repository of the reference, for blobs that are too large
+// to read into memory, such as layers. The reader returns an error on EOF if
+// the content does not match the digest.
+func (c *client) OpenBlob(ctx context.Context, ref reference.Named, dgst digest.Digest) (io.ReadCloser, error) {
+	var result io.ReadCloser
+	fetch := func(ctx context.Context, repo distribution.Repository, ref reference.Named) (bool, error) {
+		rc, err := repo.Blobs(ctx).Open(ctx, dgst)
+		if err != nil {
+			return false, err
+		}
+		result = &verifyingReader{ReadCloser: rc, dgst: dgst, verifier: dgst.Verifier()}
+		return true, nil
+	}
+
+	err := c.iterateEndpoints(ctx, ref, fetch)
+	return result, err
+}
+
+// GetRawManifest returns the descriptor and the content of the manifest, or
+// manifest list, that the reference points to, as stored in the registry.
+func (c *client) GetRawManifest(ctx context.Context, ref reference.Named) (ocispec.Descriptor, []byte, error) {
+	var (
+		desc ocispec.Descriptor
+		raw  []byte
+	)
+	fetch := func(ctx context.Context, repo distribution.Repository, ref reference.Named) (bool, error) {
+		mfst, err := getManifest(ctx, repo, ref)
+		if err != nil {
+			return false, err
+		}
+		desc, err = validateManifestDigest(ref, mfst)
+		if err != nil {
+			return false, err
+		}
+		_, raw, err = mfst.Payload()
+		return err == nil, err
+	}
+
+	err := c.iterateEndpoints(ctx, ref, fetch)
+	return desc, raw, err
+}
+
+// PushBlob uploads the content of a blob to the repository of the reference,
+// unless the repository already contains the blob. The content is not read
+// if the blob exists.
+func (c *client) PushBlob(ctx context.Context, ref reference.Named, desc ocispec.Descriptor, content io.Reader) error {
+	repoEndpoint, err := newDefaultRepositoryEndpoint(ref, c.insecureRegistry)
+	if err != nil {
+		return err
+	}
+	repoEndpoint.actions = []string{"pull", "push"}
+	repo, err := c.getRepositoryForReference(ctx, ref, repoEndpoint)
+	if err != nil {
+		return err
+	}
+	blobs := repo.Blobs(ctx)
+	if _, err := blobs.Stat(ctx, desc.Digest); err == nil {
+		logrus.Debugf("blob %s exists in %s", desc.Digest, ref)
+		return nil
+	}
+
+	w, err := blobs.Create(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to push blob %s to %s: %w", desc.Digest, ref, err)
+	}
+	if _, err := w.ReadFrom(content); err != nil {
+		_ = w.Cancel(ctx)
+		return fmt.Errorf("failed to push blob %s to %s: %w", desc.Digest, ref, err)
+	}
+	if _, err := w.Commit(ctx, distribution.Descriptor{MediaType: desc.MediaType, Digest: desc.Digest, Size: desc.Size}); err != nil {
+		return fmt.Errorf("failed to push blob %s to %s: %w", desc.Digest, ref, err)
+	}
+	return nil
+}
+
+// verifyingReader verifies the digest of the content it reads.
+type verifyingReader struct {
+	io.ReadCloser
+	dgst     digest.Digest
+	verifier digest.Verifier
+}
+
+func (r *verifyingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	_, _ = r.verifier.Write(p[:n])
+	if errors.Is(err, io.EOF) && !r.verifier.Verified() {
+		return n, fmt.Errorf("blob verification failed for digest %s", r.dgst)
+	}
+	return n, err
+}
+
 func getManifestOptionsFromReference(ref reference.Named) (digest.Digest, []distribution.ManifestServiceOption, error) {
 	if tagged, isTagged := ref.(reference.NamedTagged); isTagged {
 		tag := tagged.Tag()
@@ -73,7 +73,7 @@ func pullManifestSchemaV2(ctx context.Context, ref reference.Named, repo distrib
 	if err != nil {
 		return types.ImageManifest{}, err
 	}
-	configJSON, err := pullManifestSchemaV2ImageConfig(ctx, mfst.Target().Digest, repo)
+	configJSON, err := pullBlob(ctx, mfst.Target().Digest, repo)
 	if err != nil {
 		return types.ImageManifest{}, err
 	}
@@ -95,7 +95,7 @@ func pullManifestOCISchema(ctx context.Context, ref reference.Named, repo distri
 	if err != nil {
 		return types.ImageManifest{}, err
 	}
-	configJSON, err := pullManifestSchemaV2ImageConfig(ctx, mfst.Target().Digest, repo)
+	configJSON, err := pullBlob(ctx, mfst.Target().Digest, repo)
 	if err != nil {
 		return types.ImageManifest{}, err
 	}
@@ -112,21 +112,22 @@ func pullManifestOCISchema(ctx context.Context, ref reference.Named, repo distri
 	return types.NewOCIImageManifest(ref, manifestDesc, &mfst), nil
 }
 
-func pullManifestSchemaV2ImageConfig(ctx context.Context, dgst digest.Digest, repo distribution.Repository) ([]byte, error) {
+// pullBlob fetches a blob, such as an image config, and verifies its digest.
+func pullBlob(ctx context.Context, dgst digest.Digest, repo distribution.Repository) ([]byte, error) {
 	blobs := repo.Blobs(ctx)
-	configJSON, err := blobs.Get(ctx, dgst)
+	content, err := blobs.Get(ctx, dgst)
 	if err != nil {
 		return nil, err
 	}
 
 	verifier := dgst.Verifier()
-	if _, err := verifier.Write(configJSON); err != nil {
+	if _, err := verifier.Write(content); err != nil {
 		return nil, err
 	}
 	if !verifier.Verified() {
-		return nil, fmt.Errorf("image config verification failed for digest %s", dgst)
+		return nil, fmt.Errorf("blob verification failed for digest %s", dgst)
 	}
-	return configJSON, nil
+	return content, nil
 }
 
 // validateManifestDigest computes the manifest digest, and, if pulling by
@@ -189,6 +190,7 @@ func pullManifestList(ctx context.Context, ref reference.Named, repo distributio
 		// Replace platform from config
 		p := manifestDescriptor.Platform
 		imageManifest.Descriptor.Platform = types.OCIPlatform(&p)
+		imageManifest.Descriptor.Annotations = manifestDescriptor.Annotations
 
 		infos = append(infos, imageManifest)
 	}
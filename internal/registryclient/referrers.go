@@ -0,0 +1,262 @@
+package registryclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/distribution/reference"
+	"github.com/docker/distribution"
+	distributionclient "github.com/docker/distribution/registry/client"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// maxIndexSize is the maximum size of an image index that is read from a
+// registry.
+const maxIndexSize = 4 << 20
+
+// GetDescriptor returns the descriptor of the manifest, or manifest list,
+// that the reference points to.
+func (c *client) GetDescriptor(ctx context.Context, ref reference.Named) (ocispec.Descriptor, error) {
+	var result ocispec.Descriptor
+	fetch := func(ctx context.Context, repo distribution.Repository, ref reference.Named) (bool, error) {
+		mfst, err := getManifest(ctx, repo, ref)
+		if err != nil {
+			return false, err
+		}
+		result, err = validateManifestDigest(ref, mfst)
+		return err == nil, err
+	}
+
+	err := c.iterateEndpoints(ctx, ref, fetch)
+	return result, err
+}
+
+// PutBlob uploads a blob to the repository of the reference.
+func (c *client) PutBlob(ctx context.Context, ref reference.Named, mediaType string, content []byte) (ocispec.Descriptor, error) {
+	repoEndpoint, err := newDefaultRepositoryEndpoint(ref, c.insecureRegistry)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	repoEndpoint.actions = []string{"pull", "push"}
+	repo, err := c.getRepositoryForReference(ctx, ref, repoEndpoint)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	desc, err := repo.Blobs(ctx).Put(ctx, mediaType, content)
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("failed to push blob to %s: %w", ref, err)
+	}
+	return ocispec.Descriptor{MediaType: mediaType, Digest: desc.Digest, Size: desc.Size}, nil
+}
+
+// GetReferrers returns the descriptors of the manifests that refer to the
+// subject through their "subject" field, optionally filtered by artifact
+// type. It uses the referrers API of OCI distribution-spec v1.1, and falls
+// back to the referrers tag schema for registries that do not support it.
+func (c *client) GetReferrers(ctx context.Context, subject reference.Canonical, artifactType string) ([]ocispec.Descriptor, error) {
+	httpClient, repoURL, err := c.getHTTPClientForRepo(ctx, subject, "pull")
+	if err != nil {
+		return nil, err
+	}
+
+	u := repoURL + "/referrers/" + subject.Digest().String()
+	if artifactType != "" {
+		u += "?" + url.Values{"artifactType": {artifactType}}.Encode()
+	}
+	var referrers []ocispec.Descriptor
+	for u != "" {
+		index, resp, err := getIndex(ctx, httpClient, u)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode == http.StatusNotFound {
+			// The registry does not support the referrers API.
+			return c.getReferrersFromTag(ctx, httpClient, repoURL, subject.Digest(), artifactType)
+		}
+		if artifactType != "" && resp.Header.Get("OCI-Filters-Applied") != "artifactType" {
+			index.Manifests = filterArtifactType(index.Manifests, artifactType)
+		}
+		referrers = append(referrers, index.Manifests...)
+		u, err = nextLink(resp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return referrers, nil
+}
+
+func (*client) getReferrersFromTag(ctx context.Context, httpClient *http.Client, repoURL string, subject digest.Digest, artifactType string) ([]ocispec.Descriptor, error) {
+	index, resp, err := getIndex(ctx, httpClient, repoURL+"/manifests/"+referrersTag(subject))
+	if err != nil || resp.StatusCode == http.StatusNotFound {
+		return nil, err
+	}
+	return filterArtifactType(index.Manifests, artifactType), nil
+}
+
+// PutReferrer pushes a manifest that refers to another manifest through its
+// "subject" field to the repository of the reference, and returns its
+// descriptor. The blobs of the manifest must already be present in the
+// repository. If the registry does not support the referrers API, the
+// manifest is added to the index tagged with the referrers tag schema.
+func (c *client) PutReferrer(ctx context.Context, ref reference.Named, manifest ocispec.Manifest) (ocispec.Descriptor, error) {
+	if manifest.Subject == nil {
+		return ocispec.Descriptor{}, errors.New("manifest has no subject")
+	}
+	dt, err := json.Marshal(manifest)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	desc := ocispec.Descriptor{
+		MediaType:    ocispec.MediaTypeImageManifest,
+		Digest:       digest.FromBytes(dt),
+		Size:         int64(len(dt)),
+		ArtifactType: manifest.ArtifactType,
+		Annotations:  manifest.Annotations,
+	}
+	if desc.ArtifactType == "" {
+		desc.ArtifactType = manifest.Config.MediaType
+	}
+
+	httpClient, repoURL, err := c.getHTTPClientForRepo(ctx, ref, "pull", "push")
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	resp, err := putManifest(ctx, httpClient, repoURL+"/manifests/"+desc.Digest.String(), desc.MediaType, dt)
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("failed to put manifest %s: %w", ref, err)
+	}
+	if resp.Header.Get("OCI-Subject") != "" {
+		// The registry supports the referrers API, and indexed the manifest.
+		return desc, nil
+	}
+
+	tagURL := repoURL + "/manifests/" + referrersTag(manifest.Subject.Digest)
+	index, resp, err := getIndex(ctx, httpClient, tagURL)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		index = ocispec.Index{MediaType: ocispec.MediaTypeImageIndex}
+		index.SchemaVersion = 2
+	}
+	for _, d := range index.Manifests {
+		if d.Digest == desc.Digest {
+			return desc, nil
+		}
+	}
+	index.Manifests = append(index.Manifests, desc)
+	dt, err = json.Marshal(index)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	if _, err := putManifest(ctx, httpClient, tagURL, ocispec.MediaTypeImageIndex, dt); err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("failed to update referrers of %s: %w", manifest.Subject.Digest, err)
+	}
+	return desc, nil
+}
+
+// getHTTPClientForRepo returns a client for requests to the registry API
+// that are not supported by the distribution client, and the URL of the
+// repository of the reference.
+func (c *client) getHTTPClientForRepo(ctx context.Context, ref reference.Named, actions ...string) (*http.Client, string, error) {
+	repoEndpoint, err := newDefaultRepositoryEndpoint(ref, c.insecureRegistry)
+	if err != nil {
+		return nil, "", err
+	}
+	repoEndpoint.actions = actions
+	httpTransport, err := c.getHTTPTransportForRepoEndpoint(ctx, repoEndpoint)
+	if err != nil {
+		return nil, "", err
+	}
+	repoURL := strings.TrimSuffix(repoEndpoint.BaseURL(), "/") + "/v2/" + repoEndpoint.repoName
+	return &http.Client{Transport: httpTransport}, repoURL, nil
+}
+
+// getIndex fetches an image index. It returns an empty index if the response
+// status is 404 (Not Found).
+func getIndex(ctx context.Context, httpClient *http.Client, u string) (ocispec.Index, *http.Response, error) {
+	var index ocispec.Index
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, http.NoBody)
+	if err != nil {
+		return index, nil, err
+	}
+	req.Header.Set("Accept", ocispec.MediaTypeImageIndex)
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return index, nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return index, resp, nil
+	case !distributionclient.SuccessStatus(resp.StatusCode):
+		return index, resp, distributionclient.HandleErrorResponse(resp)
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxIndexSize)).Decode(&index); err != nil {
+		return index, resp, fmt.Errorf("invalid image index from %s: %w", req.URL.Path, err)
+	}
+	return index, resp, nil
+}
+
+func putManifest(ctx context.Context, httpClient *http.Client, u string, mediaType string, content []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, u, bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", mediaType)
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if !distributionclient.SuccessStatus(resp.StatusCode) {
+		return resp, distributionclient.HandleErrorResponse(resp)
+	}
+	return resp, nil
+}
+
+// nextLink returns the URL of the next page of results from the "Link"
+// header of the response, or an empty string if there are no more results.
+func nextLink(resp *http.Response) (string, error) {
+	link := resp.Header.Get("Link")
+	if link == "" {
+		return "", nil
+	}
+	target, params, _ := strings.Cut(link, ";")
+	if !strings.Contains(strings.ReplaceAll(params, " ", ""), `rel="next"`) {
+		return "", nil
+	}
+	next, err := resp.Request.URL.Parse(strings.Trim(strings.TrimSpace(target), "<>"))
+	if err != nil {
+		return "", fmt.Errorf("invalid Link header %q: %w", link, err)
+	}
+	return next.String(), nil
+}
+
+// referrersTag returns the tag of the index with the referrers of a manifest,
+// for registries that do not support the referrers API.
+func referrersTag(dgst digest.Digest) string {
+	return dgst.Algorithm().String() + "-" + dgst.Encoded()
+}
+
+func filterArtifactType(descriptors []ocispec.Descriptor, artifactType string) []ocispec.Descriptor {
+	if artifactType == "" {
+		return descriptors
+	}
+	filtered := make([]ocispec.Descriptor, 0, len(descriptors))
+	for _, d := range descriptors {
+		if d.ArtifactType == artifactType {
+			filtered = append(filtered, d)
+		}
+	}
+	return filtered
+}